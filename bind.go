@@ -5,12 +5,12 @@ package echo
 
 import (
 	"encoding"
-	"encoding/xml"
 	"errors"
 	"fmt"
 	"mime/multipart"
 	"net/http"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -21,7 +21,34 @@ type Binder interface {
 }
 
 // DefaultBinder is the default implementation of the Binder interface.
-type DefaultBinder struct{}
+//
+// Query and form values can be bound to nested structs, maps and slices with bracket and dot notation, i.e.
+// `filter[status]=open`, `items[0].sku=A`, `items[0][sku]=A` or `tags[]=x`. Field names in keys are matched with
+// `query` and `form` tags of fields on every level. Keys that match field name exactly are bound as before and take
+// precedence over nested keys.
+//
+// Binding tags accept `required` option, i.e. `query:"id,required"`, that makes binding fail when value is missing or
+// empty in that source. Field with `default:"20"` tag gets the default value instead, unless it already has a value
+// bound from previous source. Field with `delim:","` tag has values split by delimiter before binding, so
+// `?ids=1,2&ids=3` binds to `[]int64{1, 2, 3}` same way as `ValueBinder.BindWithDelimiter` does.
+//
+// Options are applied only when their source is bound. `Bind` binds query params only for GET, DELETE and HEAD
+// requests, so `query` tag options of other requests are checked only when `BindQueryParams` is called. `form` tag
+// options are checked for requests with empty body too, except GET, DELETE and HEAD requests. Form options are not
+// checked when body is decoded with Codec, i.e. for JSON bodies.
+type DefaultBinder struct {
+	// MaxNestingDepth limits number of nested levels in query and form keys, i.e. `a[b][c]` has 2 levels. Binding fails
+	// when key for bound field is nested deeper. Defaults to 10.
+	MaxNestingDepth int
+	// MaxSliceIndex limits index of slice elements in query and form keys, i.e. 5 in `items[5]`. Binding fails when key
+	// for bound field has bigger index. Defaults to 1000.
+	MaxSliceIndex int
+}
+
+const (
+	defaultBindMaxNestingDepth = 10
+	defaultBindMaxSliceIndex   = 1000
+)
 
 // BindUnmarshaler is the interface used to wrap the UnmarshalParam method.
 // Types that don't implement this, but do implement encoding.TextUnmarshaler
@@ -47,7 +74,7 @@ func (b *DefaultBinder) BindPathParams(c Context, i interface{}) error {
 		params[name] = []string{values[i]}
 	}
 	if err := b.bindData(i, params, "param", nil); err != nil {
-		return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		return newBindDataError(err)
 	}
 	return nil
 }
@@ -55,12 +82,13 @@ func (b *DefaultBinder) BindPathParams(c Context, i interface{}) error {
 // BindQueryParams binds query params to bindable object
 func (b *DefaultBinder) BindQueryParams(c Context, i interface{}) error {
 	if err := b.bindData(i, c.QueryParams(), "query", nil); err != nil {
-		return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		return newBindDataError(err)
 	}
 	return nil
 }
 
-// BindBody binds request body contents to bindable object
+// BindBody binds request body contents to bindable object. Form and multipart bodies are bound with `form` struct tags,
+// all other media types are decoded with Codec registered for request `Content-Type` in `Echo.Codecs`.
 // NB: then binding forms take note that this implementation uses standard library form parsing
 // which parses form data from BOTH URL and BODY if content type is not MIMEMultipartForm
 // See non-MIMEMultipartForm: https://golang.org/pkg/net/http/#Request.ParseForm
@@ -68,39 +96,26 @@ func (b *DefaultBinder) BindQueryParams(c Context, i interface{}) error {
 func (b *DefaultBinder) BindBody(c Context, i interface{}) (err error) {
 	req := c.Request()
 	if req.ContentLength == 0 {
+		if bindsQueryParams(req.Method) {
+			return
+		}
+		// empty body has no form values, but required form fields must still fail and default values be set
+		if err = b.bindData(i, nil, "form", nil); err != nil {
+			return newBindDataError(err)
+		}
 		return
 	}
 
-	// mediatype is found like `mime.ParseMediaType()` does it
-	base, _, _ := strings.Cut(req.Header.Get(HeaderContentType), ";")
-	mediatype := strings.TrimSpace(base)
+	mediatype := normalizeMediaType(req.Header.Get(HeaderContentType))
 
 	switch mediatype {
-	case MIMEApplicationJSON:
-		if err = c.Echo().JSONSerializer.Deserialize(c, i); err != nil {
-			switch err.(type) {
-			case *HTTPError:
-				return err
-			default:
-				return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
-			}
-		}
-	case MIMEApplicationXML, MIMETextXML:
-		if err = xml.NewDecoder(req.Body).Decode(i); err != nil {
-			if ute, ok := err.(*xml.UnsupportedTypeError); ok {
-				return NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unsupported type error: type=%v, error=%v", ute.Type, ute.Error())).SetInternal(err)
-			} else if se, ok := err.(*xml.SyntaxError); ok {
-				return NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Syntax error: line=%v, error=%v", se.Line, se.Error())).SetInternal(err)
-			}
-			return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
-		}
 	case MIMEApplicationForm:
 		params, err := c.FormParams()
 		if err != nil {
 			return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
 		}
 		if err = b.bindData(i, params, "form", nil); err != nil {
-			return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+			return newBindDataError(err)
 		}
 	case MIMEMultipartForm:
 		params, err := c.MultipartForm()
@@ -108,10 +123,21 @@ func (b *DefaultBinder) BindBody(c Context, i interface{}) (err error) {
 			return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
 		}
 		if err = b.bindData(i, params.Value, "form", params.File); err != nil {
-			return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+			return newBindDataError(err)
 		}
 	default:
-		return ErrUnsupportedMediaType
+		codec, ok := c.Echo().Codecs.Lookup(mediatype)
+		if !ok {
+			return ErrUnsupportedMediaType
+		}
+		if err = codec.Decode(c, i); err != nil {
+			switch err.(type) {
+			case *HTTPError:
+				return err
+			default:
+				return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+			}
+		}
 	}
 	return nil
 }
@@ -119,7 +145,7 @@ func (b *DefaultBinder) BindBody(c Context, i interface{}) (err error) {
 // BindHeaders binds HTTP headers to a bindable object
 func (b *DefaultBinder) BindHeaders(c Context, i interface{}) error {
 	if err := b.bindData(i, c.Request().Header, "header", nil); err != nil {
-		return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		return newBindDataError(err)
 	}
 	return nil
 }
@@ -134,8 +160,7 @@ func (b *DefaultBinder) Bind(i interface{}, c Context) (err error) {
 	// Only bind query parameters for GET/DELETE/HEAD to avoid unexpected behavior with destination struct binding from body.
 	// For example a request URL `&id=1&lang=en` with body `{"id":100,"lang":"de"}` would lead to precedence issues.
 	// The HTTP method check restores pre-v4.1.11 behavior to avoid these problems (see issue #1670)
-	method := c.Request().Method
-	if method == http.MethodGet || method == http.MethodDelete || method == http.MethodHead {
+	if bindsQueryParams(c.Request().Method) {
 		if err = b.BindQueryParams(c, i); err != nil {
 			return err
 		}
@@ -143,12 +168,35 @@ func (b *DefaultBinder) Bind(i interface{}, c Context) (err error) {
 	return b.BindBody(c, i)
 }
 
+// bindsQueryParams reports whether `Bind` binds query params for request method. Requests with these methods are not
+// expected to have body.
+func bindsQueryParams(method string) bool {
+	return method == http.MethodGet || method == http.MethodDelete || method == http.MethodHead
+}
+
+// newBindDataError wraps bindData error to HTTPError with status 400. Message of BindingError is used as is, so the
+// response does not repeat its status code.
+func newBindDataError(err error) error {
+	var be *BindingError
+	if errors.As(err, &be) {
+		return NewHTTPError(http.StatusBadRequest, fmt.Sprintf("%v, field=%s", be.Message, be.Field)).SetInternal(err)
+	}
+	return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+}
+
 // bindData will bind data ONLY fields in destination struct that have EXPLICIT tag
 func (b *DefaultBinder) bindData(destination interface{}, data map[string][]string, tag string, dataFiles map[string][]*multipart.FileHeader) error {
-	if destination == nil || (len(data) == 0 && len(dataFiles) == 0) {
+	if destination == nil {
 		return nil
 	}
 	hasFiles := len(dataFiles) > 0
+	if len(data) == 0 && !hasFiles {
+		// nothing to bind, unless struct fields have default values or are required
+		t := reflect.TypeOf(destination)
+		if t.Kind() != reflect.Pointer || t.Elem().Kind() != reflect.Struct || !hasBindOptions(t.Elem(), tag) {
+			return nil
+		}
+	}
 	typ := reflect.TypeOf(destination).Elem()
 	val := reflect.ValueOf(destination).Elem()
 
@@ -204,7 +252,8 @@ func (b *DefaultBinder) bindData(destination interface{}, data map[string][]stri
 			continue
 		}
 		structFieldKind := structField.Kind()
-		inputFieldName := typeField.Tag.Get(tag)
+		inputFieldName, tagOptions, _ := strings.Cut(typeField.Tag.Get(tag), ",")
+		isRequired := hasTagOption(tagOptions, "required")
 		if typeField.Anonymous && structFieldKind == reflect.Struct && inputFieldName != "" {
 			// if anonymous struct with query/param/form tags, report an error
 			return errors.New("query/param/form tags are not allowed with anonymous struct field")
@@ -247,53 +296,314 @@ func (b *DefaultBinder) bindData(destination interface{}, data map[string][]stri
 			}
 		}
 
-		if !exists {
-			continue
+		if !exists && (tag == "query" || tag == "form") {
+			entries, err := b.nestedEntries(data, inputFieldName)
+			if err != nil {
+				return err
+			}
+			if len(entries) > 0 {
+				if err := b.bindNested(structField, nil, entries, tag); err != nil {
+					return err
+				}
+				continue
+			}
 		}
 
-		// NOTE: algorithm here is not particularly sophisticated. It probably does not work with absurd types like `**[]*int`
-		// but it is smart enough to handle niche cases like `*int`,`*[]string`,`[]*int` .
+		if !exists || len(inputValue) == 0 || inputValue[0] == "" {
+			if defaultValue, ok := typeField.Tag.Lookup("default"); ok {
+				if !structField.IsZero() {
+					continue // do not override value bound from previous source
+				}
+				inputValue = []string{defaultValue}
+			} else if isRequired {
+				return NewBindingError(inputFieldName, inputValue, "required field value is empty", nil)
+			} else if !exists {
+				continue
+			}
+		}
+		if delimiter := typeField.Tag.Get("delim"); delimiter != "" {
+			inputValue = splitBindValues(inputValue, delimiter)
+		}
 
-		// try unmarshalling first, in case we're dealing with an alias to an array type
-		if ok, err := unmarshalInputsToField(typeField.Type.Kind(), inputValue, structField); ok {
-			if err != nil {
-				return err
+		if err := bindValues(typeField.Type.Kind(), structField, inputValue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasBindOptions checks if struct type has fields with `default` tag or `required` option for given tag, including
+// fields of untagged nested structs that bindData descends into.
+func hasBindOptions(typ reflect.Type, tag string) bool {
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		name, options, _ := strings.Cut(f.Tag.Get(tag), ",")
+		if name == "" {
+			if f.Type.Kind() == reflect.Struct && hasBindOptions(f.Type, tag) {
+				return true
 			}
 			continue
 		}
+		if _, ok := f.Tag.Lookup("default"); ok || hasTagOption(options, "required") {
+			return true
+		}
+	}
+	return false
+}
 
-		if ok, err := unmarshalInputToField(typeField.Type.Kind(), inputValue[0], structField); ok {
-			if err != nil {
+// hasTagOption checks if comma separated struct tag options contain given option.
+func hasTagOption(options string, option string) bool {
+	for options != "" {
+		var o string
+		o, options, _ = strings.Cut(options, ",")
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// splitBindValues splits every value with delimiter, i.e. `1,2` and `3` become `1`, `2` and `3`.
+func splitBindValues(values []string, delimiter string) []string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		result = append(result, strings.Split(v, delimiter)...)
+	}
+	return result
+}
+
+// bindValues binds values to field of given kind.
+func bindValues(kind reflect.Kind, field reflect.Value, values []string) error {
+	// NOTE: algorithm here is not particularly sophisticated. It probably does not work with absurd types like `**[]*int`
+	// but it is smart enough to handle niche cases like `*int`,`*[]string`,`[]*int` .
+
+	// try unmarshalling first, in case we're dealing with an alias to an array type
+	if ok, err := unmarshalInputsToField(kind, values, field); ok {
+		return err
+	}
+
+	if ok, err := unmarshalInputToField(kind, values[0], field); ok {
+		return err
+	}
+
+	// we could be dealing with pointer to slice `*[]string` so dereference it. There are weird OpenAPI generators
+	// that could create struct fields like that.
+	if kind == reflect.Pointer {
+		kind = field.Elem().Kind()
+		field = field.Elem()
+	}
+
+	if kind == reflect.Slice {
+		sliceOf := field.Type().Elem().Kind()
+		numElems := len(values)
+		slice := reflect.MakeSlice(field.Type(), numElems, numElems)
+		for j := 0; j < numElems; j++ {
+			if err := setWithProperType(sliceOf, values[j], slice.Index(j)); err != nil {
 				return err
 			}
+		}
+		field.Set(slice)
+		return nil
+	}
+
+	return setWithProperType(kind, values[0], field)
+}
+
+// bindEntry is query or form value with nested key split to path segments, i.e. `items[0].sku` has path `0`, `sku`
+// relative to `items` field.
+type bindEntry struct {
+	path   []string
+	values []string
+}
+
+// nestedEntries returns values of nested keys for field name, i.e. `filter[status]` and `filter.owner` for `filter`.
+func (b *DefaultBinder) nestedEntries(data map[string][]string, name string) ([]bindEntry, error) {
+	maxDepth := b.MaxNestingDepth
+	if maxDepth <= 0 {
+		maxDepth = defaultBindMaxNestingDepth
+	}
+	var entries []bindEntry
+	for key, values := range data {
+		segments := splitBindKey(key)
+		if len(segments) < 2 || !strings.EqualFold(segments[0], name) {
 			continue
 		}
+		if len(segments)-1 > maxDepth {
+			return nil, fmt.Errorf("binding key %q exceeds maximum nesting depth %d", key, maxDepth)
+		}
+		entries = append(entries, bindEntry{path: segments[1:], values: values})
+	}
+	return entries, nil
+}
 
-		// we could be dealing with pointer to slice `*[]string` so dereference it. There are weird OpenAPI generators
-		// that could create struct fields like that.
-		if structFieldKind == reflect.Pointer {
-			structFieldKind = structField.Elem().Kind()
-			structField = structField.Elem()
+// bindNested binds values and nested entries to struct, map or slice field. Values are bound to the field itself
+// and entries to its fields or elements.
+func (b *DefaultBinder) bindNested(field reflect.Value, values []string, entries []bindEntry, tag string) error {
+	if len(entries) == 0 {
+		if len(values) == 0 {
+			return nil
 		}
+		if field.Kind() == reflect.Interface {
+			field.Set(reflect.ValueOf(values[0])) // same as binding to map[string]interface{}
+			return nil
+		}
+		return bindValues(field.Kind(), field, values)
+	}
+	if field.Kind() == reflect.Pointer {
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+		field = field.Elem()
+	}
 
-		if structFieldKind == reflect.Slice {
-			sliceOf := structField.Type().Elem().Kind()
-			numElems := len(inputValue)
-			slice := reflect.MakeSlice(structField.Type(), numElems, numElems)
-			for j := 0; j < numElems; j++ {
-				if err := setWithProperType(sliceOf, inputValue[j], slice.Index(j)); err != nil {
+	switch field.Kind() {
+	case reflect.Struct:
+		data := make(map[string][]string, len(entries))
+		for _, e := range entries {
+			key := joinBindKey(e.path)
+			data[key] = append(data[key], e.values...)
+		}
+		return b.bindData(field.Addr().Interface(), data, tag, nil)
+	case reflect.Map:
+		if field.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+		if field.IsNil() {
+			field.Set(reflect.MakeMap(field.Type()))
+		}
+		keys, grouped := groupBindEntries(entries)
+		for _, key := range keys {
+			k := reflect.ValueOf(key).Convert(field.Type().Key())
+			elem := reflect.New(field.Type().Elem()).Elem()
+			if existing := field.MapIndex(k); existing.IsValid() {
+				elem.Set(existing)
+			}
+			if err := b.bindNested(elem, grouped[key].values, grouped[key].entries, tag); err != nil {
+				return err
+			}
+			field.SetMapIndex(k, elem)
+		}
+	case reflect.Slice:
+		maxIndex := b.MaxSliceIndex
+		if maxIndex <= 0 {
+			maxIndex = defaultBindMaxSliceIndex
+		}
+		keys, grouped := groupBindEntries(entries)
+		indexes := make([]int, 0, len(keys))
+		for _, key := range keys {
+			if key == "" {
+				continue
+			}
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 {
+				return fmt.Errorf("invalid slice index %q", key)
+			}
+			if index > maxIndex {
+				return fmt.Errorf("slice index %d exceeds maximum index %d", index, maxIndex)
+			}
+			indexes = append(indexes, index)
+		}
+		sort.Ints(indexes)
+		if n := len(indexes); n > 0 && indexes[n-1] >= field.Len() {
+			grown := reflect.MakeSlice(field.Type(), indexes[n-1]+1, indexes[n-1]+1)
+			reflect.Copy(grown, field)
+			field.Set(grown)
+		}
+		for _, index := range indexes {
+			g := grouped[strconv.Itoa(index)]
+			if err := b.bindNested(field.Index(index), g.values, g.entries, tag); err != nil {
+				return err
+			}
+		}
+		// `tags[]=a&tags[]=b` appends values
+		if g, ok := grouped[""]; ok {
+			for _, v := range g.values {
+				elem := reflect.New(field.Type().Elem()).Elem()
+				if err := b.bindNested(elem, []string{v}, nil, tag); err != nil {
 					return err
 				}
+				field.Set(reflect.Append(field, elem))
 			}
-			structField.Set(slice)
-			continue
 		}
+	default:
+		// nested keys of scalar values are ignored, i.e. `filter[status][x]` when `status` is string
+		return b.bindNested(field, values, nil, tag)
+	}
+	return nil
+}
 
-		if err := setWithProperType(structFieldKind, inputValue[0], structField); err != nil {
-			return err
+type bindEntryGroup struct {
+	values  []string
+	entries []bindEntry
+}
+
+// groupBindEntries groups entries by first segment of their path. Returns keys in order of first appearance.
+func groupBindEntries(entries []bindEntry) ([]string, map[string]*bindEntryGroup) {
+	var keys []string
+	grouped := map[string]*bindEntryGroup{}
+	for _, e := range entries {
+		g, ok := grouped[e.path[0]]
+		if !ok {
+			g = &bindEntryGroup{}
+			grouped[e.path[0]] = g
+			keys = append(keys, e.path[0])
+		}
+		if len(e.path) == 1 {
+			g.values = append(g.values, e.values...)
+		} else {
+			g.entries = append(g.entries, bindEntry{path: e.path[1:], values: e.values})
 		}
 	}
-	return nil
+	sort.Strings(keys)
+	return keys, grouped
+}
+
+// splitBindKey splits key with bracket and dot notation to segments, i.e. `items[0].sku` to `items`, `0`, `sku`.
+// Returns nil for keys that are not nested or are malformed.
+func splitBindKey(key string) []string {
+	i := strings.IndexAny(key, "[.")
+	if i <= 0 {
+		return nil
+	}
+	segments := []string{key[:i]}
+	rest := key[i:]
+	for rest != "" {
+		switch rest[0] {
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil
+			}
+			segments = append(segments, rest[1:end])
+			rest = rest[end+1:]
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, "[.")
+			if end == -1 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil
+			}
+			segments = append(segments, rest[:end])
+			rest = rest[end:]
+		default:
+			return nil
+		}
+	}
+	return segments
+}
+
+// joinBindKey joins segments to key with bracket notation, i.e. `address`, `city` to `address[city]`.
+func joinBindKey(segments []string) string {
+	var sb strings.Builder
+	sb.WriteString(segments[0])
+	for _, s := range segments[1:] {
+		sb.WriteByte('[')
+		sb.WriteString(s)
+		sb.WriteByte(']')
+	}
+	return sb.String()
 }
 
 func setWithProperType(valueKind reflect.Kind, val string, structField reflect.Value) error {
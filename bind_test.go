@@ -565,6 +565,396 @@ func TestBindbindData(t *testing.T) {
 	assert.Equal(t, "", ts.cantSet)
 }
 
+type bindNestedItem struct {
+	SKU      string `query:"sku" form:"sku"`
+	Quantity int    `query:"qty" form:"qty"`
+}
+
+type bindNestedFilter struct {
+	Status string `query:"status" form:"status"`
+	Owner  string `query:"owner" form:"owner"`
+}
+
+type bindNestedRequest struct {
+	Filter   bindNestedFilter             `query:"filter" form:"filter"`
+	Paging   *bindNestedFilter            `query:"paging"`
+	Items    []bindNestedItem             `query:"items" form:"items"`
+	Tags     []string                     `query:"tags" form:"tags"`
+	IDs      []int                        `query:"ids"`
+	Labels   map[string]string            `query:"labels"`
+	Extra    map[string]interface{}       `query:"extra"`
+	Groups   map[string]bindNestedItem    `query:"groups"`
+	Lists    map[string][]string          `query:"lists"`
+	Matrix   [][]int                      `query:"matrix"`
+	Pointers []*bindNestedItem            `query:"ptrs"`
+	Nested   map[string]map[string]string `query:"nested"`
+}
+
+func TestDefaultBinder_bindDataNested(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		whenData    map[string][]string
+		expect      bindNestedRequest
+		expectError string
+	}{
+		{
+			name: "ok, struct with bracket and dot notation",
+			whenData: map[string][]string{
+				"filter[status]": {"open"},
+				"filter.owner":   {"me"},
+				"paging[status]": {"x"},
+			},
+			expect: bindNestedRequest{
+				Filter: bindNestedFilter{Status: "open", Owner: "me"},
+				Paging: &bindNestedFilter{Status: "x"},
+			},
+		},
+		{
+			name: "ok, slice of structs",
+			whenData: map[string][]string{
+				"items[1].sku":   {"B"},
+				"items[0][sku]":  {"A"},
+				"items[0][qty]":  {"2"},
+				"ptrs[0].sku":    {"P"},
+				"ITEMS[1][qty]":  {"3"},
+				"matrix[1][1]":   {"5"},
+				"tags[]":         {"x", "y"},
+				"ids[1]":         {"20"},
+				"ids[0]":         {"10"},
+				"unrelated[0].a": {"b"},
+			},
+			expect: bindNestedRequest{
+				Items:    []bindNestedItem{{SKU: "A", Quantity: 2}, {SKU: "B", Quantity: 3}},
+				Pointers: []*bindNestedItem{{SKU: "P"}},
+				Matrix:   [][]int{nil, {0, 5}},
+				Tags:     []string{"x", "y"},
+				IDs:      []int{10, 20},
+			},
+		},
+		{
+			name: "ok, maps",
+			whenData: map[string][]string{
+				"labels[env]":       {"prod"},
+				"extra[a]":          {"1"},
+				"groups[g1].sku":    {"A"},
+				"lists[colors]":     {"red", "blue"},
+				"nested[a][b]":      {"c"},
+				"labels.team":       {"web"},
+				"groups[g1][qty]":   {"1"},
+				"groups[g2][qty]":   {"2"},
+				"nested[a].d":       {"e"},
+				"nested[x][y]":      {"z"},
+				"nested[x][y][too]": {"deep"},
+			},
+			expect: bindNestedRequest{
+				Labels: map[string]string{"env": "prod", "team": "web"},
+				Extra:  map[string]interface{}{"a": "1"},
+				Groups: map[string]bindNestedItem{"g1": {SKU: "A", Quantity: 1}, "g2": {Quantity: 2}},
+				Lists:  map[string][]string{"colors": {"red", "blue"}},
+				Nested: map[string]map[string]string{"a": {"b": "c", "d": "e"}, "x": {"y": "z"}},
+			},
+		},
+		{
+			name:        "nok, index too big",
+			whenData:    map[string][]string{"items[1001].sku": {"A"}},
+			expectError: "slice index 1001 exceeds maximum index 1000",
+		},
+		{
+			name:        "nok, invalid index",
+			whenData:    map[string][]string{"items[x].sku": {"A"}},
+			expectError: `invalid slice index "x"`,
+		},
+		{
+			name:        "nok, too deep",
+			whenData:    map[string][]string{"nested[a][b][c][d][e][f][g][h][i][j][k]": {"A"}},
+			expectError: `binding key "nested[a][b][c][d][e][f][g][h][i][j][k]" exceeds maximum nesting depth 10`,
+		},
+		{
+			name:        "nok, invalid value",
+			whenData:    map[string][]string{"items[0].qty": {"x"}},
+			expectError: `strconv.ParseInt: parsing "x": invalid syntax`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var dest bindNestedRequest
+			err := new(DefaultBinder).bindData(&dest, tc.whenData, "query", nil)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, dest)
+		})
+	}
+}
+
+func TestDefaultBinder_bindDataNestedLimits(t *testing.T) {
+	b := &DefaultBinder{MaxNestingDepth: 1, MaxSliceIndex: 2}
+	var dest bindNestedRequest
+
+	assert.NoError(t, b.bindData(&dest, map[string][]string{"items[2]": {}}, "query", nil))
+	assert.EqualError(t, b.bindData(&dest, map[string][]string{"items[3]": {}}, "query", nil),
+		"slice index 3 exceeds maximum index 2")
+	assert.EqualError(t, b.bindData(&dest, map[string][]string{"items[0].sku": {"A"}}, "query", nil),
+		`binding key "items[0].sku" exceeds maximum nesting depth 1`)
+}
+
+func TestDefaultBinder_bindDataNestedOnlyQueryAndForm(t *testing.T) {
+	var dest struct {
+		Filter map[string]string `param:"filter" header:"filter"`
+	}
+	data := map[string][]string{"filter[status]": {"open"}}
+	assert.NoError(t, new(DefaultBinder).bindData(&dest, data, "param", nil))
+	assert.NoError(t, new(DefaultBinder).bindData(&dest, data, "header", nil))
+	assert.Nil(t, dest.Filter)
+}
+
+func TestBindQueryParamsNested(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodGet, "/?filter[status]=open&filter[owner]=me&items[0].sku=A&tags[]=x&tags[]=y", nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	var dest bindNestedRequest
+	err := c.Bind(&dest)
+	assert.NoError(t, err)
+	assert.Equal(t, bindNestedRequest{
+		Filter: bindNestedFilter{Status: "open", Owner: "me"},
+		Items:  []bindNestedItem{{SKU: "A"}},
+		Tags:   []string{"x", "y"},
+	}, dest)
+}
+
+func TestBindMultipartFormNested(t *testing.T) {
+	body := new(bytes.Buffer)
+	mw := multipart.NewWriter(body)
+	mw.WriteField("filter[status]", "open")
+	mw.WriteField("items[0][sku]", "A")
+	mw.WriteField("items[0][qty]", "2")
+	mw.Close()
+
+	e := New()
+	req := httptest.NewRequest(http.MethodPost, "/", body)
+	req.Header.Set(HeaderContentType, mw.FormDataContentType())
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	var dest bindNestedRequest
+	err := c.Bind(&dest)
+	assert.NoError(t, err)
+	assert.Equal(t, bindNestedFilter{Status: "open"}, dest.Filter)
+	assert.Equal(t, []bindNestedItem{{SKU: "A", Quantity: 2}}, dest.Items)
+}
+
+func TestDefaultBinder_bindDataTagOptions(t *testing.T) {
+	type filter struct {
+		Status string `query:"status" default:"open"`
+	}
+	type request struct {
+		ID     int64    `param:"id,required" query:"id"`
+		Limit  int      `query:"limit" default:"20"`
+		Sort   *string  `query:"sort" default:"name"`
+		IDs    []int64  `query:"ids" delim:","`
+		Fields []string `query:"fields" delim:"," default:"id,name"`
+		Token  string   `header:"X-Token,required"`
+		Name   string   `form:"name,required"`
+		Filter filter
+	}
+	var testCases = []struct {
+		name        string
+		givenTag    string
+		givenData   map[string][]string
+		given       request
+		expect      request
+		expectError string
+	}{
+		{
+			name:      "ok, defaults",
+			givenTag:  "query",
+			givenData: map[string][]string{"ids": {"1,2", "3"}},
+			expect: request{
+				Limit:  20,
+				Sort:   ptr("name"),
+				IDs:    []int64{1, 2, 3},
+				Fields: []string{"id", "name"},
+				Filter: filter{Status: "open"},
+			},
+		},
+		{
+			name:      "ok, defaults without data",
+			givenTag:  "query",
+			givenData: nil,
+			expect:    request{Limit: 20, Sort: ptr("name"), Fields: []string{"id", "name"}, Filter: filter{Status: "open"}},
+		},
+		{
+			name:      "ok, values override defaults",
+			givenTag:  "query",
+			givenData: map[string][]string{"limit": {"5"}, "sort": {"id"}, "fields": {"id"}, "status": {"closed"}},
+			expect:    request{Limit: 5, Sort: ptr("id"), Fields: []string{"id"}, Filter: filter{Status: "closed"}},
+		},
+		{
+			name:      "ok, empty value gets default",
+			givenTag:  "query",
+			givenData: map[string][]string{"limit": {""}},
+			expect:    request{Limit: 20, Sort: ptr("name"), Fields: []string{"id", "name"}, Filter: filter{Status: "open"}},
+		},
+		{
+			name:      "ok, default does not override value from previous source",
+			givenTag:  "query",
+			givenData: map[string][]string{},
+			given:     request{Limit: 5},
+			expect:    request{Limit: 5, Sort: ptr("name"), Fields: []string{"id", "name"}, Filter: filter{Status: "open"}},
+		},
+		{
+			name:      "ok, required param",
+			givenTag:  "param",
+			givenData: map[string][]string{"id": {"1"}},
+			expect:    request{ID: 1},
+		},
+		{
+			name:        "nok, required param missing",
+			givenTag:    "param",
+			givenData:   map[string][]string{"other": {"1"}},
+			expectError: "code=400, message=required field value is empty, field=id",
+		},
+		{
+			name:        "nok, required header missing",
+			givenTag:    "header",
+			givenData:   nil,
+			expectError: "code=400, message=required field value is empty, field=X-Token",
+		},
+		{
+			name:        "nok, required form value empty",
+			givenTag:    "form",
+			givenData:   map[string][]string{"name": {""}},
+			expectError: "code=400, message=required field value is empty, field=name",
+		},
+		{
+			name:        "nok, invalid delimited value",
+			givenTag:    "query",
+			givenData:   map[string][]string{"ids": {"1,x"}},
+			expectError: `strconv.ParseInt: parsing "x": invalid syntax`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dest := tc.given
+			err := new(DefaultBinder).bindData(&dest, tc.givenData, tc.givenTag, nil)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, dest)
+		})
+	}
+}
+
+func TestBindTagOptions(t *testing.T) {
+	type request struct {
+		ID    int64   `param:"id,required"`
+		Limit int     `query:"limit" default:"20"`
+		IDs   []int64 `query:"ids" delim:","`
+		Token string  `header:"X-Token,required"`
+	}
+	e := New()
+	e.GET("/users/:id", func(c Context) error {
+		var req request
+		if err := c.Bind(&req); err != nil {
+			return err
+		}
+		if err := (&DefaultBinder{}).BindHeaders(c, &req); err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, req)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1?ids=1,2,3", nil)
+	req.Header.Set("X-Token", "secret")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"ID":1,"Limit":20,"IDs":[1,2,3],"Token":"secret"}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.JSONEq(t, `{"message":"required field value is empty, field=X-Token"}`, rec.Body.String())
+}
+
+func TestBindTagOptions_emptyBody(t *testing.T) {
+	type request struct {
+		Name  string `query:"name" form:"name,required"`
+		Limit int    `form:"limit" default:"20"`
+	}
+	var testCases = []struct {
+		name        string
+		whenMethod  string
+		whenURL     string
+		whenBody    string
+		expect      request
+		expectError string
+	}{
+		{
+			name:        "nok, required form value missing in empty POST body",
+			whenMethod:  http.MethodPost,
+			whenURL:     "/",
+			expectError: "code=400, message=required field value is empty, field=name, internal=code=400, message=required field value is empty, field=name",
+		},
+		{
+			name:       "ok, form values",
+			whenMethod: http.MethodPost,
+			whenURL:    "/",
+			whenBody:   "name=jon",
+			expect:     request{Name: "jon", Limit: 20},
+		},
+		{
+			name:       "ok, form options are not checked for GET",
+			whenMethod: http.MethodGet,
+			whenURL:    "/?name=jon",
+			expect:     request{Name: "jon"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			req := httptest.NewRequest(tc.whenMethod, tc.whenURL, strings.NewReader(tc.whenBody))
+			req.Header.Set(HeaderContentType, MIMEApplicationForm)
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			var dest request
+			err := c.Bind(&dest)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, dest)
+		})
+	}
+}
+
+func TestSplitBindKey(t *testing.T) {
+	var testCases = []struct {
+		whenKey string
+		expect  []string
+	}{
+		{whenKey: "name", expect: nil},
+		{whenKey: "filter[status]", expect: []string{"filter", "status"}},
+		{whenKey: "items[0].sku", expect: []string{"items", "0", "sku"}},
+		{whenKey: "a.b.c", expect: []string{"a", "b", "c"}},
+		{whenKey: "tags[]", expect: []string{"tags", ""}},
+		{whenKey: "[0]", expect: nil},
+		{whenKey: "a[0", expect: nil},
+		{whenKey: "a..b", expect: nil},
+		{whenKey: "a[0]b", expect: nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.whenKey, func(t *testing.T) {
+			assert.Equal(t, tc.expect, splitBindKey(tc.whenKey))
+		})
+	}
+}
+
 func TestBindParam(t *testing.T) {
 	e := New()
 	req := httptest.NewRequest(http.MethodGet, "/", nil)
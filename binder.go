@@ -18,6 +18,8 @@ import (
     * QueryParamsBinder(c) - binds query parameters (source URL)
     * PathParamsBinder(c) - binds path parameters (source URL)
     * FormFieldBinder(c) - binds form fields (source URL + body)
+    * HeadersBinder(c) - binds request headers
+    * CookiesBinder(c) - binds request cookies
 
 	Example:
   ```go
@@ -92,6 +94,11 @@ func (be *BindingError) Error() string {
 	return fmt.Sprintf("%s, field=%s", be.HTTPError.Error(), be.Field)
 }
 
+// InvalidParams returns field that failed to bind. Implements InvalidParamsError interface.
+func (be *BindingError) InvalidParams() []InvalidParam {
+	return []InvalidParam{{Name: be.Field, Reason: fmt.Sprintf("%v", be.Message)}}
+}
+
 // ValueBinder provides utility methods for binding query or path parameter to various Go built-in types
 type ValueBinder struct {
 	// ValueFunc is used to get single parameter (first) value from request
@@ -171,6 +178,47 @@ func FormFieldBinder(c Context) *ValueBinder {
 	return vb
 }
 
+// HeadersBinder creates request header value binder. Header names are case-insensitive.
+func HeadersBinder(c Context) *ValueBinder {
+	return &ValueBinder{
+		failFast: true,
+		ValueFunc: func(sourceParam string) string {
+			return c.Request().Header.Get(sourceParam)
+		},
+		ValuesFunc: func(sourceParam string) []string {
+			values := c.Request().Header.Values(sourceParam)
+			if len(values) == 0 {
+				return nil
+			}
+			return values
+		},
+		ErrorFunc: NewBindingError,
+	}
+}
+
+// CookiesBinder creates request cookie value binder. When request has multiple cookies with the same name, values are
+// returned in the order they appear in the `Cookie` header.
+func CookiesBinder(c Context) *ValueBinder {
+	return &ValueBinder{
+		failFast: true,
+		ValueFunc: func(sourceParam string) string {
+			cookie, err := c.Cookie(sourceParam)
+			if err != nil {
+				return ""
+			}
+			return cookie.Value
+		},
+		ValuesFunc: func(sourceParam string) []string {
+			var values []string
+			for _, cookie := range c.Request().CookiesNamed(sourceParam) {
+				values = append(values, cookie.Value)
+			}
+			return values
+		},
+		ErrorFunc: NewBindingError,
+	}
+}
+
 // FailFast set internal flag to indicate if binding methods will return early (without binding) when previous bind failed
 // NB: call this method before any other binding methods as it modifies binding methods behaviour
 func (b *ValueBinder) FailFast(value bool) *ValueBinder {
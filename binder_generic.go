@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+/**
+	Following generic functions convert single request value to type T:
+		* PathParam[T](c, "id") - path parameter
+		* QueryParam[T](c, "id") - first query parameter value
+		* QueryParams[T](c, "id") - all query parameter values
+		* HeaderValue[T](c, "X-Id") - first header value
+		* CookieValue[T](c, "id") - cookie value
+
+	Example:
+  ```go
+  id, err := echo.PathParam[int64](c, "id")
+  if err != nil {
+    return err
+  }
+  ```
+
+	T can be any type that `DefaultBinder` binds struct fields to: type implementing BindUnmarshaler or
+	encoding.TextUnmarshaler (i.e. time.Time), bool, string, int/uint and float types of any size, time.Duration or
+	pointer to one of these. Functions return `BindingError` when value does not exist (with ErrNonExistentKey as internal
+	error), when value is empty and T is not a string or unmarshaler type (with ErrEmptyValue as internal error), i.e.
+	for `?id=`, or when conversion fails.
+*/
+
+// ErrNonExistentKey is internal error of `BindingError` returned by generic value accessors when request does not have
+// value for the key.
+var ErrNonExistentKey = errors.New("non existent key")
+
+// ErrEmptyValue is internal error of `BindingError` returned by generic value accessors when request has empty value
+// for the key and the value can not be converted to requested type.
+var ErrEmptyValue = errors.New("empty value")
+
+// PathParam returns path parameter value converted to type T.
+func PathParam[T any](c Context, name string) (T, error) {
+	for _, n := range c.ParamNames() {
+		if n == name {
+			return parseValue[T](name, c.Param(name))
+		}
+	}
+	var zero T
+	return zero, nonExistentKeyError(name)
+}
+
+// QueryParam returns first query parameter value converted to type T.
+func QueryParam[T any](c Context, name string) (T, error) {
+	values, ok := c.QueryParams()[name]
+	if !ok || len(values) == 0 {
+		var zero T
+		return zero, nonExistentKeyError(name)
+	}
+	return parseValue[T](name, values[0])
+}
+
+// QueryParams returns all query parameter values converted to type T.
+func QueryParams[T any](c Context, name string) ([]T, error) {
+	values, ok := c.QueryParams()[name]
+	if !ok || len(values) == 0 {
+		return nil, nonExistentKeyError(name)
+	}
+	result := make([]T, len(values))
+	for i, value := range values {
+		v, err := parseValue[T](name, value)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = v
+	}
+	return result, nil
+}
+
+// HeaderValue returns first value of request header converted to type T.
+func HeaderValue[T any](c Context, name string) (T, error) {
+	values := c.Request().Header.Values(name)
+	if len(values) == 0 {
+		var zero T
+		return zero, nonExistentKeyError(name)
+	}
+	return parseValue[T](name, values[0])
+}
+
+// CookieValue returns value of request cookie converted to type T.
+func CookieValue[T any](c Context, name string) (T, error) {
+	cookie, err := c.Cookie(name)
+	if err != nil {
+		var zero T
+		return zero, nonExistentKeyError(name)
+	}
+	return parseValue[T](name, cookie.Value)
+}
+
+func nonExistentKeyError(name string) error {
+	return NewBindingError(name, nil, "required field value is empty", ErrNonExistentKey)
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func parseValue[T any](name string, value string) (T, error) {
+	var result T
+	field := reflect.ValueOf(&result).Elem()
+	for field.Kind() == reflect.Ptr {
+		field.Set(reflect.New(field.Type().Elem()))
+		field = field.Elem()
+	}
+
+	isUnmarshaler, err := unmarshalInputToField(field.Kind(), value, field)
+	if !isUnmarshaler {
+		switch {
+		case value == "" && field.Kind() != reflect.String:
+			err = ErrEmptyValue
+		case field.Type() == durationType:
+			var d time.Duration
+			if d, err = time.ParseDuration(value); err == nil {
+				field.SetInt(int64(d))
+			}
+		default:
+			err = setWithProperType(field.Kind(), value, field)
+		}
+	}
+	if err != nil {
+		var zero T
+		return zero, NewBindingError(name, []string{value}, fmt.Sprintf("failed to bind field value to %v", reflect.TypeOf(result)), err)
+	}
+	return result, nil
+}
@@ -0,0 +1,208 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathParam(t *testing.T) {
+	c := createTestContext("/", nil, map[string]string{"id": "1", "name": "bob", "empty": ""})
+
+	id, err := PathParam[int64](c, "id")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), id)
+
+	name, err := PathParam[*string](c, "name")
+	assert.NoError(t, err)
+	assert.Equal(t, "bob", *name)
+
+	empty, err := PathParam[string](c, "empty")
+	assert.NoError(t, err)
+	assert.Equal(t, "", empty)
+
+	_, err = PathParam[int64](c, "name")
+	assert.EqualError(t, err, `code=400, message=failed to bind field value to int64, internal=strconv.ParseInt: parsing "bob": invalid syntax, field=name`)
+
+	_, err = PathParam[int64](c, "missing")
+	assert.EqualError(t, err, `code=400, message=required field value is empty, internal=non existent key, field=missing`)
+	assert.ErrorIs(t, err, ErrNonExistentKey)
+}
+
+func TestQueryParam(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		whenURL     string
+		whenName    string
+		expect      interface{}
+		expectError string
+	}{
+		{
+			name:     "ok, int",
+			whenURL:  "/?n=-5&n=6",
+			whenName: "n",
+			expect:   -5,
+		},
+		{
+			name:     "ok, bool",
+			whenURL:  "/?b=true",
+			whenName: "b",
+			expect:   true,
+		},
+		{
+			name:     "ok, float32",
+			whenURL:  "/?f=1.5",
+			whenName: "f",
+			expect:   float32(1.5),
+		},
+		{
+			name:     "ok, duration",
+			whenURL:  "/?d=1m30s",
+			whenName: "d",
+			expect:   90 * time.Second,
+		},
+		{
+			name:     "ok, pointer to duration",
+			whenURL:  "/?d=1m30s",
+			whenName: "d",
+			expect:   func() *time.Duration { d := 90 * time.Second; return &d }(),
+		},
+		{
+			name:     "ok, TextUnmarshaler",
+			whenURL:  "/?t=2024-01-02T03:04:05Z",
+			whenName: "t",
+			expect:   time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		{
+			name:     "ok, BindUnmarshaler",
+			whenURL:  "/?ts=2024-01-02T03:04:05Z",
+			whenName: "ts",
+			expect:   Timestamp(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+		},
+		{
+			name:        "nok, uint8 overflow",
+			whenURL:     "/?u=300",
+			whenName:    "u",
+			expect:      uint8(0),
+			expectError: `code=400, message=failed to bind field value to uint8, internal=strconv.ParseUint: parsing "300": value out of range, field=u`,
+		},
+		{
+			name:        "nok, empty int",
+			whenURL:     "/?n=",
+			whenName:    "n",
+			expect:      0,
+			expectError: `code=400, message=failed to bind field value to int, internal=empty value, field=n`,
+		},
+		{
+			name:        "nok, empty duration",
+			whenURL:     "/?d=",
+			whenName:    "d",
+			expect:      time.Duration(0),
+			expectError: `code=400, message=failed to bind field value to time.Duration, internal=empty value, field=d`,
+		},
+		{
+			name:        "nok, missing",
+			whenURL:     "/?other=1",
+			whenName:    "n",
+			expect:      0,
+			expectError: `code=400, message=required field value is empty, internal=non existent key, field=n`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := createTestContext(tc.whenURL, nil, nil)
+
+			var result interface{}
+			var err error
+			switch tc.expect.(type) {
+			case int:
+				result, err = QueryParam[int](c, tc.whenName)
+			case bool:
+				result, err = QueryParam[bool](c, tc.whenName)
+			case float32:
+				result, err = QueryParam[float32](c, tc.whenName)
+			case time.Duration:
+				result, err = QueryParam[time.Duration](c, tc.whenName)
+			case *time.Duration:
+				result, err = QueryParam[*time.Duration](c, tc.whenName)
+			case time.Time:
+				result, err = QueryParam[time.Time](c, tc.whenName)
+			case Timestamp:
+				result, err = QueryParam[Timestamp](c, tc.whenName)
+			case uint8:
+				result, err = QueryParam[uint8](c, tc.whenName)
+			}
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expect, result)
+		})
+	}
+}
+
+func TestQueryParams(t *testing.T) {
+	c := createTestContext("/?id=1&id=2&name=x", nil, nil)
+
+	ids, err := QueryParams[int64](c, "id")
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, ids)
+
+	ids, err = QueryParams[int64](c, "name")
+	assert.EqualError(t, err, `code=400, message=failed to bind field value to int64, internal=strconv.ParseInt: parsing "x": invalid syntax, field=name`)
+	assert.Nil(t, ids)
+
+	_, err = QueryParams[int64](c, "missing")
+	assert.ErrorIs(t, err, ErrNonExistentKey)
+}
+
+func TestHeaderValue(t *testing.T) {
+	c := createTestContext("/", nil, nil)
+	c.Request().Header.Set("X-Limit", "10")
+
+	limit, err := HeaderValue[uint](c, "x-limit")
+	assert.NoError(t, err)
+	assert.Equal(t, uint(10), limit)
+
+	_, err = HeaderValue[uint](c, "X-Missing")
+	assert.ErrorIs(t, err, ErrNonExistentKey)
+}
+
+func TestCookieValue(t *testing.T) {
+	c := createTestContext("/", nil, nil)
+	c.Request().AddCookie(&http.Cookie{Name: "page", Value: "2"})
+
+	page, err := CookieValue[int](c, "page")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, page)
+
+	_, err = CookieValue[int](c, "missing")
+	var be *BindingError
+	assert.True(t, errors.As(err, &be))
+	assert.Equal(t, "missing", be.Field)
+}
+
+func TestPathParam_errorResponse(t *testing.T) {
+	e := New()
+	e.GET("/users/:id", func(c Context) error {
+		id, err := PathParam[int64](c, "id")
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, id)
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/abc", nil))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.JSONEq(t, `{"message":"failed to bind field value to int64, field=id"}`, rec.Body.String())
+}
@@ -131,6 +131,51 @@ func TestQueryParamsBinder_FailFast(t *testing.T) {
 	}
 }
 
+func TestHeadersBinder(t *testing.T) {
+	c := createTestContext("/", nil, nil)
+	c.Request().Header.Set("X-Request-Id", "1")
+	c.Request().Header.Add("X-Ids", "1,2")
+	c.Request().Header.Add("X-Ids", "3")
+
+	var id int64
+	var ids []int64
+	var missing string
+	err := HeadersBinder(c).
+		Int64("x-request-id", &id).
+		BindWithDelimiter("X-Ids", &ids, ",").
+		String("X-Missing", &missing).
+		BindError()
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), id)
+	assert.Equal(t, []int64{1, 2, 3}, ids)
+
+	err = HeadersBinder(c).MustString("X-Missing", &missing).BindError()
+	assert.EqualError(t, err, "code=400, message=required field value is empty, field=X-Missing")
+}
+
+func TestCookiesBinder(t *testing.T) {
+	c := createTestContext("/", nil, nil)
+	c.Request().Header.Set("Cookie", "session=abc; page=2; tag=a; tag=b")
+
+	var session string
+	var page int
+	var tags []string
+	err := CookiesBinder(c).
+		String("session", &session).
+		Int("page", &page).
+		Strings("tag", &tags).
+		BindError()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", session)
+	assert.Equal(t, 2, page)
+	assert.Equal(t, []string{"a", "b"}, tags)
+
+	err = CookiesBinder(c).MustInt("missing", &page).BindError()
+	assert.EqualError(t, err, "code=400, message=required field value is empty, field=missing")
+}
+
 func TestFormFieldBinder(t *testing.T) {
 	e := New()
 	body := `texta=foo&slice=5`
@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Codec is the interface that decodes request bodies and encodes response bodies for a media type.
+type Codec interface {
+	// Decode reads request body and converts it into an interface.
+	Decode(c Context, i interface{}) error
+	// Encode converts an interface and writes it to the response. Indent is optional and is used to pretty print output.
+	Encode(c Context, i interface{}, indent string) error
+}
+
+// CodecRegistry maps media types to Codecs. It is used by `DefaultBinder.BindBody` to decode request bodies and by
+// `Context.Encode` to write responses.
+//
+// Media types can be registered as exact values (`application/json`), with structured syntax suffix wildcard
+// (`application/*+json`) or with subtype wildcard (`application/*`). Lookup prefers exact match over suffix match over
+// subtype wildcard match.
+type CodecRegistry struct {
+	codecs map[string]Codec
+	// mediaTypes preserves registration order for callers that need deterministic iteration
+	mediaTypes []string
+}
+
+// NewCodecRegistry creates new empty instance of CodecRegistry.
+func NewCodecRegistry() *CodecRegistry {
+	return &CodecRegistry{codecs: map[string]Codec{}}
+}
+
+// Register adds (or replaces) codec for given media type. Media type parameters (`; charset=UTF-8`) are ignored.
+func (r *CodecRegistry) Register(mediaType string, codec Codec) {
+	mediaType = normalizeMediaType(mediaType)
+	if _, ok := r.codecs[mediaType]; !ok {
+		r.mediaTypes = append(r.mediaTypes, mediaType)
+	}
+	r.codecs[mediaType] = codec
+}
+
+// Unregister removes codec registered for given media type.
+func (r *CodecRegistry) Unregister(mediaType string) {
+	mediaType = normalizeMediaType(mediaType)
+	if _, ok := r.codecs[mediaType]; !ok {
+		return
+	}
+	delete(r.codecs, mediaType)
+	for i, mt := range r.mediaTypes {
+		if mt == mediaType {
+			r.mediaTypes = append(r.mediaTypes[:i], r.mediaTypes[i+1:]...)
+			break
+		}
+	}
+}
+
+// Lookup returns codec for given media type (i.e. value of `Content-Type` header).
+func (r *CodecRegistry) Lookup(mediaType string) (Codec, bool) {
+	if r == nil {
+		return nil, false
+	}
+	mediaType = normalizeMediaType(mediaType)
+	if codec, ok := r.codecs[mediaType]; ok {
+		return codec, true
+	}
+	typ, subtype, ok := strings.Cut(mediaType, "/")
+	if !ok {
+		return nil, false
+	}
+	if i := strings.LastIndexByte(subtype, '+'); i != -1 {
+		if codec, ok := r.codecs[typ+"/*"+subtype[i:]]; ok {
+			return codec, true
+		}
+	}
+	codec, ok := r.codecs[typ+"/*"]
+	return codec, ok
+}
+
+// MediaTypes returns registered media types in order of registration.
+func (r *CodecRegistry) MediaTypes() []string {
+	if r == nil {
+		return nil
+	}
+	result := make([]string, len(r.mediaTypes))
+	copy(result, r.mediaTypes)
+	return result
+}
+
+// normalizeMediaType removes parameters from media type and lowercases it. Media type is found like
+// `mime.ParseMediaType()` does it.
+func normalizeMediaType(mediaType string) string {
+	base, _, _ := strings.Cut(mediaType, ";")
+	return strings.ToLower(strings.TrimSpace(base))
+}
+
+func (e *Echo) registerDefaultCodecs() {
+	e.Codecs = NewCodecRegistry()
+	e.Codecs.Register(MIMEApplicationJSON, JSONCodec{})
+	e.Codecs.Register("application/*+json", JSONCodec{})
+	e.Codecs.Register(MIMEApplicationXML, XMLCodec{})
+	e.Codecs.Register(MIMETextXML, XMLCodec{})
+	e.Codecs.Register("application/*+xml", XMLCodec{})
+}
+
+// JSONCodec implements Codec for JSON media types using `Echo.JSONSerializer`, or `Group.JSONSerializer` of group
+// of matched route.
+type JSONCodec struct{}
+
+// Decode reads a JSON from a request body with JSONSerializer.
+func (JSONCodec) Decode(c Context, i interface{}) error {
+	return jsonSerializer(c).Deserialize(c, i)
+}
+
+// Encode writes i as JSON to the response with JSONSerializer.
+func (JSONCodec) Encode(c Context, i interface{}, indent string) error {
+	return jsonSerializer(c).Serialize(c, i, indent)
+}
+
+// jsonSerializer returns JSONSerializer of the nearest group of matched route that sets it, or of Echo.
+func jsonSerializer(c Context) JSONSerializer {
+	for g := matchedGroupKey.Value(c); g != nil; g = g.parent {
+		if g.JSONSerializer != nil {
+			return g.JSONSerializer
+		}
+	}
+	return c.Echo().JSONSerializer
+}
+
+// XMLCodec implements Codec for XML media types using encoding/xml.
+type XMLCodec struct{}
+
+// Decode reads a XML from a request body and converts it into an interface.
+func (XMLCodec) Decode(c Context, i interface{}) error {
+	err := xml.NewDecoder(c.Request().Body).Decode(i)
+	if ute, ok := err.(*xml.UnsupportedTypeError); ok {
+		return NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unsupported type error: type=%v, error=%v", ute.Type, ute.Error())).SetInternal(err)
+	} else if se, ok := err.(*xml.SyntaxError); ok {
+		return NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Syntax error: line=%v, error=%v", se.Line, se.Error())).SetInternal(err)
+	}
+	return err
+}
+
+// Encode writes XML header and i as XML to the response.
+func (XMLCodec) Encode(c Context, i interface{}, indent string) error {
+	res := c.Response()
+	enc := xml.NewEncoder(res)
+	if indent != "" {
+		enc.Indent("", indent)
+	}
+	if _, err := res.Write([]byte(xml.Header)); err != nil {
+		return err
+	}
+	return enc.Encode(i)
+}
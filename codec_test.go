@@ -0,0 +1,283 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testCSVCodec struct{}
+
+func (testCSVCodec) Decode(c Context, i interface{}) error {
+	b, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return err
+	}
+	parts := strings.Split(strings.TrimSpace(string(b)), ",")
+	if len(parts) != 2 {
+		return errors.New("invalid csv")
+	}
+	u := i.(*user)
+	u.Name = parts[1]
+	return nil
+}
+
+func (testCSVCodec) Encode(c Context, i interface{}, indent string) error {
+	u := i.(user)
+	_, err := c.Response().Write([]byte(u.Name + "\n"))
+	return err
+}
+
+func TestCodecRegistry_Lookup(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		whenType    string
+		expectCodec Codec
+		expectOk    bool
+	}{
+		{
+			name:        "ok, exact match",
+			whenType:    MIMEApplicationJSON,
+			expectCodec: JSONCodec{},
+			expectOk:    true,
+		},
+		{
+			name:        "ok, exact match with parameters and different case",
+			whenType:    "Application/JSON; charset=UTF-8",
+			expectCodec: JSONCodec{},
+			expectOk:    true,
+		},
+		{
+			name:        "ok, suffix match json",
+			whenType:    "application/vnd.api+json",
+			expectCodec: JSONCodec{},
+			expectOk:    true,
+		},
+		{
+			name:        "ok, suffix match xml",
+			whenType:    "application/atom+xml",
+			expectCodec: XMLCodec{},
+			expectOk:    true,
+		},
+		{
+			name:        "ok, text/xml",
+			whenType:    MIMETextXML,
+			expectCodec: XMLCodec{},
+			expectOk:    true,
+		},
+		{
+			name:     "nok, unregistered",
+			whenType: MIMEApplicationMsgpack,
+		},
+		{
+			name:     "nok, invalid media type",
+			whenType: "json",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			codec, ok := e.Codecs.Lookup(tc.whenType)
+			assert.Equal(t, tc.expectOk, ok)
+			assert.Equal(t, tc.expectCodec, codec)
+		})
+	}
+}
+
+func TestCodecRegistry_LookupPrefersExactMatch(t *testing.T) {
+	r := NewCodecRegistry()
+	r.Register("text/*", JSONCodec{})
+	r.Register("text/*+csv", XMLCodec{})
+	r.Register("text/x+csv", testCSVCodec{})
+
+	codec, ok := r.Lookup("text/x+csv")
+	assert.True(t, ok)
+	assert.Equal(t, testCSVCodec{}, codec)
+
+	codec, ok = r.Lookup("text/y+csv")
+	assert.True(t, ok)
+	assert.Equal(t, XMLCodec{}, codec)
+
+	codec, ok = r.Lookup("text/plain")
+	assert.True(t, ok)
+	assert.Equal(t, JSONCodec{}, codec)
+}
+
+func TestCodecRegistry_RegisterUnregister(t *testing.T) {
+	r := NewCodecRegistry()
+	r.Register("text/csv", testCSVCodec{})
+	r.Register(MIMEApplicationJSON, JSONCodec{})
+	r.Register("TEXT/CSV", testCSVCodec{}) // replacing does not change order
+
+	assert.Equal(t, []string{"text/csv", MIMEApplicationJSON}, r.MediaTypes())
+
+	r.Unregister("text/csv")
+	_, ok := r.Lookup("text/csv")
+	assert.False(t, ok)
+	assert.Equal(t, []string{MIMEApplicationJSON}, r.MediaTypes())
+
+	var nilRegistry *CodecRegistry
+	_, ok = nilRegistry.Lookup(MIMEApplicationJSON)
+	assert.False(t, ok)
+	assert.Nil(t, nilRegistry.MediaTypes())
+}
+
+func TestDefaultBinder_BindBodyWithRegisteredCodec(t *testing.T) {
+	e := New()
+	e.Codecs.Register("text/csv", testCSVCodec{})
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("1,Jon Snow"))
+	req.Header.Set(HeaderContentType, "text/csv")
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	u := new(user)
+	err := new(DefaultBinder).BindBody(c, u)
+	assert.NoError(t, err)
+	assert.Equal(t, "Jon Snow", u.Name)
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("invalid"))
+	req.Header.Set(HeaderContentType, "text/csv")
+	c = e.NewContext(req, httptest.NewRecorder())
+
+	err = new(DefaultBinder).BindBody(c, u)
+	var he *HTTPError
+	if assert.ErrorAs(t, err, &he) {
+		assert.Equal(t, http.StatusBadRequest, he.Code)
+		assert.EqualError(t, he.Internal, "invalid csv")
+	}
+}
+
+func TestDefaultBinder_BindBodyStructuredSyntaxSuffix(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(userJSON))
+	req.Header.Set(HeaderContentType, "application/merge-patch+json")
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	u := new(user)
+	err := new(DefaultBinder).BindBody(c, u)
+	assert.NoError(t, err)
+	assert.Equal(t, user{ID: 1, Name: "Jon Snow"}, *u)
+}
+
+func TestContext_Encode(t *testing.T) {
+	var testCases = []struct {
+		name              string
+		whenContentType   string
+		whenURL           string
+		expectBody        string
+		expectContentType string
+		expectErr         error
+	}{
+		{
+			name:              "ok, json",
+			whenContentType:   MIMEApplicationJSON,
+			whenURL:           "/",
+			expectBody:        userJSON + "\n",
+			expectContentType: MIMEApplicationJSON,
+		},
+		{
+			name:              "ok, json suffix with pretty",
+			whenContentType:   "application/vnd.user+json",
+			whenURL:           "/?pretty",
+			expectBody:        userJSONPretty + "\n",
+			expectContentType: "application/vnd.user+json",
+		},
+		{
+			name:              "ok, xml",
+			whenContentType:   MIMEApplicationXMLCharsetUTF8,
+			whenURL:           "/",
+			expectBody:        xml.Header + userXML,
+			expectContentType: MIMEApplicationXMLCharsetUTF8,
+		},
+		{
+			name:              "ok, custom codec",
+			whenContentType:   "text/csv",
+			whenURL:           "/",
+			expectBody:        "Jon Snow\n",
+			expectContentType: "text/csv",
+		},
+		{
+			name:            "nok, codec not registered",
+			whenContentType: MIMEApplicationMsgpack,
+			whenURL:         "/",
+			expectErr:       ErrCodecNotRegistered,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.Codecs.Register("text/csv", testCSVCodec{})
+
+			req := httptest.NewRequest(http.MethodGet, tc.whenURL, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := c.Encode(http.StatusCreated, tc.whenContentType, user{1, "Jon Snow"})
+			if tc.expectErr != nil {
+				assert.ErrorIs(t, err, tc.expectErr)
+				assert.False(t, c.Response().Committed)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusCreated, rec.Code)
+			assert.Equal(t, tc.expectContentType, rec.Header().Get(HeaderContentType))
+			assert.Equal(t, tc.expectBody, rec.Body.String())
+		})
+	}
+}
+
+func TestJSONCodec_GroupSerializerWithWrappedContext(t *testing.T) {
+	e := New()
+	api := e.Group("/api", func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			return next(&testWrappedContext{Context: c})
+		}
+	})
+	api.JSONSerializer = testGroupSerializer{}
+	api.GET("/json", func(c Context) error {
+		return c.Encode(http.StatusOK, MIMEApplicationJSON, map[string]bool{"group": false})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/json", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `{"group":true}`, rec.Body.String())
+}
+
+func TestJSONCodec_GroupSerializerForMethodNotAllowed(t *testing.T) {
+	e := New()
+	e.Use(func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			return next(&testWrappedContext{Context: c})
+		}
+	})
+	e.HTTPErrorHandler = func(err error, c Context) {
+		_ = c.Encode(http.StatusMethodNotAllowed, MIMEApplicationJSON, map[string]bool{"group": false})
+		_ = c.JSON(http.StatusMethodNotAllowed, map[string]bool{"group": false})
+	}
+	api := e.Group("/api")
+	api.JSONSerializer = testGroupSerializer{}
+	api.GET("/json", func(c Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/json", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, `{"group":true}{"group":true}`, rec.Body.String())
+}
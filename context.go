@@ -51,9 +51,18 @@ type Context interface {
 	// SetPath sets the registered path for the handler.
 	SetPath(p string)
 
+	// Route returns the route that matched the request. When no route matches the request it is the route registered
+	// with `RouteNotFound` for the path, if any. Returns nil for 404 and 405 responses without `RouteNotFound` route,
+	// for routes added directly with `Router.Add` or when called before routing in `Pre` middleware.
+	Route() *Route
+
 	// Param returns path parameter by name.
 	Param(name string) string
 
+	// HostParam returns parameter of host pattern (i.e. `tenant` for `{tenant}.example.com`) by name. Returns empty
+	// string when request host did not match host pattern with such parameter.
+	HostParam(name string) string
+
 	// ParamNames returns path parameter names.
 	ParamNames() []string
 
@@ -87,6 +96,10 @@ type Context interface {
 	// MultipartForm returns the multipart form.
 	MultipartForm() (*multipart.Form, error)
 
+	// MultipartReader returns reader that streams parts of multipart/form-data request body with limits of config.
+	// See `MultipartReader`.
+	MultipartReader(config MultipartConfig) (*MultipartReader, error)
+
 	// Cookie returns the named cookie provided in the request.
 	Cookie(name string) (*http.Cookie, error)
 
@@ -96,7 +109,8 @@ type Context interface {
 	// Cookies returns the HTTP cookies sent with the request.
 	Cookies() []*http.Cookie
 
-	// Get retrieves data from the context.
+	// Get retrieves data from the context. Values set with `ContextKey` are returned by key name when there is no value
+	// set with Set for the same key. Use `ContextKey` for typed values.
 	Get(key string) interface{}
 
 	// Set saves data in the context.
@@ -149,12 +163,31 @@ type Context interface {
 	// XMLBlob sends an XML blob response with status code.
 	XMLBlob(code int, b []byte) error
 
+	// Encode sends a response with status code and content type. Body is encoded with the Codec registered
+	// for content type in `Echo.Codecs`.
+	Encode(code int, contentType string, i interface{}) error
+
+	// Negotiate sends a response with status code in the offered media type that best matches the request `Accept`
+	// header and adds `Vary: Accept` header to the response. When no offers are given, media types with registered Codec
+	// are offered (and `text/html` when `i` is TemplateData and `Echo.Renderer` is registered).
+	// Returns ErrNotAcceptable when none of the offers is acceptable to the client.
+	Negotiate(code int, i interface{}, offers ...string) error
+
 	// Blob sends a blob response with status code and content type.
 	Blob(code int, contentType string, b []byte) error
 
 	// Stream sends a streaming response with status code and content type.
 	Stream(code int, contentType string, r io.Reader) error
 
+	// EventStream sends Server-Sent Events (`text/event-stream`) response headers and calls fn to write events to the
+	// stream. Stream is terminated when fn returns. Returns nil when fn returned because request context was cancelled
+	// (client disconnected).
+	EventStream(fn func(es *EventStream) error) error
+
+	// WebSocket upgrades the connection to WebSocket with default `WebSocketUpgrader` and calls fn to communicate
+	// over it. Connection is closed when fn returns. See `WebSocketUpgrader.Upgrade`.
+	WebSocket(fn func(ws *WebSocket) error) error
+
 	// File sends a response with the content of the file.
 	File(file string) error
 
@@ -184,7 +217,8 @@ type Context interface {
 	// SetHandler sets the matched handler by router.
 	SetHandler(h HandlerFunc)
 
-	// Logger returns the `Logger` instance.
+	// Logger returns the `Logger` instance. When `Echo.RequestLogger` is set, the request logger is created on the
+	// first call and returned for the rest of the request.
 	Logger() Logger
 
 	// SetLogger Set the logger
@@ -194,7 +228,8 @@ type Context interface {
 	Echo() *Echo
 
 	// Reset resets the context after request completes. It must be called along
-	// with `Echo#AcquireContext()` and `Echo#ReleaseContext()`.
+	// with `Echo#AcquireContext()` and `Echo#ReleaseContext()`. Cleanup of the previous
+	// request, i.e. removal of spooled multipart files, is done before context is reset.
 	// See `Echo#ServeHTTP()`
 	Reset(r *http.Request, w http.ResponseWriter)
 }
@@ -209,9 +244,22 @@ type context struct {
 	store Map
 	lock  sync.RWMutex
 
+	// values are values of ContextKeys indexed by key slot. Slice is reused across requests.
+	values []contextValue
+
 	// following fields are set by Router
 	handler HandlerFunc
 
+	// route is route that Router matched. It is nil when there is no route match.
+	route *Route
+
+	// hostParamNames and hostParamValues are parameters of host pattern that request host matched.
+	hostParamNames  []string
+	hostParamValues []string
+
+	// requestEnd are functions called when request ends, i.e. to clean up temporary files.
+	requestEnd []func()
+
 	// path is route path that Router matched. It is empty string where there is no route match.
 	// Route registered with RouteNotFound is considered as a match and path therefore is not empty.
 	path string
@@ -234,6 +282,14 @@ const (
 	ContextKeyHeaderAllow = "echo_header_allow"
 )
 
+// headerAllowKey is typed key for ContextKeyHeaderAllow value.
+var headerAllowKey = NewContextKey[string](ContextKeyHeaderAllow)
+
+// matchedGroupKey is set by Router to group of matched route, or of route registered for the matched path when method
+// is not allowed. Group settings override Echo settings for the request. Value is looked up through Context interface,
+// so contexts wrapped by middleware resolve the same settings.
+var matchedGroupKey = NewContextKey[*Group]("echo_group")
+
 const (
 	defaultMemory = 32 << 20 // 32 MB
 	indexPage     = "index.html"
@@ -325,6 +381,10 @@ func (c *context) SetPath(p string) {
 	c.path = p
 }
 
+func (c *context) Route() *Route {
+	return c.route
+}
+
 func (c *context) Param(name string) string {
 	for i, n := range c.pnames {
 		if i < len(c.pvalues) {
@@ -336,6 +396,15 @@ func (c *context) Param(name string) string {
 	return ""
 }
 
+func (c *context) HostParam(name string) string {
+	for i, n := range c.hostParamNames {
+		if n == name && i < len(c.hostParamValues) {
+			return c.hostParamValues[i]
+		}
+	}
+	return ""
+}
+
 func (c *context) ParamNames() []string {
 	return c.pnames
 }
@@ -433,7 +502,11 @@ func (c *context) Cookies() []*http.Cookie {
 func (c *context) Get(key string) interface{} {
 	c.lock.RLock()
 	defer c.lock.RUnlock()
-	return c.store[key]
+	if v, ok := c.store[key]; ok {
+		return v
+	}
+	v, _ := c.valueByName(key)
+	return v
 }
 
 func (c *context) Set(key string, val interface{}) {
@@ -447,22 +520,24 @@ func (c *context) Set(key string, val interface{}) {
 }
 
 func (c *context) Bind(i interface{}) error {
-	return c.echo.Binder.Bind(i, c)
+	return c.binder().Bind(i, c)
 }
 
 func (c *context) Validate(i interface{}) error {
-	if c.echo.Validator == nil {
+	validator := c.validator()
+	if validator == nil {
 		return ErrValidatorNotRegistered
 	}
-	return c.echo.Validator.Validate(i)
+	return validator.Validate(i)
 }
 
 func (c *context) Render(code int, name string, data interface{}) (err error) {
-	if c.echo.Renderer == nil {
+	renderer := c.renderer()
+	if renderer == nil {
 		return ErrRendererNotRegistered
 	}
 	buf := new(bytes.Buffer)
-	if err = c.echo.Renderer.Render(buf, name, data, c); err != nil {
+	if err = renderer.Render(buf, name, data, c); err != nil {
 		return
 	}
 	return c.HTMLBlob(code, buf.Bytes())
@@ -490,7 +565,7 @@ func (c *context) jsonPBlob(code int, callback string, i interface{}) (err error
 	if _, err = c.response.Write([]byte(callback + "(")); err != nil {
 		return
 	}
-	if err = c.echo.JSONSerializer.Serialize(c, i, indent); err != nil {
+	if err = jsonSerializer(c).Serialize(c, i, indent); err != nil {
 		return
 	}
 	if _, err = c.response.Write([]byte(");")); err != nil {
@@ -502,7 +577,7 @@ func (c *context) jsonPBlob(code int, callback string, i interface{}) (err error
 func (c *context) json(code int, i interface{}, indent string) error {
 	c.writeContentType(MIMEApplicationJSON)
 	c.response.Status = code
-	return c.echo.JSONSerializer.Serialize(c, i, indent)
+	return jsonSerializer(c).Serialize(c, i, indent)
 }
 
 func (c *context) JSON(code int, i interface{}) (err error) {
@@ -541,14 +616,7 @@ func (c *context) JSONPBlob(code int, callback string, b []byte) (err error) {
 func (c *context) xml(code int, i interface{}, indent string) (err error) {
 	c.writeContentType(MIMEApplicationXMLCharsetUTF8)
 	c.response.WriteHeader(code)
-	enc := xml.NewEncoder(c.response)
-	if indent != "" {
-		enc.Indent("", indent)
-	}
-	if _, err = c.response.Write([]byte(xml.Header)); err != nil {
-		return
-	}
-	return enc.Encode(i)
+	return XMLCodec{}.Encode(c, i, indent)
 }
 
 func (c *context) XML(code int, i interface{}) (err error) {
@@ -573,6 +641,20 @@ func (c *context) XMLBlob(code int, b []byte) (err error) {
 	return
 }
 
+func (c *context) Encode(code int, contentType string, i interface{}) error {
+	codec, ok := c.echo.Codecs.Lookup(contentType)
+	if !ok {
+		return ErrCodecNotRegistered
+	}
+	indent := ""
+	if _, pretty := c.QueryParams()["pretty"]; c.echo.Debug || pretty {
+		indent = defaultIndent
+	}
+	c.writeContentType(contentType)
+	c.response.Status = code
+	return codec.Encode(c, i, indent)
+}
+
 func (c *context) Blob(code int, contentType string, b []byte) (err error) {
 	c.writeContentType(contentType)
 	c.response.WriteHeader(code)
@@ -617,7 +699,47 @@ func (c *context) Redirect(code int, url string) error {
 }
 
 func (c *context) Error(err error) {
-	c.echo.HTTPErrorHandler(err, c)
+	c.httpErrorHandler()(err, c)
+}
+
+// httpErrorHandler returns HTTPErrorHandler of the nearest group of matched route that sets it, or of Echo.
+func (c *context) httpErrorHandler() HTTPErrorHandler {
+	for g := matchedGroupKey.Value(c); g != nil; g = g.parent {
+		if g.HTTPErrorHandler != nil {
+			return g.HTTPErrorHandler
+		}
+	}
+	return c.echo.HTTPErrorHandler
+}
+
+// binder returns Binder of the nearest group of matched route that sets it, or of Echo.
+func (c *context) binder() Binder {
+	for g := matchedGroupKey.Value(c); g != nil; g = g.parent {
+		if g.Binder != nil {
+			return g.Binder
+		}
+	}
+	return c.echo.Binder
+}
+
+// validator returns Validator of the nearest group of matched route that sets it, or of Echo.
+func (c *context) validator() Validator {
+	for g := matchedGroupKey.Value(c); g != nil; g = g.parent {
+		if g.Validator != nil {
+			return g.Validator
+		}
+	}
+	return c.echo.Validator
+}
+
+// renderer returns Renderer of the nearest group of matched route that sets it, or of Echo.
+func (c *context) renderer() Renderer {
+	for g := matchedGroupKey.Value(c); g != nil; g = g.parent {
+		if g.Renderer != nil {
+			return g.Renderer
+		}
+	}
+	return c.echo.Renderer
 }
 
 func (c *context) Echo() *Echo {
@@ -637,6 +759,12 @@ func (c *context) Logger() Logger {
 	if res != nil {
 		return res
 	}
+	if c.echo.RequestLogger != nil {
+		if res = c.echo.RequestLogger(c); res != nil {
+			c.logger = res
+			return res
+		}
+	}
 	return c.echo.Logger
 }
 
@@ -644,13 +772,32 @@ func (c *context) SetLogger(l Logger) {
 	c.logger = l
 }
 
+// onRequestEnd registers function to be called after the handler chain and error handler are done with the request.
+// Functions that did not run yet are called when context is reset or released, i.e. for contexts created with
+// `Echo.NewContext` or `Echo.AcquireContext`.
+func (c *context) onRequestEnd(fn func()) {
+	c.requestEnd = append(c.requestEnd, fn)
+}
+
+func (c *context) endRequest() {
+	for _, fn := range c.requestEnd {
+		fn()
+	}
+	c.requestEnd = nil
+}
+
 func (c *context) Reset(r *http.Request, w http.ResponseWriter) {
+	c.endRequest() // previous request of the context has ended
 	c.request = r
 	c.response.reset(w)
 	c.query = nil
 	c.handler = NotFoundHandler
 	c.store = nil
+	clear(c.values)
 	c.path = ""
+	c.route = nil
+	c.hostParamNames = nil
+	c.hostParamValues = nil
 	c.pnames = nil
 	c.logger = nil
 	// NOTE: Don't reset because it has to have length c.echo.maxParam (or bigger) at all times
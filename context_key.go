@@ -0,0 +1,143 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import "sync"
+
+// ContextKey is a typed key for request scoped values. Unlike `Context.Get` and `Context.Set` values are accessed
+// without type assertions and keys can not collide with each other even when they have the same name, as each key is
+// distinct instance. Values are stored in a slot reserved for the key in the context, slots are reused when context
+// is returned to the pool. Contexts wrapped by custom type have no slots, so values are stored with `Context.Set` by
+// key name and keys with the same name share the value.
+//
+// Values set with key are also readable with `Context.Get` by key name, unless a value with the same name is set
+// with `Context.Set`. Keys are meant to be created once, as package level variables.
+//
+// Example:
+//
+//	var userKey = echo.NewContextKey[*User]("user")
+//
+//	func auth(next echo.HandlerFunc) echo.HandlerFunc {
+//		return func(c echo.Context) error {
+//			userKey.Set(c, &User{Name: "jon"})
+//			return next(c)
+//		}
+//	}
+//
+//	func handler(c echo.Context) error {
+//		user, ok := userKey.Get(c)
+//		...
+//	}
+type ContextKey[T any] struct {
+	name  string
+	index int
+}
+
+// contextValue is value stored in context slot of ContextKey.
+type contextValue struct {
+	value interface{}
+	set   bool
+}
+
+// contextKeyNames holds names of all created context keys by their slot index.
+var contextKeyNames struct {
+	lock  sync.RWMutex
+	names []string
+}
+
+// NewContextKey creates new typed context key. Name is used to read the value with `Context.Get`.
+func NewContextKey[T any](name string) *ContextKey[T] {
+	contextKeyNames.lock.Lock()
+	defer contextKeyNames.lock.Unlock()
+
+	contextKeyNames.names = append(contextKeyNames.names, name)
+	return &ContextKey[T]{name: name, index: len(contextKeyNames.names) - 1}
+}
+
+// Name returns name of the key.
+func (k *ContextKey[T]) Name() string {
+	return k.name
+}
+
+// Get returns value of the key from context. Returns false when value is not set. Falls back to value set with
+// `Context.Set` by key name when it has type T. Values of other keys with the same name are never returned.
+func (k *ContextKey[T]) Get(c Context) (T, bool) {
+	ctx, ok := c.(*context)
+	if !ok {
+		value, ok := c.Get(k.name).(T)
+		return value, ok
+	}
+
+	ctx.lock.RLock()
+	var v contextValue
+	if k.index < len(ctx.values) {
+		v = ctx.values[k.index]
+	}
+	stored := ctx.store[k.name] // only value set with Context.Set, not slot of other key with the same name
+	ctx.lock.RUnlock()
+	if v.set {
+		value, _ := v.value.(T) // value is nil for nil interface types
+		return value, true
+	}
+	value, ok := stored.(T)
+	return value, ok
+}
+
+// Value returns value of the key from context or zero value of T when value is not set.
+func (k *ContextKey[T]) Value(c Context) T {
+	value, _ := k.Get(c)
+	return value
+}
+
+// Set sets value of the key in context. When context is not created by Echo (i.e. is wrapped by custom type) value
+// is stored with `Context.Set` by key name.
+func (k *ContextKey[T]) Set(c Context, value T) {
+	ctx, ok := c.(*context)
+	if !ok {
+		c.Set(k.name, value)
+		return
+	}
+
+	ctx.lock.Lock()
+	defer ctx.lock.Unlock()
+	if k.index >= len(ctx.values) {
+		contextKeyNames.lock.RLock()
+		size := len(contextKeyNames.names)
+		contextKeyNames.lock.RUnlock()
+
+		values := make([]contextValue, size)
+		copy(values, ctx.values)
+		ctx.values = values
+	}
+	ctx.values[k.index] = contextValue{value: value, set: true}
+}
+
+// Delete removes value of the key from context.
+func (k *ContextKey[T]) Delete(c Context) {
+	ctx, ok := c.(*context)
+	if !ok {
+		c.Set(k.name, nil)
+		return
+	}
+	ctx.lock.Lock()
+	defer ctx.lock.Unlock()
+	if k.index < len(ctx.values) {
+		ctx.values[k.index] = contextValue{}
+	}
+}
+
+// valueByName returns value set with ContextKey that has given name. Context lock must be held by the caller.
+func (c *context) valueByName(name string) (interface{}, bool) {
+	if len(c.values) == 0 {
+		return nil, false
+	}
+	contextKeyNames.lock.RLock()
+	defer contextKeyNames.lock.RUnlock()
+	for i, v := range c.values {
+		if v.set && contextKeyNames.names[i] == name {
+			return v.value, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testContextUser struct {
+	Name string
+}
+
+var (
+	testUserKey  = NewContextKey[*testContextUser]("user")
+	testCountKey = NewContextKey[int]("count")
+	testErrorKey = NewContextKey[error]("error")
+)
+
+func TestContextKey(t *testing.T) {
+	c := New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+
+	_, ok := testUserKey.Get(c)
+	assert.False(t, ok)
+	assert.Nil(t, testUserKey.Value(c))
+
+	user := &testContextUser{Name: "jon"}
+	testUserKey.Set(c, user)
+	testCountKey.Set(c, 2)
+
+	u, ok := testUserKey.Get(c)
+	assert.True(t, ok)
+	assert.Same(t, user, u)
+	assert.Equal(t, 2, testCountKey.Value(c))
+	assert.Equal(t, "user", testUserKey.Name())
+
+	testCountKey.Delete(c)
+	_, ok = testCountKey.Get(c)
+	assert.False(t, ok)
+}
+
+func TestContextKey_nilInterfaceValue(t *testing.T) {
+	c := New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+
+	testErrorKey.Set(c, nil)
+	err, ok := testErrorKey.Get(c)
+	assert.True(t, ok)
+	assert.Nil(t, err)
+
+	testErrorKey.Set(c, errors.New("x"))
+	assert.EqualError(t, testErrorKey.Value(c), "x")
+}
+
+func TestContextKey_sameNameDoesNotCollide(t *testing.T) {
+	c := New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+	otherCountKey := NewContextKey[int]("count")
+
+	testCountKey.Set(c, 1)
+	otherCountKey.Set(c, 2)
+
+	assert.Equal(t, 1, testCountKey.Value(c))
+	assert.Equal(t, 2, otherCountKey.Value(c))
+
+	otherCountKey.Delete(c)
+	_, ok := otherCountKey.Get(c)
+	assert.False(t, ok, "value of other key with the same name must not be returned")
+}
+
+func TestContextKey_oldAPI(t *testing.T) {
+	c := New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+
+	testCountKey.Set(c, 1)
+	assert.Equal(t, 1, c.Get("count"))
+	assert.Nil(t, c.Get("user"))
+
+	c.Set("count", 5) // value set with Context.Set takes precedence for Context.Get
+	assert.Equal(t, 5, c.Get("count"))
+	assert.Equal(t, 1, testCountKey.Value(c))
+
+	c.Set("user", &testContextUser{Name: "old"}) // typed key falls back to Context.Set value of same type
+	assert.Equal(t, "old", testUserKey.Value(c).Name)
+
+	c.Set("error", "not an error")
+	_, ok := testErrorKey.Get(c)
+	assert.False(t, ok)
+}
+
+type testWrappedContext struct {
+	Context
+}
+
+func TestContextKey_wrappedContext(t *testing.T) {
+	c := &testWrappedContext{New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())}
+
+	testCountKey.Set(c, 3)
+	assert.Equal(t, 3, testCountKey.Value(c))
+	assert.Equal(t, 3, c.Get("count"))
+
+	testCountKey.Delete(c)
+	_, ok := testCountKey.Get(c)
+	assert.False(t, ok)
+}
+
+func TestContextKey_resetAndAllocations(t *testing.T) {
+	e := New()
+	user := &testContextUser{Name: "jon"}
+	e.GET("/", func(c Context) error {
+		_, ok := testUserKey.Get(c)
+		assert.False(t, ok, "values are not kept between requests")
+		testUserKey.Set(c, user)
+		return nil
+	})
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	e.ServeHTTP(rec, req)
+
+	c := e.NewContext(req, rec)
+	testUserKey.Set(c, user)
+	allocs := testing.AllocsPerRun(100, func() {
+		c.Reset(req, rec)
+		testUserKey.Set(c, user)
+		testUserKey.Value(c)
+	})
+	assert.Equal(t, float64(0), allocs)
+}
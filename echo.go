@@ -43,15 +43,20 @@ import (
 	stdContext "context"
 	"crypto/tls"
 	"encoding/json"
+	"encoding/xml"
 	"errors"
 	"fmt"
 	stdLog "log"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"reflect"
 	"runtime"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	//"github.com/labstack/gommon/color"
@@ -66,7 +71,7 @@ import (
 //
 // Goroutine safety: Do not mutate Echo instance fields after server has started. Accessing these
 // fields from handlers/middlewares and changing field values at the same time leads to data-races.
-// Adding new routes after the server has been started is also not safe!
+// Adding new routes after the server has been started is also not safe unless it is done within `Echo.UpdateRoutes`!
 type Echo struct {
 	filesystem
 	common
@@ -82,7 +87,19 @@ type Echo struct {
 	maxParam      *int
 	router        *Router
 	routers       map[string]*Router
-	pool          sync.Pool
+	hosts         *hostPatterns
+	// routing is snapshot of routers that ServeHTTP serves requests with. Before routes are updated with UpdateRoutes it
+	// points to router, routers, hosts and maxParam fields.
+	routing   atomic.Pointer[routing]
+	routingMu sync.Mutex
+	// groupRoutes holds routes of groups before they were changed by UpdateRoutes that is in progress, so they can be
+	// restored when the update is discarded. Nil when update is not in progress.
+	groupRoutes map[*Group][]*Route
+	pool        sync.Pool
+	webSockets  webSocketTracker
+	// routeConflicts are conflicts detected when routes were added.
+	routeConflicts []RouteConflict
+	conflictsMu    sync.Mutex
 
 	StdLogger        *stdLog.Logger
 	Server           *http.Server
@@ -93,12 +110,24 @@ type Echo struct {
 	HTTPErrorHandler HTTPErrorHandler
 	Binder           Binder
 	JSONSerializer   JSONSerializer
+	Codecs           *CodecRegistry
+	ErrorRegistry    *ErrorRegistry
+	ParamConstraints *ParamConstraintRegistry
 	Validator        Validator
 	Renderer         Renderer
 	Logger           Logger
 	IPExtractor      IPExtractor
 	ListenerNetwork  string
 
+	// RequestLogger creates logger for a request, i.e. `DefaultRequestLogger`. The logger is returned by
+	// `Context.Logger` and is carried by request `context.Context` for the route handler (see `LoggerFromContext`).
+	// When nil, `Context.Logger` returns `Echo.Logger`.
+	RequestLogger func(c Context) Logger
+
+	// RouteConflictPolicy defines what happens when added route conflicts with registered routes. Defaults to
+	// RouteConflictIgnore.
+	RouteConflictPolicy RouteConflictPolicy
+
 	// OnAddRouteHandler is called when Echo adds new route to specific host router.
 	OnAddRouteHandler func(host string, route Route, handler HandlerFunc, middleware []MiddlewareFunc)
 	DisableHTTP2      bool
@@ -186,6 +215,7 @@ const (
 	MIMETextPlainCharsetUTF8             = MIMETextPlain + "; " + charsetUTF8
 	MIMEMultipartForm                    = "multipart/form-data"
 	MIMEOctetStream                      = "application/octet-stream"
+	MIMETextEventStream                  = "text/event-stream"
 )
 
 const (
@@ -216,6 +246,7 @@ const (
 	HeaderSetCookie           = "Set-Cookie"
 	HeaderIfModifiedSince     = "If-Modified-Since"
 	HeaderLastModified        = "Last-Modified"
+	HeaderLastEventID         = "Last-Event-ID"
 	HeaderLocation            = "Location"
 	HeaderRetryAfter          = "Retry-After"
 	HeaderUpgrade             = "Upgrade"
@@ -235,6 +266,7 @@ const (
 	HeaderOrigin              = "Origin"
 	HeaderCacheControl        = "Cache-Control"
 	HeaderConnection          = "Connection"
+	HeaderXAccelBuffering     = "X-Accel-Buffering"
 
 	// Access control
 	HeaderAccessControlRequestMethod    = "Access-Control-Request-Method"
@@ -246,6 +278,13 @@ const (
 	HeaderAccessControlExposeHeaders    = "Access-Control-Expose-Headers"
 	HeaderAccessControlMaxAge           = "Access-Control-Max-Age"
 
+	// WebSocket
+	HeaderSecWebSocketKey        = "Sec-WebSocket-Key"
+	HeaderSecWebSocketAccept     = "Sec-WebSocket-Accept"
+	HeaderSecWebSocketVersion    = "Sec-WebSocket-Version"
+	HeaderSecWebSocketProtocol   = "Sec-WebSocket-Protocol"
+	HeaderSecWebSocketExtensions = "Sec-WebSocket-Extensions"
+
 	// Security
 	HeaderStrictTransportSecurity         = "Strict-Transport-Security"
 	HeaderXContentTypeOptions             = "X-Content-Type-Options"
@@ -333,6 +372,7 @@ var (
 
 	ErrValidatorNotRegistered = errors.New("validator not registered")
 	ErrRendererNotRegistered  = errors.New("renderer not registered")
+	ErrCodecNotRegistered     = errors.New("codec not registered")
 	ErrInvalidRedirectCode    = errors.New("invalid redirect status code")
 	ErrCookieNotFound         = errors.New("cookie not found")
 	ErrInvalidCertOrKeyType   = errors.New("invalid cert or key type, must be string or []byte")
@@ -350,7 +390,7 @@ var NotFoundHandler = func(c Context) error {
 var MethodNotAllowedHandler = func(c Context) error {
 	// See RFC 7231 section 7.4.1: An origin server MUST generate an Allow field in a 405 (Method Not Allowed)
 	// response and MAY do so in any other response. For disabled resources an empty Allow header may be returned
-	routerAllowMethods, ok := c.Get(ContextKeyHeaderAllow).(string)
+	routerAllowMethods, ok := headerAllowKey.Get(c)
 	if ok && routerAllowMethods != "" {
 		c.Response().Header().Set(HeaderAllow, routerAllowMethods)
 	}
@@ -376,6 +416,9 @@ func New() (e *Echo) {
 	e.HTTPErrorHandler = e.DefaultHTTPErrorHandler
 	e.Binder = &DefaultBinder{}
 	e.JSONSerializer = &DefaultJSONSerializer{}
+	e.registerDefaultCodecs()
+	e.ErrorRegistry = NewErrorRegistry()
+	e.ParamConstraints = NewParamConstraintRegistry()
 	//e.Logger.SetLevel(log.ERROR)
 	//e.StdLogger = stdLog.New(e.Logger.Output(), e.Logger.Prefix()+": ", 0)
 	e.SetLogger(new(SlogLogger))
@@ -384,6 +427,8 @@ func New() (e *Echo) {
 	}
 	e.router = NewRouter(e)
 	e.routers = map[string]*Router{}
+	e.hosts = &hostPatterns{}
+	e.routing.Store(&routing{router: e.router, routers: e.routers, hosts: e.hosts, maxParam: e.maxParam})
 	return
 }
 
@@ -394,23 +439,28 @@ func (e *Echo) NewContext(r *http.Request, w http.ResponseWriter) Context {
 		response: NewResponse(w, e),
 		store:    make(Map),
 		echo:     e,
-		pvalues:  make([]string, *e.maxParam),
+		pvalues:  make([]string, *e.routing.Load().maxParam),
 		handler:  NotFoundHandler,
 	}
 }
 
 // Router returns the default router.
 func (e *Echo) Router() *Router {
-	return e.router
+	return e.routing.Load().router
 }
 
 // Routers returns the map of host => router.
 func (e *Echo) Routers() map[string]*Router {
-	return e.routers
+	return e.routing.Load().routers
 }
 
 // DefaultHTTPErrorHandler is the default HTTP error handler. It sends a JSON response
-// with status code.
+// with status code. For string and error messages the response media type is negotiated from the request `Accept`
+// header among media types with registered Codec, falling back to JSON when none of them is acceptable.
+// `BindingError` results its status code with field name in the message. ProblemDetails (returned as error or as
+// HTTPError message) are sent as `application/problem+json`. To send all errors as problem details use
+// `Echo.ProblemDetailsHTTPErrorHandler`. Errors that are not HTTPError are converted to response with
+// `Echo.ErrorRegistry` mappings and result 500 Internal Server Error when no mapping matches.
 //
 // NOTE: In case errors happens in middleware call-chain that is returning from handler (which did not return an error).
 // When handler has already sent response (ala c.JSON()) and there is error in middleware that is returning from
@@ -429,6 +479,15 @@ func (e *Echo) DefaultHTTPErrorHandler(err error, c Context) {
 				he = herr
 			}
 		}
+	} else if be, ok := err.(*BindingError); ok {
+		he = &HTTPError{Code: be.Code, Message: fmt.Sprintf("%v, field=%s", be.Message, be.Field), Internal: be}
+	} else if problem, ok := err.(*ProblemDetails); ok {
+		he = &HTTPError{Code: problem.Status, Message: problem}
+		if problem.Status == 0 {
+			he.Code = http.StatusInternalServerError
+		}
+	} else if mapped, ok := e.mapError(c, err); ok {
+		he = mapped
 	} else {
 		he = &HTTPError{
 			Code:    http.StatusInternalServerError,
@@ -443,21 +502,23 @@ func (e *Echo) DefaultHTTPErrorHandler(err error, c Context) {
 	switch m := he.Message.(type) {
 	case string:
 		if e.Debug {
-			message = Map{"message": m, "error": err.Error()}
+			message = errorMessage{"message": m, "error": err.Error()}
 		} else {
-			message = Map{"message": m}
+			message = errorMessage{"message": m}
 		}
+	case *ProblemDetails:
+		message = m.withDefaultStatus(code)
 	case json.Marshaler:
 		// do nothing - this type knows how to format itself to JSON
 	case error:
-		message = Map{"message": m.Error()}
+		message = errorMessage{"message": m.Error()}
 	}
 
 	// Send response
 	if c.Request().Method == http.MethodHead { // Issue #608
 		err = c.NoContent(he.Code)
 	} else {
-		err = c.JSON(code, message)
+		err = writeErrorMessage(c, code, message)
 	}
 	if err != nil {
 		//e.Logger.Error(err)
@@ -465,6 +526,42 @@ func (e *Echo) DefaultHTTPErrorHandler(err error, c Context) {
 	}
 }
 
+// errorMessage is the response body DefaultHTTPErrorHandler sends for string and error messages. It is encoded as
+// JSON object and as `<error>` element with child element for each key when XML is negotiated.
+type errorMessage map[string]interface{}
+
+// MarshalXML encodes message keys as child elements of `<error>` element in sorted order.
+func (m errorMessage) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	start = xml.StartElement{Name: xml.Name{Local: "error"}}
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if err := enc.EncodeElement(m[k], xml.StartElement{Name: xml.Name{Local: k}}); err != nil {
+			return err
+		}
+	}
+	return enc.EncodeToken(start.End())
+}
+
+func writeErrorMessage(c Context, code int, message interface{}) error {
+	if problem, ok := message.(*ProblemDetails); ok {
+		return writeProblemDetails(c, code, problem)
+	}
+	if _, ok := message.(errorMessage); ok && c.Request().Header.Get(HeaderAccept) != "" {
+		if err := c.Negotiate(code, message); err != ErrNotAcceptable {
+			return err
+		}
+	}
+	return c.JSON(code, message)
+}
+
 // Pre adds middleware to the chain which is run before router.
 func (e *Echo) Pre(middleware ...MiddlewareFunc) {
 	e.premiddleware = append(e.premiddleware, middleware...)
@@ -574,14 +671,58 @@ func (e *Echo) File(path, file string, m ...MiddlewareFunc) *Route {
 	return e.file(path, file, e.GET, m...)
 }
 
-func (e *Echo) add(host, method, path string, handler HandlerFunc, middlewares ...MiddlewareFunc) *Route {
+func (e *Echo) add(host string, g *Group, method, path string, handler HandlerFunc, middlewares ...MiddlewareFunc) *Route {
+	if e.RouteConflictPolicy == RouteConflictIgnore {
+		return e.register(host, g, method, path, handler, middlewares...)
+	}
+	if conflicts := e.checkRoute(host, method, path); len(conflicts) > 0 {
+		switch e.RouteConflictPolicy {
+		case RouteConflictPanic:
+			panic((&RouteConflictError{Conflicts: conflicts}).Error())
+		case RouteConflictLog:
+			if e.Logger != nil {
+				for _, c := range conflicts {
+					e.Logger.Warn("route conflict", "kind", c.Kind, "host", c.Host, "method", c.Method,
+						"path", c.Path, "conflicts_with", c.ConflictsWith, "message", c.Message)
+				}
+			}
+		}
+		e.recordRouteConflicts(conflicts)
+	}
+	return e.register(host, g, method, path, handler, middlewares...)
+}
+
+// addE adds route to host router unless it conflicts with registered routes.
+func (e *Echo) addE(host string, g *Group, method, path string, handler HandlerFunc, middlewares ...MiddlewareFunc) (*Route, error) {
+	if conflicts := e.checkRoute(host, method, path); len(conflicts) > 0 {
+		return nil, &RouteConflictError{Conflicts: conflicts}
+	}
+	return e.register(host, g, method, path, handler, middlewares...), nil
+}
+
+// register adds route to host router. g is group the route is added with, nil for routes added with Echo.
+func (e *Echo) register(host string, g *Group, method, path string, handler HandlerFunc, middlewares ...MiddlewareFunc) *Route {
 	router := e.findRouter(host)
 	//FIXME: when handler+middleware are both nil ... make it behave like handler removal
 	name := handlerName(handler)
-	route := router.add(method, path, name, func(c Context) error {
-		h := applyMiddleware(handler, middlewares...)
+	route := router.addRoute(method, path, name, g, func(c Context) error {
+		h := handler
+		if e.RequestLogger != nil {
+			// logger is created after group and route middleware, i.e. RequestID, are executed
+			h = withRequestLogger(h)
+		}
+		h = applyMiddleware(h, middlewares...)
 		return h(c)
 	})
+	if len(middlewares) > 0 {
+		names := make([]string, 0, len(middlewares))
+		for _, m := range middlewares {
+			names = append(names, runtime.FuncForPC(reflect.ValueOf(m).Pointer()).Name())
+		}
+		route.updateInfo(func(info *RouteInfo) {
+			info.Middleware = names
+		})
+	}
 
 	if e.OnAddRouteHandler != nil {
 		e.OnAddRouteHandler(host, *route, handler, middlewares)
@@ -593,11 +734,31 @@ func (e *Echo) add(host, method, path string, handler HandlerFunc, middlewares .
 // Add registers a new route for an HTTP method and path with matching handler
 // in the router with optional route-level middleware.
 func (e *Echo) Add(method, path string, handler HandlerFunc, middleware ...MiddlewareFunc) *Route {
-	return e.add("", method, path, handler, middleware...)
+	return e.add("", nil, method, path, handler, middleware...)
+}
+
+// AddE registers a new route for an HTTP method and path like Add but returns `*RouteConflictError` instead of
+// registering the route when it conflicts with registered routes, regardless of RouteConflictPolicy.
+func (e *Echo) AddE(method, path string, handler HandlerFunc, middleware ...MiddlewareFunc) (*Route, error) {
+	return e.addE("", nil, method, path, handler, middleware...)
 }
 
 // Host creates a new router group for the provided host and optional host-level middleware.
+//
+// Host can be a pattern where whole labels are `*` wildcards or `{name}` parameters, i.e. `*.customers.example.com`
+// or `{tenant}.example.com`. Wildcards and parameters match exactly one label and parameter values are available with
+// `Context.HostParam`. Pattern without port matches requests to any port, exact host matches only requests with the
+// same `Host` header value. Literal labels of patterns are matched case-insensitively and parameter values are
+// captured as sent, while exact hosts are matched case-sensitively. Exact hosts take precedence over patterns and
+// patterns with more literal labels take precedence over other patterns. Panics when pattern is not valid.
 func (e *Echo) Host(name string, m ...MiddlewareFunc) (g *Group) {
+	if isHostPattern(name) {
+		p, err := parseHostPattern(name)
+		if err != nil {
+			panic(err.Error())
+		}
+		e.hosts.add(p)
+	}
 	e.routers[name] = NewRouter(e)
 	g = &Group{host: name, echo: e}
 	g.Use(m...)
@@ -611,6 +772,67 @@ func (e *Echo) Group(prefix string, m ...MiddlewareFunc) (g *Group) {
 	return
 }
 
+// Mount delegates requests with path prefix to sub, which is usually another Echo instance with its own
+// HTTPErrorHandler, Binder, Renderer, JSONSerializer, Logger and middleware. Prefix is stripped from request path
+// (and from raw path when request path contains escaped characters) before request is passed to sub. Routes of
+// mounted Echo instance are included in `Routes` and `Reverse` of Echo.
+//
+// Mount is registered as `RouteNotFound` routes for prefix and `prefix/*`, so it is checked for conflicts, reported to
+// `OnAddRouteHandler` and listed in `Routes` like other routes. Routes registered with Echo under the prefix take
+// precedence over sub. Prefix can contain path params.
+//
+// Example:
+//
+//	admin := echo.New()
+//	admin.HTTPErrorHandler = adminErrorHandler
+//	admin.GET("/users", listUsers)
+//	e.Mount("/admin", admin) // serves `/admin/users`
+//	e.Mount("/metrics", promhttp.Handler())
+func (e *Echo) Mount(prefix string, sub http.Handler) {
+	mountTo(e.findRouter(""), e.RouteNotFound, prefix, prefix, sub)
+}
+
+// mountTo registers handler passing requests to sub with add and records mounted Echo instance to router. Path is
+// prefix as given to add and fullPrefix is path that prefix results to in router.
+func mountTo(router *Router, add func(string, HandlerFunc, ...MiddlewareFunc) *Route, prefix, fullPrefix string, sub http.Handler) {
+	prefix = strings.TrimSuffix(prefix, "/")
+	h := mountHandler(sub)
+
+	// mounted handler is registered as "not found" route so it serves all methods, including the ones Echo does not
+	// know about.
+	add(prefix, h)
+	route := add(prefix+"/*", h)
+	if sub, ok := sub.(*Echo); ok {
+		router.mounts = append(router.mounts, mount{prefix: strings.TrimSuffix(fullPrefix, "/"), echo: sub, route: route})
+	}
+}
+
+// mountHandler passes request to sub with path that is left after mount prefix.
+func mountHandler(sub http.Handler) HandlerFunc {
+	return func(c Context) error {
+		req := c.Request()
+		rest := "/" + c.Param("*") // value of `*` is part of path returned by GetPath
+
+		u := *req.URL
+		if u.RawPath != "" {
+			u.RawPath = rest
+			if p, err := url.PathUnescape(rest); err == nil {
+				u.Path = p
+			} else {
+				u.Path, u.RawPath = rest, ""
+			}
+		} else {
+			u.Path = rest
+		}
+		r2 := new(http.Request)
+		*r2 = *req
+		r2.URL = &u
+
+		sub.ServeHTTP(c.Response(), r2)
+		return nil
+	}
+}
+
 // URI generates an URI from handler.
 func (e *Echo) URI(handler HandlerFunc, params ...interface{}) string {
 	name := handlerName(handler)
@@ -624,13 +846,74 @@ func (e *Echo) URL(h HandlerFunc, params ...interface{}) string {
 
 // Reverse generates a URL from route name and provided parameters.
 func (e *Echo) Reverse(name string, params ...interface{}) string {
-	return e.router.Reverse(name, params...)
+	return e.routing.Load().router.Reverse(name, params...)
 }
 
 // Routes returns the registered routes for default router.
 // In case when Echo serves multiple hosts/domains use `e.Routers()["domain2.site"].Routes()` to get specific host routes.
 func (e *Echo) Routes() []*Route {
-	return e.router.Routes()
+	return e.routing.Load().router.Routes()
+}
+
+// RemoveRoute removes route registered for method and path from the default router. Returns false when there is no
+// such route. To remove routes while server is running call it within UpdateRoutes.
+func (e *Echo) RemoveRoute(method, path string) bool {
+	return e.findRouter("").Remove(method, path)
+}
+
+// UpdateRoutes changes routes while server is running. Routes added, replaced (added again for the same method and
+// path) and removed in fn with Echo and Group methods are applied to copies of routers, and requests are served with
+// the new routers after fn returns. Requests that are already being routed keep using previous routers. When fn
+// returns an error or panics, all changes are discarded. To replace route without it being reported as duplicate by
+// `RouteConflicts`, remove it before adding it again. `Route.Info` of routes that are removed, or added by discarded
+// changes, is released.
+//
+// Calls to UpdateRoutes are serialized. Routers returned by `Router` and `Routers` must not be modified in fn.
+//
+// Example:
+//
+//	err := e.UpdateRoutes(func() error {
+//		e.RemoveRoute(http.MethodGet, "/legacy")
+//		plugins := e.Group("/plugins/reports", pluginAuth)
+//		plugins.GET("/daily", dailyReport)
+//		return nil
+//	})
+func (e *Echo) UpdateRoutes(fn func() error) error {
+	e.routingMu.Lock()
+	defer e.routingMu.Unlock()
+
+	current := e.routing.Load()
+	maxParam := *current.maxParam
+	next := &routing{
+		router:   current.router.clone(),
+		routers:  make(map[string]*Router, len(current.routers)),
+		hosts:    current.hosts.clone(),
+		maxParam: &maxParam,
+	}
+	for host, r := range current.routers {
+		next.routers[host] = r.clone()
+	}
+
+	e.router, e.routers, e.hosts, e.maxParam = next.router, next.routers, next.hosts, next.maxParam
+	e.groupRoutes = map[*Group][]*Route{}
+	published := false
+	defer func() {
+		if !published {
+			e.router, e.routers, e.hosts, e.maxParam = current.router, current.routers, current.hosts, current.maxParam
+			for g, routes := range e.groupRoutes {
+				g.routes = routes
+			}
+			releaseRouteInfo(next, current)
+		}
+		e.groupRoutes = nil
+	}()
+	if err := fn(); err != nil {
+		return err
+	}
+	e.routing.Store(next)
+	published = true
+	releaseRouteInfo(current, next)
+	return nil
 }
 
 // AcquireContext returns an empty `Context` instance from the pool.
@@ -640,8 +923,12 @@ func (e *Echo) AcquireContext() Context {
 }
 
 // ReleaseContext returns the `Context` instance back to the pool.
-// You must call it after `AcquireContext()`.
+// You must call it after `AcquireContext()`. Cleanup of the request, i.e. removal of spooled multipart files, is done
+// before context is returned to the pool.
 func (e *Echo) ReleaseContext(c Context) {
+	if ctx, ok := c.(*context); ok {
+		ctx.endRequest()
+	}
 	e.pool.Put(c)
 }
 
@@ -650,16 +937,36 @@ func (e *Echo) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// Acquire context
 	c := e.pool.Get().(*context)
 	c.Reset(r, w)
+	defer func() {
+		// functions registered for request end (i.e. removal of spooled multipart files) are executed also when
+		// handler or error handler panics.
+		c.endRequest()
+		// Release context
+		e.pool.Put(c)
+	}()
 	var h HandlerFunc
 
+	rt := e.routing.Load()
+	if len(c.pvalues) < *rt.maxParam {
+		// routes with more path params have been added since context was created
+		c.pvalues = make([]string, *rt.maxParam)
+	}
+
 	if e.premiddleware == nil {
-		e.findRouter(r.Host).Find(r.Method, GetPath(r), c)
+		rt.hostRouter(r.Host, c).Find(r.Method, GetPath(r), c)
 		h = c.Handler()
+		if e.RequestLogger != nil && c.route == nil {
+			// route handlers add request logger after route middleware, see register
+			h = withRequestLogger(h)
+		}
 		h = applyMiddleware(h, e.middleware...)
 	} else {
 		h = func(c Context) error {
-			e.findRouter(r.Host).Find(r.Method, GetPath(r), c)
+			rt.hostRouter(r.Host, c.(*context)).Find(r.Method, GetPath(r), c)
 			h := c.Handler()
+			if e.RequestLogger != nil && c.Route() == nil {
+				h = withRequestLogger(h)
+			}
 			h = applyMiddleware(h, e.middleware...)
 			return h(c)
 		}
@@ -668,11 +975,8 @@ func (e *Echo) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	// Execute chain
 	if err := h(c); err != nil {
-		e.HTTPErrorHandler(err, c)
+		c.httpErrorHandler()(err, c)
 	}
-
-	// Release context
-	e.pool.Put(c)
 }
 
 // Start starts an HTTP server.
@@ -774,6 +1078,7 @@ func (e *Echo) StartServer(s *http.Server) (err error) {
 
 func (e *Echo) configureServer(s *http.Server) error {
 	// Setup
+	e.webSockets.start()
 	//e.colorer.SetOutput(e.Logger.Output())
 	s.ErrorLog = e.StdLogger
 	s.Handler = e
@@ -842,6 +1147,7 @@ func (e *Echo) StartH2CServer(address string, h2s *http2.Server) error {
 	//e.colorer.SetOutput(e.Logger.Output())
 	s.ErrorLog = e.StdLogger
 	s.Handler = h2c.NewHandler(e, h2s)
+	e.webSockets.start()
 	//if e.Debug {
 	//	e.Logger.SetLevel(log.DEBUG)
 	//}
@@ -871,6 +1177,7 @@ func (e *Echo) StartH2CServer(address string, h2s *http2.Server) error {
 func (e *Echo) Close() error {
 	e.startupMutex.Lock()
 	defer e.startupMutex.Unlock()
+	e.webSockets.close()
 	if err := e.TLSServer.Close(); err != nil {
 		return err
 	}
@@ -878,10 +1185,12 @@ func (e *Echo) Close() error {
 }
 
 // Shutdown stops the server gracefully.
-// It internally calls `http.Server#Shutdown()`.
+// It internally calls `http.Server#Shutdown()`. Open WebSocket connections are sent "going away" close frame as
+// `http.Server` does not track hijacked connections.
 func (e *Echo) Shutdown(ctx stdContext.Context) error {
 	e.startupMutex.Lock()
 	defer e.startupMutex.Unlock()
+	e.webSockets.shutdown()
 	if err := e.TLSServer.Shutdown(ctx); err != nil {
 		return err
 	}
@@ -959,6 +1268,7 @@ func GetPath(r *http.Request) string {
 	return path
 }
 
+// findRouter returns router for host that routes are added to.
 func (e *Echo) findRouter(host string) *Router {
 	if len(e.routers) > 0 {
 		if r, ok := e.routers[host]; ok {
@@ -968,6 +1278,41 @@ func (e *Echo) findRouter(host string) *Router {
 	return e.router
 }
 
+// routing is snapshot of routers requests are served with.
+type routing struct {
+	router   *Router
+	routers  map[string]*Router
+	hosts    *hostPatterns
+	maxParam *int
+}
+
+// eachRouter calls fn for default router and routers of hosts.
+func (rt *routing) eachRouter(fn func(r *Router)) {
+	fn(rt.router)
+	for _, r := range rt.routers {
+		fn(r)
+	}
+}
+
+// hostRouter returns router for request host and sets host parameters to the context. Exact host has precedence
+// over host patterns. Default router is returned when no host matches.
+func (rt *routing) hostRouter(host string, c *context) *Router {
+	if len(rt.routers) == 0 {
+		return rt.router
+	}
+	if r, ok := rt.routers[host]; ok {
+		return r
+	}
+	for _, p := range rt.hosts.list {
+		if values, ok := p.match(host); ok {
+			c.hostParamNames = p.names
+			c.hostParamValues = values
+			return rt.routers[p.pattern]
+		}
+	}
+	return rt.router
+}
+
 func handlerName(h HandlerFunc) string {
 	t := reflect.ValueOf(h).Type()
 	if t.Kind() == reflect.Func {
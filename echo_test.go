@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net"
 	"net/http"
 	"net/http/httptest"
@@ -1591,6 +1592,470 @@ func TestEcho_OnAddRouteHandler(t *testing.T) {
 	assert.Len(t, added[1].middleware, 1)
 }
 
+func TestEcho_UpdateRoutes(t *testing.T) {
+	e := New()
+	e.GET("/status", func(c Context) error {
+		return c.String(http.StatusOK, "v1")
+	})
+
+	err := e.UpdateRoutes(func() error {
+		e.GET("/status", func(c Context) error {
+			return c.String(http.StatusOK, "v2")
+		})
+		e.GET("/plugins/:plugin/:action/:id", func(c Context) error {
+			return c.String(http.StatusOK, c.Param("plugin")+":"+c.Param("action")+":"+c.Param("id"))
+		})
+		e.Host("api.example.com").GET("/", func(c Context) error {
+			return c.String(http.StatusOK, "api")
+		})
+
+		// requests are served with previous routers until fn returns
+		code, body := request(http.MethodGet, "/status", e)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "v1", body)
+		code, _ = request(http.MethodGet, "/plugins/reports/run/1", e)
+		assert.Equal(t, http.StatusNotFound, code)
+		assert.Len(t, e.Routes(), 1)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	code, body := request(http.MethodGet, "/status", e)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "v2", body)
+
+	code, body = request(http.MethodGet, "/plugins/reports/run/1", e)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "reports:run:1", body)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Host = "api.example.com"
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, "api", rec.Body.String())
+	assert.Contains(t, e.Routers(), "api.example.com")
+	assert.Len(t, e.Routes(), 2)
+}
+
+func TestEcho_UpdateRoutesDiscardsChanges(t *testing.T) {
+	e := New()
+	e.GET("/a", handlerFunc)
+
+	err := e.UpdateRoutes(func() error {
+		e.RemoveRoute(http.MethodGet, "/a")
+		e.GET("/b", handlerFunc)
+		return errors.New("plugin failed")
+	})
+	assert.EqualError(t, err, "plugin failed")
+
+	assert.Panics(t, func() {
+		_ = e.UpdateRoutes(func() error {
+			e.GET("/c", handlerFunc)
+			panic("plugin panicked")
+		})
+	})
+
+	code, _ := request(http.MethodGet, "/a", e)
+	assert.Equal(t, http.StatusOK, code)
+	code, _ = request(http.MethodGet, "/b", e)
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _ = request(http.MethodGet, "/c", e)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	// changes after discarded updates are made to the routers that serve requests
+	e.GET("/d", handlerFunc)
+	code, _ = request(http.MethodGet, "/d", e)
+	assert.Equal(t, http.StatusOK, code)
+}
+
+func TestEcho_UpdateRoutesDiscardsGroupChanges(t *testing.T) {
+	e := New()
+	api := e.Group("/api")
+	users := api.GET("/users", handlerFunc)
+	admin := e.Group("/admin")
+	settings := admin.GET("/settings", handlerFunc)
+
+	err := e.UpdateRoutes(func() error {
+		api.GET("/posts", handlerFunc)
+		admin.Remove()
+		return errors.New("plugin failed")
+	})
+	assert.EqualError(t, err, "plugin failed")
+	assert.Equal(t, []*Route{users}, api.routes)
+	assert.Equal(t, []*Route{settings}, admin.routes)
+
+	assert.Panics(t, func() {
+		_ = e.UpdateRoutes(func() error {
+			api.Group("/v2").GET("/posts", handlerFunc)
+			panic("plugin panicked")
+		})
+	})
+	assert.Equal(t, []*Route{users}, api.routes)
+
+	// group routes are consistent with routers after discarded updates
+	admin.Remove()
+	code, _ := request(http.MethodGet, "/admin/settings", e)
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _ = request(http.MethodGet, "/api/users", e)
+	assert.Equal(t, http.StatusOK, code)
+}
+
+func TestEcho_UpdateRoutesConcurrentRequests(t *testing.T) {
+	e := New()
+	e.GET("/ping", func(c Context) error {
+		return c.String(http.StatusOK, "pong")
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			path := fmt.Sprintf("/dynamic/%d/:a/:b/:c", i)
+			err := e.UpdateRoutes(func() error {
+				e.GET(path, func(c Context) error {
+					return c.String(http.StatusOK, c.Param("c"))
+				})
+				e.RemoveRoute(http.MethodGet, fmt.Sprintf("/dynamic/%d/:a/:b/:c", i-1))
+				return nil
+			})
+			assert.NoError(t, err)
+		}
+	}()
+
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		default:
+		}
+		code, body := request(http.MethodGet, "/ping", e)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "pong", body)
+		_ = e.Reverse("missing")
+	}
+
+	code, body := request(http.MethodGet, "/dynamic/19/a/b/c", e)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "c", body)
+	code, _ = request(http.MethodGet, "/dynamic/18/a/b/c", e)
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestEcho_RemoveRoute(t *testing.T) {
+	e := New()
+	e.GET("/users/:id", handlerFunc)
+	e.POST("/users/:id", handlerFunc)
+	e.GET("/users/:id/files", handlerFunc)
+
+	assert.True(t, e.RemoveRoute(http.MethodPost, "/users/:id"))
+	assert.False(t, e.RemoveRoute(http.MethodPost, "/users/:id"))
+
+	req := httptest.NewRequest(http.MethodPut, "/users/1", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "OPTIONS, GET", rec.Header().Get(HeaderAllow))
+
+	// param node acts as leaf again after its only child route is removed
+	code, _ := request(http.MethodGet, "/users/1/photos", e)
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.True(t, e.RemoveRoute(http.MethodGet, "/users/:id/files"))
+	code, _ = request(http.MethodGet, "/users/1/photos", e)
+	assert.Equal(t, http.StatusOK, code)
+
+	assert.True(t, e.RemoveRoute(http.MethodGet, "/users/:id"))
+	code, _ = request(http.MethodGet, "/users/1", e)
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Len(t, e.Routes(), 0)
+}
+
+func TestEcho_RouteConflictPolicy(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		whenPolicy  RouteConflictPolicy
+		expectPanic string
+		expectLog   string
+		expectBody  string
+
+		expectConflicts int
+	}{
+		{
+			name:       "ignore replaces route",
+			whenPolicy: RouteConflictIgnore,
+			expectBody: "second",
+		},
+		{
+			name:            "log replaces route",
+			whenPolicy:      RouteConflictLog,
+			expectLog:       `level=WARN msg="route conflict" kind=duplicate host="" method=GET path=/users conflicts_with=/users`,
+			expectBody:      "second",
+			expectConflicts: 1,
+		},
+		{
+			name:        "panic keeps route",
+			whenPolicy:  RouteConflictPanic,
+			expectPanic: "echo: route conflict: GET /users: route replaces already registered route GET /users",
+			expectBody:  "first",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			e := New()
+			e.SetLogger(&SlogLogger{Logger: slog.New(slog.NewTextHandler(buf, nil))})
+			e.RouteConflictPolicy = tc.whenPolicy
+
+			e.GET("/users", func(c Context) error { return c.String(http.StatusOK, "first") })
+			add := func() {
+				e.GET("/users", func(c Context) error { return c.String(http.StatusOK, "second") })
+			}
+			if tc.expectPanic != "" {
+				assert.PanicsWithValue(t, tc.expectPanic, add)
+				assert.Len(t, e.RouteConflicts(), 0)
+			} else {
+				add()
+				assert.Len(t, e.RouteConflicts(), tc.expectConflicts)
+			}
+			if tc.expectLog != "" {
+				assert.Contains(t, buf.String(), tc.expectLog)
+			} else {
+				assert.Empty(t, buf.String())
+			}
+
+			_, body := request(http.MethodGet, "/users", e)
+			assert.Equal(t, tc.expectBody, body)
+		})
+	}
+}
+
+func TestEcho_RouteConflictEscapedColon(t *testing.T) {
+	for _, policy := range []RouteConflictPolicy{RouteConflictIgnore, RouteConflictLog, RouteConflictPanic} {
+		e := New()
+		e.RouteConflictPolicy = policy
+		e.GET("/a/:id", func(c Context) error { return c.String(http.StatusOK, "id="+c.Param("id")) })
+		assert.NotPanics(t, func() {
+			e.GET("/a/\\:foo", func(c Context) error { return c.String(http.StatusOK, "foo") })
+		})
+		assert.Len(t, e.RouteConflicts(), 0)
+
+		_, body := request(http.MethodGet, "/a/1", e)
+		assert.Equal(t, "id=1", body)
+	}
+}
+
+func TestEcho_AddE(t *testing.T) {
+	e := New()
+	e.GET("/users/:id", handlerFunc)
+
+	route, err := e.AddE(http.MethodGet, "/users/:name/posts", handlerFunc)
+	assert.Nil(t, route)
+	var conflictErr *RouteConflictError
+	assert.ErrorAs(t, err, &conflictErr)
+	assert.Equal(t, []RouteConflict{
+		{
+			Kind:          RouteConflictParamName,
+			Method:        http.MethodGet,
+			Path:          "/users/:name/posts",
+			ConflictsWith: "/users/:id",
+			Message:       `path param "name" is named "id" in already registered route`,
+		},
+	}, conflictErr.Conflicts)
+	assert.Len(t, e.Routes(), 1)
+	assert.Len(t, e.RouteConflicts(), 0) // rejected routes are not reported
+
+	route, err = e.AddE(http.MethodGet, "/users/:id/posts", handlerFunc)
+	assert.NoError(t, err)
+	assert.Equal(t, "/users/:id/posts", route.Path)
+	assert.Len(t, e.Routes(), 2)
+}
+
+func TestEcho_RouteConflicts(t *testing.T) {
+	e := New()
+	e.SetLogger(&SlogLogger{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+	e.RouteConflictPolicy = RouteConflictLog
+	e.GET("/files/*/raw", handlerFunc)
+	e.GET("/users", handlerFunc)
+	api := e.Host("api.example.com")
+	api.GET("/users", handlerFunc)
+	api.GET("/users", handlerFunc)
+
+	// removing route before adding it again does not result conflict
+	assert.True(t, e.RemoveRoute(http.MethodGet, "/users"))
+	e.GET("/users", handlerFunc)
+
+	assert.Equal(t, []RouteConflict{
+		{
+			Kind:    RouteConflictUnreachable,
+			Method:  http.MethodGet,
+			Path:    "/files/*/raw",
+			Message: "path after `*` at position 7 is never matched",
+		},
+		{
+			Kind:          RouteConflictDuplicate,
+			Host:          "api.example.com",
+			Method:        http.MethodGet,
+			Path:          "/users",
+			ConflictsWith: "/users",
+			Message:       "route replaces already registered route GET /users",
+		},
+	}, e.RouteConflicts())
+	assert.Equal(t, "GET /users (host api.example.com): route replaces already registered route GET /users",
+		e.RouteConflicts()[1].String())
+}
+
+func TestEcho_Mount(t *testing.T) {
+	admin := New()
+	admin.HTTPErrorHandler = func(err error, c Context) {
+		_ = c.String(http.StatusTeapot, "admin: "+err.Error())
+	}
+	admin.GET("/users/:id", func(c Context) error {
+		return c.String(http.StatusOK, c.Request().URL.Path+" "+c.Param("id"))
+	}).Name = "admin-user"
+	admin.GET("/files/:name", func(c Context) error {
+		return c.String(http.StatusOK, c.Request().URL.Path+" "+c.Request().URL.RawPath+" "+c.Param("name"))
+	})
+	admin.GET("/", func(c Context) error {
+		return c.String(http.StatusOK, "admin root")
+	})
+	admin.GET("/fail", func(c Context) error {
+		return errors.New("failed")
+	})
+
+	e := New()
+	var parentMiddlewareCalls int
+	e.Use(func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			parentMiddlewareCalls++
+			return next(c)
+		}
+	})
+	e.Mount("/admin/", admin)
+	e.Mount("/tenants/:tenant/metrics", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("metrics " + r.URL.Path))
+	}))
+	e.GET("/admin/health", func(c Context) error {
+		return c.String(http.StatusOK, "parent health")
+	})
+
+	var testCases = []struct {
+		whenMethod string
+		whenURL    string
+		expectCode int
+		expectBody string
+	}{
+		{whenURL: "/admin/users/1", expectCode: http.StatusOK, expectBody: "/users/1 1"},
+		{whenURL: "/admin", expectCode: http.StatusOK, expectBody: "admin root"},
+		{whenURL: "/admin/", expectCode: http.StatusOK, expectBody: "admin root"},
+		{whenURL: "/admin/files/a%2Fb", expectCode: http.StatusOK, expectBody: "/files/a/b /files/a%2Fb a%2Fb"},
+		{whenURL: "/admin/fail", expectCode: http.StatusTeapot, expectBody: "admin: failed"},
+		{whenURL: "/admin/missing", expectCode: http.StatusTeapot, expectBody: "admin: code=404, message=Not Found"},
+		{whenMethod: "PURGE", whenURL: "/admin/users/1", expectCode: http.StatusTeapot, expectBody: "admin: code=405, message=Method Not Allowed"},
+		{whenURL: "/admin/health", expectCode: http.StatusOK, expectBody: "parent health"},
+		{whenURL: "/administration", expectCode: http.StatusNotFound, expectBody: `{"message":"Not Found"}` + "\n"},
+		{whenURL: "/tenants/acme/metrics/cpu", expectCode: http.StatusOK, expectBody: "metrics /cpu"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.whenURL, func(t *testing.T) {
+			parentMiddlewareCalls = 0
+			method := tc.whenMethod
+			if method == "" {
+				method = http.MethodGet
+			}
+			code, body := request(method, tc.whenURL, e)
+
+			assert.Equal(t, tc.expectCode, code)
+			assert.Equal(t, tc.expectBody, body)
+			assert.Equal(t, 1, parentMiddlewareCalls)
+		})
+	}
+}
+
+func TestEcho_MountRoutesAndReverse(t *testing.T) {
+	admin := New()
+	admin.GET("/users/:id", handlerFunc).Name = "admin-user"
+
+	e := New()
+	e.GET("/", handlerFunc).Name = "home"
+	e.Mount("/admin", admin)
+	e.Group("/tenants/:tenant").Mount("/admin", admin)
+	admin.POST("/users", handlerFunc) // routes added after mounting are visible too
+
+	paths := make([]string, 0)
+	for _, r := range e.Routes() {
+		paths = append(paths, r.Method+" "+r.Path)
+	}
+	assert.ElementsMatch(t, []string{
+		"GET /",
+		RouteNotFound + " /admin",
+		RouteNotFound + " /admin/*",
+		"GET /admin/users/:id",
+		"POST /admin/users",
+		RouteNotFound + " /tenants/:tenant/admin",
+		RouteNotFound + " /tenants/:tenant/admin/*",
+		"GET /tenants/:tenant/admin/users/:id",
+		"POST /tenants/:tenant/admin/users",
+	}, paths)
+	assert.Len(t, admin.Routes(), 2)
+
+	assert.Equal(t, "/", e.Reverse("home"))
+	assert.Equal(t, "/admin/users/1", e.Reverse("admin-user", 1))
+	assert.Equal(t, "", e.Reverse("missing"))
+
+	tenants := New()
+	tenants.Group("/tenants/:tenant").Mount("/admin", admin)
+	assert.Equal(t, "/tenants/acme/admin/users/1", tenants.Reverse("admin-user", "acme", 1))
+}
+
+func TestEcho_MountRemoved(t *testing.T) {
+	admin := New()
+	admin.GET("/users/:id", handlerFunc).Name = "admin-user"
+
+	e := New()
+	e.Mount("/admin", admin)
+	tenant := e.Group("/tenants/:tenant")
+	tenant.Mount("/admin", admin)
+
+	err := e.UpdateRoutes(func() error {
+		e.RemoveRoute(RouteNotFound, "/admin/*")
+		tenant.Remove()
+		return nil
+	})
+	assert.NoError(t, err)
+
+	paths := make([]string, 0)
+	for _, r := range e.Routes() {
+		paths = append(paths, r.Method+" "+r.Path)
+	}
+	assert.Equal(t, []string{RouteNotFound + " /admin"}, paths)
+	assert.Equal(t, "", e.Reverse("admin-user", 1))
+
+	code, _ := request(http.MethodGet, "/admin/users/1", e)
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestEcho_MountAddsRoutesThroughAdd(t *testing.T) {
+	e := New()
+	var added []string
+	e.OnAddRouteHandler = func(host string, route Route, handler HandlerFunc, middleware []MiddlewareFunc) {
+		added = append(added, route.Method+" "+route.Path)
+	}
+	e.RouteConflictPolicy = RouteConflictLog
+	e.GET("/tenants/:id", handlerFunc)
+
+	e.Mount("/tenants/:tenant/admin", New())
+
+	assert.Equal(t, []string{
+		"GET /tenants/:id",
+		RouteNotFound + " /tenants/:tenant/admin",
+		RouteNotFound + " /tenants/:tenant/admin/*",
+	}, added)
+	conflicts := e.RouteConflicts()
+	if assert.Len(t, conflicts, 2) {
+		assert.Equal(t, RouteConflictParamName, conflicts[0].Kind)
+		assert.Equal(t, "/tenants/:tenant/admin", conflicts[0].Path)
+	}
+}
+
 func TestEchoReverse(t *testing.T) {
 	var testCases = []struct {
 		name          string
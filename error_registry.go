@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	stdContext "context"
+	"errors"
+	"fmt"
+)
+
+// StatusClientClosedRequest is non-standard status code used when client closed the request (i.e. cancelled the request
+// context) before the server sent the response.
+const StatusClientClosedRequest = 499
+
+// ErrorMapping describes how HTTP error handlers respond to an error that is not `*HTTPError`.
+type ErrorMapping struct {
+	// Code is HTTP status code sent to the client.
+	Code int
+	// Message is sent to the client same way as `HTTPError.Message`. Optional. Defaults to status text of Code.
+	Message interface{}
+	// LogLevel is level the error is logged with `Echo.Logger`. Optional. Defaults to LogLevelNone - not logged.
+	LogLevel LogLevel
+}
+
+type errorMatcher struct {
+	// match reports if error matches. Context is nil when error is looked up without request.
+	match   func(c Context, err error) bool
+	mapping ErrorMapping
+}
+
+// ErrorRegistry maps errors to HTTP status codes, messages and log levels. It is consulted by
+// `Echo.DefaultHTTPErrorHandler` and `Echo.ProblemDetailsHTTPErrorHandler` for errors that are not `*HTTPError`
+// before falling back to 500 Internal Server Error.
+//
+// Mappings are checked in order of registration and first matching mapping wins.
+type ErrorRegistry struct {
+	matchers []errorMatcher
+}
+
+// NewErrorRegistry creates new instance of ErrorRegistry with default mapping of `context.Canceled` to
+// StatusClientClosedRequest (499), that is logged with debug level. The mapping applies only when request context was
+// canceled, i.e. client closed the connection, and not to cancellations of other contexts.
+func NewErrorRegistry() *ErrorRegistry {
+	r := &ErrorRegistry{}
+	r.matchers = append(r.matchers, errorMatcher{
+		match: func(c Context, err error) bool {
+			return c != nil && errors.Is(err, stdContext.Canceled) && errors.Is(c.Request().Context().Err(), stdContext.Canceled)
+		},
+		mapping: ErrorMapping{
+			Code:     StatusClientClosedRequest,
+			Message:  "Client Closed Request",
+			LogLevel: LogLevelDebug,
+		},
+	})
+	return r
+}
+
+// Register adds mapping for errors that match target with `errors.Is`.
+//
+// Example: `e.ErrorRegistry.Register(sql.ErrNoRows, echo.ErrorMapping{Code: http.StatusNotFound})`
+func (r *ErrorRegistry) Register(target error, mapping ErrorMapping) {
+	r.RegisterFunc(func(err error) bool {
+		return errors.Is(err, target)
+	}, mapping)
+}
+
+// RegisterFunc adds mapping for errors for which match function returns true.
+func (r *ErrorRegistry) RegisterFunc(match func(err error) bool, mapping ErrorMapping) {
+	r.matchers = append(r.matchers, errorMatcher{
+		match: func(c Context, err error) bool {
+			return match(err)
+		},
+		mapping: mapping,
+	})
+}
+
+// RegisterErrorType adds mapping for errors that match type T with `errors.As`.
+//
+// Example: `echo.RegisterErrorType[*pgconn.PgError](e.ErrorRegistry, echo.ErrorMapping{Code: http.StatusConflict})`
+func RegisterErrorType[T error](r *ErrorRegistry, mapping ErrorMapping) {
+	r.RegisterFunc(func(err error) bool {
+		var target T
+		return errors.As(err, &target)
+	}, mapping)
+}
+
+// Lookup returns first mapping that matches the error. Default mapping of client closed request is not matched as it
+// depends on the request.
+func (r *ErrorRegistry) Lookup(err error) (ErrorMapping, bool) {
+	return r.lookup(nil, err)
+}
+
+func (r *ErrorRegistry) lookup(c Context, err error) (ErrorMapping, bool) {
+	if r == nil || err == nil {
+		return ErrorMapping{}, false
+	}
+	for _, m := range r.matchers {
+		if m.match(c, err) {
+			return m.mapping, true
+		}
+	}
+	return ErrorMapping{}, false
+}
+
+// mapError converts error to HTTPError with registered mapping and logs the error with mapped log level.
+func (e *Echo) mapError(c Context, err error) (*HTTPError, bool) {
+	mapping, ok := e.ErrorRegistry.lookup(c, err)
+	if !ok {
+		return nil, false
+	}
+	if e.Logger != nil {
+		logWithLevel(e.Logger, mapping.LogLevel, fmt.Sprintf("request error: %v", err), "status", mapping.Code)
+	}
+	he := NewHTTPError(mapping.Code).SetInternal(err)
+	if mapping.Message != nil {
+		he.Message = mapping.Message
+	} else if he.Message == "" {
+		he.Message = fmt.Sprintf("%d", mapping.Code)
+	}
+	return he, true
+}
@@ -0,0 +1,198 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"bytes"
+	stdContext "context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errTestNoRows = errors.New("no rows in result set")
+
+func TestErrorRegistry_Lookup(t *testing.T) {
+	r := NewErrorRegistry()
+	r.Register(errTestNoRows, ErrorMapping{Code: http.StatusNotFound})
+	RegisterErrorType[*fs.PathError](r, ErrorMapping{Code: http.StatusGone, Message: "file is gone"})
+	r.RegisterFunc(func(err error) bool {
+		return err.Error() == "custom"
+	}, ErrorMapping{Code: http.StatusTeapot})
+
+	var testCases = []struct {
+		name     string
+		whenErr  error
+		expect   ErrorMapping
+		expectOk bool
+	}{
+		{
+			name:     "ok, errors.Is match",
+			whenErr:  fmt.Errorf("query failed: %w", errTestNoRows),
+			expect:   ErrorMapping{Code: http.StatusNotFound},
+			expectOk: true,
+		},
+		{
+			name:     "ok, errors.As match",
+			whenErr:  fmt.Errorf("open failed: %w", &fs.PathError{Op: "open", Path: "x", Err: fs.ErrNotExist}),
+			expect:   ErrorMapping{Code: http.StatusGone, Message: "file is gone"},
+			expectOk: true,
+		},
+		{
+			name:     "ok, func match",
+			whenErr:  errors.New("custom"),
+			expect:   ErrorMapping{Code: http.StatusTeapot},
+			expectOk: true,
+		},
+		{
+			name:    "nok, client closed request depends on request",
+			whenErr: stdContext.Canceled,
+		},
+		{
+			name:    "nok, deadline exceeded is not mapped",
+			whenErr: fmt.Errorf("db: %w", stdContext.DeadlineExceeded),
+		},
+		{
+			name:    "nok, no match",
+			whenErr: errors.New("other"),
+		},
+		{
+			name:    "nok, nil error",
+			whenErr: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mapping, ok := r.Lookup(tc.whenErr)
+			assert.Equal(t, tc.expectOk, ok)
+			assert.Equal(t, tc.expect, mapping)
+		})
+	}
+}
+
+func TestErrorRegistry_FirstRegisteredWins(t *testing.T) {
+	r := &ErrorRegistry{}
+	r.Register(errTestNoRows, ErrorMapping{Code: http.StatusNotFound})
+	r.Register(errTestNoRows, ErrorMapping{Code: http.StatusGone})
+
+	mapping, ok := r.Lookup(errTestNoRows)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, mapping.Code)
+
+	var nilRegistry *ErrorRegistry
+	_, ok = nilRegistry.Lookup(errTestNoRows)
+	assert.False(t, ok)
+}
+
+func TestDefaultHTTPErrorHandler_ErrorRegistry(t *testing.T) {
+	var testCases = []struct {
+		name             string
+		whenErr          error
+		whenClientClosed bool
+		expectCode       int
+		expectBody       string
+		expectLog        string
+	}{
+		{
+			name:       "ok, mapped error",
+			whenErr:    fmt.Errorf("query: %w", errTestNoRows),
+			expectCode: http.StatusNotFound,
+			expectBody: `{"message":"Not Found"}` + "\n",
+			expectLog:  `level=INFO msg="request error: query: no rows in result set" status=404`,
+		},
+		{
+			name:             "ok, client closed request is not logged as error",
+			whenErr:          stdContext.Canceled,
+			whenClientClosed: true,
+			expectCode:       StatusClientClosedRequest,
+			expectBody:       `{"message":"Client Closed Request"}` + "\n",
+			expectLog:        `level=DEBUG msg="request error: context canceled" status=499`,
+		},
+		{
+			name:       "ok, canceled context not belonging to request falls back to 500",
+			whenErr:    fmt.Errorf("job: %w", stdContext.Canceled),
+			expectCode: http.StatusInternalServerError,
+			expectBody: `{"message":"Internal Server Error"}` + "\n",
+			expectLog:  "",
+		},
+		{
+			name:       "ok, deadline exceeded falls back to 500",
+			whenErr:    stdContext.DeadlineExceeded,
+			expectCode: http.StatusInternalServerError,
+			expectBody: `{"message":"Internal Server Error"}` + "\n",
+			expectLog:  "",
+		},
+		{
+			name:       "ok, unmapped error falls back to 500",
+			whenErr:    errors.New("boom"),
+			expectCode: http.StatusInternalServerError,
+			expectBody: `{"message":"Internal Server Error"}` + "\n",
+			expectLog:  "",
+		},
+		{
+			name:       "ok, HTTPError is not mapped",
+			whenErr:    NewHTTPError(http.StatusBadRequest).SetInternal(errTestNoRows),
+			expectCode: http.StatusBadRequest,
+			expectBody: `{"message":"Bad Request"}` + "\n",
+			expectLog:  "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			e := New()
+			e.Logger = &SlogLogger{Logger: slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+				Level: slog.LevelDebug,
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if a.Key == slog.TimeKey {
+						return slog.Attr{}
+					}
+					return a
+				},
+			}))}
+			e.ErrorRegistry.Register(errTestNoRows, ErrorMapping{Code: http.StatusNotFound, LogLevel: LogLevelInfo})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.whenClientClosed {
+				ctx, cancel := stdContext.WithCancel(req.Context())
+				cancel()
+				req = req.WithContext(ctx)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			e.DefaultHTTPErrorHandler(tc.whenErr, c)
+
+			assert.Equal(t, tc.expectCode, rec.Code)
+			assert.Equal(t, tc.expectBody, rec.Body.String())
+			if tc.expectLog == "" {
+				assert.Empty(t, buf.String())
+			} else {
+				assert.Equal(t, tc.expectLog+"\n", buf.String())
+			}
+		})
+	}
+}
+
+func TestProblemDetailsHTTPErrorHandler_ErrorRegistry(t *testing.T) {
+	e := New()
+	e.ErrorRegistry.Register(errTestNoRows, ErrorMapping{Code: http.StatusNotFound, Message: "user not found"})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	e.ProblemDetailsHTTPErrorHandler(fmt.Errorf("query: %w", errTestNoRows), c)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.JSONEq(t, `{"status":404,"title":"Not Found","detail":"user not found"}`, rec.Body.String())
+}
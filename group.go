@@ -10,12 +10,28 @@ import (
 // Group is a set of sub-routes for a specified route. It can be used for inner
 // routes that share a common middleware or functionality that should be separate
 // from the parent echo instance while still inheriting from it.
+//
+// HTTPErrorHandler, Binder, Validator, Renderer and JSONSerializer fields override the same Echo fields for requests
+// matched to routes of the group and its sub-groups. Nil fields are inherited from the nearest parent group that sets
+// them, or from Echo. Requests that match no route use the settings of the group whose `RouteNotFound` route matches
+// them; catch-all `RouteNotFound` routes are added for groups with middleware. Add one, i.e.
+// `g.RouteNotFound("/*", echo.NotFoundHandler)`, to handle 404 errors of a group without middleware with its
+// HTTPErrorHandler.
 type Group struct {
 	common
 	host       string
 	prefix     string
 	echo       *Echo
+	parent     *Group
 	middleware []MiddlewareFunc
+	// routes are routes added with the group and its sub-groups.
+	routes []*Route
+
+	HTTPErrorHandler HTTPErrorHandler
+	Binder           Binder
+	Validator        Validator
+	Renderer         Renderer
+	JSONSerializer   JSONSerializer
 }
 
 // Use implements `Echo#Use()` for sub-routes within the Group.
@@ -100,8 +116,8 @@ func (g *Group) Group(prefix string, middleware ...MiddlewareFunc) (sg *Group) {
 	m := make([]MiddlewareFunc, 0, len(g.middleware)+len(middleware))
 	m = append(m, g.middleware...)
 	m = append(m, middleware...)
-	sg = g.echo.Group(g.prefix+prefix, m...)
-	sg.host = g.host
+	sg = &Group{host: g.host, prefix: g.prefix + prefix, echo: g.echo, parent: g}
+	sg.Use(m...)
 	return
 }
 
@@ -125,5 +141,83 @@ func (g *Group) Add(method, path string, handler HandlerFunc, middleware ...Midd
 	m := make([]MiddlewareFunc, 0, len(g.middleware)+len(middleware))
 	m = append(m, g.middleware...)
 	m = append(m, middleware...)
-	return g.echo.add(g.host, method, g.prefix+path, handler, m...)
+	route := g.echo.add(g.host, g, method, g.prefix+path, handler, m...)
+	g.addRoute(route)
+	return route
+}
+
+// AddE implements `Echo#AddE()` for sub-routes within the Group.
+func (g *Group) AddE(method, path string, handler HandlerFunc, middleware ...MiddlewareFunc) (*Route, error) {
+	m := make([]MiddlewareFunc, 0, len(g.middleware)+len(middleware))
+	m = append(m, g.middleware...)
+	m = append(m, middleware...)
+	route, err := g.echo.addE(g.host, g, method, g.prefix+path, handler, m...)
+	if err != nil {
+		return nil, err
+	}
+	g.addRoute(route)
+	return route, nil
+}
+
+// addRoute records route as added with the group and its ancestors.
+func (g *Group) addRoute(route *Route) {
+	for pg := g; pg != nil; pg = pg.parent {
+		pg.saveRoutes()
+		pg.routes = append(pg.routes, route)
+	}
+}
+
+// saveRoutes saves routes of the group before they are changed within `Echo.UpdateRoutes`, so they can be restored
+// when the update is discarded.
+func (g *Group) saveRoutes() {
+	if g.echo.groupRoutes == nil {
+		return
+	}
+	if _, ok := g.echo.groupRoutes[g]; !ok {
+		g.echo.groupRoutes[g] = append([]*Route(nil), g.routes...)
+	}
+}
+
+// Mount implements `Echo#Mount()` for sub-routes within the Group. Group level middleware is executed before request is
+// passed to sub.
+func (g *Group) Mount(prefix string, sub http.Handler) {
+	mountTo(g.echo.findRouter(g.host), g.RouteNotFound, prefix, g.prefix+prefix, sub)
+}
+
+// RemoveRoute removes route registered for method and path within the group. Returns false when there is no such
+// route. To remove routes while server is running call it within `Echo.UpdateRoutes`.
+func (g *Group) RemoveRoute(method, path string) bool {
+	return g.echo.findRouter(g.host).Remove(method, g.prefix+path)
+}
+
+// Remove removes all routes added with the group and its sub-groups, including routes added by group level
+// middleware. Routes that have been replaced by routes added later for the same method and path outside the group are
+// kept. To remove routes while server is running call it within `Echo.UpdateRoutes`.
+func (g *Group) Remove() {
+	router := g.echo.findRouter(g.host)
+	removed := g.routes
+	for _, route := range removed {
+		router.removeRoute(route)
+	}
+	for pg := g; pg != nil; pg = pg.parent {
+		pg.saveRoutes()
+		pg.routes = removeRoutes(pg.routes, removed)
+	}
+}
+
+func removeRoutes(routes []*Route, removed []*Route) []*Route {
+	result := make([]*Route, 0, len(routes))
+	for _, route := range routes {
+		keep := true
+		for _, r := range removed {
+			if r == route {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			result = append(result, route)
+		}
+	}
+	return result
 }
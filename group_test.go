@@ -4,6 +4,8 @@
 package echo
 
 import (
+	"errors"
+	"html/template"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -257,3 +259,192 @@ func TestGroup_RouteNotFoundWithMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestGroup_Remove(t *testing.T) {
+	e := New()
+	e.GET("/plugins", handlerFunc)
+
+	var g *Group
+	err := e.UpdateRoutes(func() error {
+		g = e.Group("/plugins/reports", func(next HandlerFunc) HandlerFunc {
+			return next
+		})
+		g.GET("/daily", handlerFunc)
+		g.Group("/admin").POST("/reset", handlerFunc)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, e.Routes(), 7) // routes + "not found" routes added by group and sub-group middleware
+
+	code, _ := request(http.MethodPost, "/plugins/reports/admin/reset", e)
+	assert.Equal(t, http.StatusOK, code)
+
+	err = e.UpdateRoutes(func() error {
+		g.Remove()
+		return nil
+	})
+	assert.NoError(t, err)
+
+	code, _ = request(http.MethodGet, "/plugins/reports/daily", e)
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _ = request(http.MethodPost, "/plugins/reports/admin/reset", e)
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _ = request(http.MethodGet, "/plugins", e)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, e.Routes(), 1)
+}
+
+func TestGroup_AddE(t *testing.T) {
+	e := New()
+	g := e.Group("/api")
+	route, err := g.AddE(http.MethodGet, "/users", handlerFunc)
+	assert.NoError(t, err)
+	assert.Equal(t, "/api/users", route.Path)
+
+	route, err = g.AddE(http.MethodGet, "/users", handlerFunc)
+	assert.Nil(t, route)
+	assert.EqualError(t, err, "echo: route conflict: GET /api/users: route replaces already registered route GET /api/users")
+	assert.Equal(t, []*Route{e.Routes()[0]}, g.routes)
+}
+
+func TestGroup_RemoveKeepsReplacedRoutes(t *testing.T) {
+	e := New()
+	g := e.Group("/api")
+	g.GET("/users", handlerFunc)
+	g.GET("/teams", handlerFunc)
+	e.GET("/api/users", func(c Context) error {
+		return c.String(http.StatusOK, "replaced")
+	})
+
+	assert.True(t, g.RemoveRoute(http.MethodGet, "/teams"))
+	g.Remove()
+
+	code, body := request(http.MethodGet, "/api/users", e)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "replaced", body)
+	code, _ = request(http.MethodGet, "/api/teams", e)
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestGroup_Mount(t *testing.T) {
+	sub := New()
+	sub.GET("/users", func(c Context) error {
+		return c.String(http.StatusOK, c.Request().URL.Path)
+	})
+
+	e := New()
+	g := e.Group("/api", func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			c.Response().Header().Set("X-Group", "api")
+			return next(c)
+		}
+	})
+	g.Mount("/v1", sub)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "/users", rec.Body.String())
+	assert.Equal(t, "api", rec.Header().Get("X-Group"))
+}
+
+type testGroupValidator struct{ err error }
+
+func (v testGroupValidator) Validate(i interface{}) error {
+	return v.err
+}
+
+type testGroupBinder struct{ value string }
+
+func (b testGroupBinder) Bind(i interface{}, c Context) error {
+	*(i.(*string)) = b.value
+	return nil
+}
+
+type testGroupSerializer struct{ DefaultJSONSerializer }
+
+func (s testGroupSerializer) Serialize(c Context, i interface{}, indent string) error {
+	_, err := c.Response().Write([]byte(`{"group":true}`))
+	return err
+}
+
+func TestGroup_Overrides(t *testing.T) {
+	e := New()
+	e.HTTPErrorHandler = func(err error, c Context) {
+		_ = c.String(http.StatusInternalServerError, "echo: "+err.Error())
+	}
+	e.Renderer = &TemplateRenderer{Template: template.Must(template.New("page").Parse("echo {{.}}"))}
+
+	api := e.Group("/api")
+	api.HTTPErrorHandler = func(err error, c Context) {
+		_ = c.String(http.StatusTeapot, "api: "+err.Error())
+	}
+	api.Binder = testGroupBinder{value: "api"}
+	api.Validator = testGroupValidator{err: errors.New("invalid")}
+	api.JSONSerializer = testGroupSerializer{}
+	api.RouteNotFound("/*", NotFoundHandler)
+	bind := func(c Context) error {
+		var v string
+		if err := c.Bind(&v); err != nil {
+			return err
+		}
+		return c.String(http.StatusOK, v)
+	}
+	api.POST("/bind", bind)
+	api.GET("/validate", func(c Context) error {
+		return c.Validate(nil)
+	})
+	api.GET("/json", func(c Context) error {
+		return c.JSON(http.StatusOK, map[string]bool{"group": false})
+	})
+
+	v1 := api.Group("/v1")
+	v1.Binder = testGroupBinder{value: "v1"}
+	v1.POST("/bind", bind)
+	v1.GET("/error", func(c Context) error {
+		return errors.New("failed")
+	})
+
+	web := e.Group("/web")
+	web.Renderer = &TemplateRenderer{Template: template.Must(template.New("page").Parse("web {{.}}"))}
+	web.HTTPErrorHandler = func(err error, c Context) {
+		_ = c.String(http.StatusBadGateway, "web: "+err.Error())
+	}
+	render := func(c Context) error {
+		return c.Render(http.StatusOK, "page", "home")
+	}
+	web.GET("/", render)
+	e.GET("/", render)
+	e.GET("/error", func(c Context) error {
+		return errors.New("failed")
+	})
+
+	var testCases = []struct {
+		name       string
+		whenMethod string
+		whenURL    string
+		expectCode int
+		expectBody string
+	}{
+		{name: "group binder", whenMethod: http.MethodPost, whenURL: "/api/bind", expectCode: http.StatusOK, expectBody: "api"},
+		{name: "sub-group binder", whenMethod: http.MethodPost, whenURL: "/api/v1/bind", expectCode: http.StatusOK, expectBody: "v1"},
+		{name: "group validator", whenMethod: http.MethodGet, whenURL: "/api/validate", expectCode: http.StatusTeapot, expectBody: "api: invalid"},
+		{name: "group serializer", whenMethod: http.MethodGet, whenURL: "/api/json", expectCode: http.StatusOK, expectBody: `{"group":true}`},
+		{name: "inherited error handler", whenMethod: http.MethodGet, whenURL: "/api/v1/error", expectCode: http.StatusTeapot, expectBody: "api: failed"},
+		{name: "group route not found", whenMethod: http.MethodGet, whenURL: "/api/missing", expectCode: http.StatusTeapot, expectBody: "api: code=404, message=Not Found"},
+		{name: "group method not allowed", whenMethod: http.MethodDelete, whenURL: "/web/", expectCode: http.StatusBadGateway, expectBody: "web: code=405, message=Method Not Allowed"},
+		{name: "group renderer", whenMethod: http.MethodGet, whenURL: "/web/", expectCode: http.StatusOK, expectBody: "web home"},
+		{name: "echo renderer", whenMethod: http.MethodGet, whenURL: "/", expectCode: http.StatusOK, expectBody: "echo home"},
+		{name: "echo error handler", whenMethod: http.MethodGet, whenURL: "/error", expectCode: http.StatusInternalServerError, expectBody: "echo: failed"},
+		{name: "no group catch-all", whenMethod: http.MethodGet, whenURL: "/web/missing", expectCode: http.StatusInternalServerError, expectBody: "echo: code=404, message=Not Found"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			code, body := request(tc.whenMethod, tc.whenURL, e)
+			assert.Equal(t, tc.expectCode, code)
+			assert.Equal(t, tc.expectBody, body)
+		})
+	}
+}
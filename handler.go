@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+)
+
+// Handle creates HandlerFunc from function that takes request value and returns response value. Request is bound
+// with `Context.Bind` and validated with `Context.Validate` (when Validator is registered) before fn is called.
+// Response is serialized with 200 status and content type negotiated from the `Accept` header (see
+// `Context.Negotiate`). When fn commits the response itself, the returned value is ignored.
+//
+// Binding errors are returned as is, validation errors that are not `*HTTPError` are returned as 400 Bad Request
+// `*HTTPError` with the validation error as internal error.
+//
+// Use `HandleRoute` to register the handler with request and response types recorded on the Route.
+//
+// Example:
+//
+//	type createUserRequest struct {
+//		Name string `json:"name" validate:"required"`
+//	}
+//
+//	e.POST("/users", echo.Handle(func(c echo.Context, req createUserRequest) (User, error) {
+//		return users.Create(c.Request().Context(), req.Name)
+//	}))
+func Handle[Req any, Resp any](fn func(c Context, req Req) (Resp, error)) HandlerFunc {
+	return func(c Context) error {
+		var req Req
+		if err := c.Bind(&req); err != nil {
+			return err
+		}
+		if err := c.Validate(&req); err != nil && !errors.Is(err, ErrValidatorNotRegistered) {
+			var he *HTTPError
+			if errors.As(err, &he) {
+				return err
+			}
+			return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+
+		resp, err := fn(c, req)
+		if err != nil {
+			return err
+		}
+		if c.Response().Committed {
+			return nil
+		}
+		return c.Negotiate(http.StatusOK, resp)
+	}
+}
+
+// RouteAdder adds routes. It is implemented by `Echo` and `Group`.
+type RouteAdder interface {
+	Add(method, path string, handler HandlerFunc, middleware ...MiddlewareFunc) *Route
+}
+
+// HandleRoute registers handler created with `Handle` from fn for method and path and records request and response
+// types of fn on the route (see `RouteInfo.RequestType` and `RouteInfo.ResponseType`), i.e. for OpenAPI document generation.
+//
+// Example:
+//
+//	echo.HandleRoute(e, http.MethodPost, "/users", createUser).WithSummary("Create user")
+func HandleRoute[Req any, Resp any](r RouteAdder, method, path string, fn func(c Context, req Req) (Resp, error), middleware ...MiddlewareFunc) *Route {
+	route := r.Add(method, path, Handle(fn), middleware...)
+	route.updateInfo(func(info *RouteInfo) {
+		info.RequestType = reflect.TypeOf((*Req)(nil)).Elem()
+		info.ResponseType = reflect.TypeOf((*Resp)(nil)).Elem()
+	})
+	return route
+}
@@ -0,0 +1,190 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testHandleRequest struct {
+	ID   int    `param:"id"`
+	Name string `json:"name" xml:"name"`
+}
+
+type testHandleResponse struct {
+	ID      int    `json:"id" xml:"id"`
+	Message string `json:"message" xml:"message"`
+}
+
+type testHandleValidator struct{}
+
+func (v *testHandleValidator) Validate(i interface{}) error {
+	req, ok := i.(*testHandleRequest)
+	if !ok {
+		return nil
+	}
+	if req.Name == "" {
+		return errors.New("name is required")
+	}
+	if req.Name == "forbidden" {
+		return NewHTTPError(http.StatusForbidden, "name is forbidden")
+	}
+	return nil
+}
+
+func testHandleGreet(c Context, req testHandleRequest) (testHandleResponse, error) {
+	if req.Name == "fail" {
+		return testHandleResponse{}, NewHTTPError(http.StatusConflict, "failed")
+	}
+	return testHandleResponse{ID: req.ID, Message: "hello " + req.Name}, nil
+}
+
+func TestHandle(t *testing.T) {
+	var testCases = []struct {
+		name              string
+		givenValidator    Validator
+		whenBody          string
+		whenAccept        string
+		expectCode        int
+		expectBody        string
+		expectContentType string
+	}{
+		{
+			name:              "ok, json",
+			givenValidator:    &testHandleValidator{},
+			whenBody:          `{"name":"bob"}`,
+			expectCode:        http.StatusOK,
+			expectBody:        `{"id":1,"message":"hello bob"}` + "\n",
+			expectContentType: MIMEApplicationJSON,
+		},
+		{
+			name:              "ok, negotiated xml",
+			whenBody:          `{"name":"bob"}`,
+			whenAccept:        MIMEApplicationXML,
+			expectCode:        http.StatusOK,
+			expectBody:        `<?xml version="1.0" encoding="UTF-8"?>` + "\n" + `<testHandleResponse><id>1</id><message>hello bob</message></testHandleResponse>`,
+			expectContentType: MIMEApplicationXML,
+		},
+		{
+			name:              "ok, without validator",
+			whenBody:          `{"name":""}`,
+			expectCode:        http.StatusOK,
+			expectBody:        `{"id":1,"message":"hello "}` + "\n",
+			expectContentType: MIMEApplicationJSON,
+		},
+		{
+			name:              "nok, bind error",
+			whenBody:          `{"name":`,
+			expectCode:        http.StatusBadRequest,
+			expectBody:        `{"message":"unexpected EOF"}` + "\n",
+			expectContentType: MIMEApplicationJSON,
+		},
+		{
+			name:              "nok, validation error is bad request",
+			givenValidator:    &testHandleValidator{},
+			whenBody:          `{"name":""}`,
+			expectCode:        http.StatusBadRequest,
+			expectBody:        `{"message":"name is required"}` + "\n",
+			expectContentType: MIMEApplicationJSON,
+		},
+		{
+			name:              "nok, validation HTTPError is returned as is",
+			givenValidator:    &testHandleValidator{},
+			whenBody:          `{"name":"forbidden"}`,
+			expectCode:        http.StatusForbidden,
+			expectBody:        `{"message":"name is forbidden"}` + "\n",
+			expectContentType: MIMEApplicationJSON,
+		},
+		{
+			name:              "nok, handler error",
+			whenBody:          `{"name":"fail"}`,
+			expectCode:        http.StatusConflict,
+			expectBody:        `{"message":"failed"}` + "\n",
+			expectContentType: MIMEApplicationJSON,
+		},
+		{
+			name:              "nok, not acceptable",
+			whenBody:          `{"name":"bob"}`,
+			whenAccept:        "image/png",
+			expectCode:        http.StatusNotAcceptable,
+			expectBody:        `{"message":"Not Acceptable"}` + "\n",
+			expectContentType: MIMEApplicationJSON,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.Validator = tc.givenValidator
+			e.POST("/users/:id", Handle(testHandleGreet))
+
+			req := httptest.NewRequest(http.MethodPost, "/users/1", strings.NewReader(tc.whenBody))
+			req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+			if tc.whenAccept != "" {
+				req.Header.Set(HeaderAccept, tc.whenAccept)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectCode, rec.Code)
+			assert.Equal(t, tc.expectBody, rec.Body.String())
+			assert.Equal(t, tc.expectContentType, rec.Header().Get(HeaderContentType))
+		})
+	}
+}
+
+func TestHandle_CommittedResponse(t *testing.T) {
+	e := New()
+	e.GET("/", Handle(func(c Context, req struct{}) (*testHandleResponse, error) {
+		return nil, c.NoContent(http.StatusNoContent)
+	}))
+
+	code, body := request(http.MethodGet, "/", e)
+	assert.Equal(t, http.StatusNoContent, code)
+	assert.Equal(t, "", body)
+}
+
+func TestHandleRoute(t *testing.T) {
+	e := New()
+	route := HandleRoute(e, http.MethodPost, "/users/:id", testHandleGreet)
+	g := e.Group("/api")
+	groupRoute := HandleRoute(g, http.MethodGet, "/ping", func(c Context, req struct{}) (string, error) {
+		return "pong", nil
+	})
+	handleRoute := e.GET("/handle", Handle(testHandleGreet))
+	plainRoute := e.GET("/plain", func(c Context) error {
+		return nil
+	})
+
+	assert.Equal(t, http.MethodPost, route.Method)
+	assert.Equal(t, "/users/:id", route.Path)
+	assert.Equal(t, reflect.TypeOf(testHandleRequest{}), route.Info().RequestType)
+	assert.Equal(t, reflect.TypeOf(testHandleResponse{}), route.Info().ResponseType)
+
+	assert.Equal(t, "/api/ping", groupRoute.Path)
+	assert.Equal(t, reflect.TypeOf(struct{}{}), groupRoute.Info().RequestType)
+	assert.Equal(t, reflect.TypeOf(""), groupRoute.Info().ResponseType)
+
+	// types are recorded only by HandleRoute, handlers are not executed on registration
+	assert.Nil(t, handleRoute.Info().RequestType)
+	assert.Nil(t, plainRoute.Info().RequestType)
+	assert.Nil(t, plainRoute.Info().ResponseType)
+
+	for _, r := range e.Routes() {
+		if r.Path == "/users/:id" {
+			assert.Equal(t, reflect.TypeOf(testHandleRequest{}), r.Info().RequestType)
+		}
+	}
+
+	code, body := request(http.MethodGet, "/api/ping", e)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "\"pong\"\n", body)
+}
@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// hostPattern is host name given to `Echo.Host` that contains `*` wildcard or `{name}` parameter labels, i.e.
+// `*.customers.example.com` or `{tenant}.example.com:8443`. Literal labels are matched case-insensitively as DNS names
+// are case-insensitive. Parameter values are captured as sent by the client.
+type hostPattern struct {
+	// pattern is host as given to `Echo.Host`. Router for the pattern is stored in routers map with it as key.
+	pattern string
+	labels  []string
+	// port that host must have. Empty when pattern matches hosts with any port or without port.
+	port string
+	// names are names of parameter labels in order they appear in pattern.
+	names []string
+	// literals is number of labels that must match exactly. Patterns with more literal labels take precedence.
+	literals int
+}
+
+// hostPatterns are host patterns ordered by precedence.
+type hostPatterns struct {
+	list []*hostPattern
+}
+
+// isHostPattern reports whether host given to `Echo.Host` is pattern instead of exact host name.
+func isHostPattern(host string) bool {
+	return strings.ContainsAny(host, "*{")
+}
+
+func parseHostPattern(pattern string) (*hostPattern, error) {
+	p := &hostPattern{pattern: pattern}
+	name, port := splitHostPort(pattern)
+	p.port = port
+	p.labels = strings.Split(name, ".")
+	for i, l := range p.labels {
+		switch {
+		case l == "*":
+		case len(l) > 2 && l[0] == '{' && l[len(l)-1] == '}':
+			p.names = append(p.names, l[1:len(l)-1])
+			p.labels[i] = "{}"
+		case l == "" || strings.ContainsAny(l, "*{}"):
+			return nil, fmt.Errorf("echo: invalid host pattern %q", pattern)
+		default:
+			p.labels[i] = strings.ToLower(l)
+			p.literals++
+		}
+	}
+	return p, nil
+}
+
+// match reports whether host matches the pattern and returns values of parameter labels.
+func (p *hostPattern) match(host string) ([]string, bool) {
+	name, port := splitHostPort(host)
+	if p.port != "" && p.port != port {
+		return nil, false
+	}
+	var values []string
+	for i, l := range p.labels {
+		label, rest, found := strings.Cut(name, ".")
+		if label == "" || found != (i < len(p.labels)-1) {
+			return nil, false
+		}
+		name = rest
+		switch l {
+		case "*":
+		case "{}":
+			if values == nil {
+				values = make([]string, 0, len(p.names))
+			}
+			values = append(values, label)
+		default:
+			if !strings.EqualFold(l, label) {
+				return nil, false
+			}
+		}
+	}
+	return values, true
+}
+
+// add adds pattern to the list or replaces pattern with the same value.
+func (hp *hostPatterns) add(p *hostPattern) {
+	for i, existing := range hp.list {
+		if existing.pattern == p.pattern {
+			hp.list[i] = p
+			return
+		}
+	}
+	hp.list = append(hp.list, p)
+	// precedence: more literal labels first, then patterns with port. Otherwise in order patterns were added.
+	sort.SliceStable(hp.list, func(i, j int) bool {
+		if hp.list[i].literals != hp.list[j].literals {
+			return hp.list[i].literals > hp.list[j].literals
+		}
+		return hp.list[i].port != "" && hp.list[j].port == ""
+	})
+}
+
+func (hp *hostPatterns) clone() *hostPatterns {
+	return &hostPatterns{list: append([]*hostPattern(nil), hp.list...)}
+}
+
+// splitHostPort splits host to name and port. Port is empty when host does not have port.
+func splitHostPort(host string) (string, string) {
+	i := strings.LastIndexByte(host, ':')
+	if i == -1 || strings.LastIndexByte(host, ']') > i {
+		return host, "" // no port or IPv6 address without port
+	}
+	return host[:i], host[i+1:]
+}
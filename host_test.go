@@ -0,0 +1,194 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHostPattern(t *testing.T) {
+	var testCases = []struct {
+		whenPattern   string
+		expect        *hostPattern
+		expectErr     string
+		expectPattern bool
+	}{
+		{
+			whenPattern: "{tenant}.Example.com:8443",
+			expect: &hostPattern{
+				pattern:  "{tenant}.Example.com:8443",
+				labels:   []string{"{}", "example", "com"},
+				port:     "8443",
+				names:    []string{"tenant"},
+				literals: 2,
+			},
+		},
+		{
+			whenPattern: "*.{region}.example.com",
+			expect: &hostPattern{
+				pattern:  "*.{region}.example.com",
+				labels:   []string{"*", "{}", "example", "com"},
+				names:    []string{"region"},
+				literals: 2,
+			},
+		},
+		{whenPattern: "api*.example.com", expectErr: `echo: invalid host pattern "api*.example.com"`},
+		{whenPattern: "{}.example.com", expectErr: `echo: invalid host pattern "{}.example.com"`},
+		{whenPattern: "*..example.com", expectErr: `echo: invalid host pattern "*..example.com"`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.whenPattern, func(t *testing.T) {
+			assert.True(t, isHostPattern(tc.whenPattern))
+			p, err := parseHostPattern(tc.whenPattern)
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, p)
+		})
+	}
+	assert.False(t, isHostPattern("example.com:8080"))
+}
+
+func TestHostPattern_match(t *testing.T) {
+	var testCases = []struct {
+		givenPattern string
+		whenHost     string
+		expectValues []string
+		expectMatch  bool
+	}{
+		{givenPattern: "{tenant}.example.com", whenHost: "acme.example.com", expectValues: []string{"acme"}, expectMatch: true},
+		{givenPattern: "{tenant}.example.com", whenHost: "Acme.example.com:8080", expectValues: []string{"Acme"}, expectMatch: true},
+		{givenPattern: "{tenant}.example.com", whenHost: "API.Example.com", expectValues: []string{"API"}, expectMatch: true},
+		{givenPattern: "{tenant}.EXAMPLE.com", whenHost: "acme.example.COM", expectValues: []string{"acme"}, expectMatch: true},
+		{givenPattern: "{tenant}.example.com", whenHost: "example.com"},
+		{givenPattern: "{tenant}.example.com", whenHost: "a.b.example.com"},
+		{givenPattern: "{tenant}.example.com", whenHost: ".example.com"},
+		{givenPattern: "{tenant}.example.com", whenHost: "acme.example.org"},
+		{givenPattern: "*.{env}.example.com", whenHost: "api.dev.example.com", expectValues: []string{"dev"}, expectMatch: true},
+		{givenPattern: "*.example.com", whenHost: "api.example.com", expectMatch: true},
+		{givenPattern: "*.example.com:8443", whenHost: "api.example.com:8443", expectMatch: true},
+		{givenPattern: "*.example.com:8443", whenHost: "api.example.com"},
+		{givenPattern: "*.example.com:8443", whenHost: "api.example.com:443"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.givenPattern+" "+tc.whenHost, func(t *testing.T) {
+			p, err := parseHostPattern(tc.givenPattern)
+			assert.NoError(t, err)
+
+			values, ok := p.match(tc.whenHost)
+			assert.Equal(t, tc.expectMatch, ok)
+			assert.Equal(t, tc.expectValues, values)
+		})
+	}
+}
+
+func TestHostPatterns_add(t *testing.T) {
+	hp := &hostPatterns{}
+	for _, pattern := range []string{"*.example.com", "{tenant}.example.com", "api.{tenant}.example.com", "*.example.com:8443", "*.example.com"} {
+		p, err := parseHostPattern(pattern)
+		assert.NoError(t, err)
+		hp.add(p)
+	}
+
+	patterns := make([]string, 0, len(hp.list))
+	for _, p := range hp.list {
+		patterns = append(patterns, p.pattern)
+	}
+	assert.Equal(t, []string{"api.{tenant}.example.com", "*.example.com:8443", "*.example.com", "{tenant}.example.com"}, patterns)
+}
+
+func TestSplitHostPort(t *testing.T) {
+	var testCases = []struct {
+		when       string
+		expectName string
+		expectPort string
+	}{
+		{when: "example.com", expectName: "example.com"},
+		{when: "example.com:8080", expectName: "example.com", expectPort: "8080"},
+		{when: "[::1]", expectName: "[::1]"},
+		{when: "[::1]:8080", expectName: "[::1]", expectPort: "8080"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.when, func(t *testing.T) {
+			name, port := splitHostPort(tc.when)
+			assert.Equal(t, tc.expectName, name)
+			assert.Equal(t, tc.expectPort, port)
+		})
+	}
+}
+
+func TestEchoHost_patterns(t *testing.T) {
+	e := New()
+	hostHandler := func(name string) HandlerFunc {
+		return func(c Context) error {
+			return c.String(http.StatusOK, name+" tenant="+c.HostParam("tenant")+" env="+c.HostParam("env"))
+		}
+	}
+	e.GET("/", hostHandler("default"))
+	e.Host("www.example.com").GET("/", hostHandler("exact"))
+	e.Host("{tenant}.example.com").GET("/", hostHandler("tenant"))
+	e.Host("*.{env}.example.com").GET("/", hostHandler("env"))
+	e.Host("admin.{env}.example.com").GET("/", hostHandler("admin"))
+	e.Host("{tenant}.example.com:8443").GET("/", hostHandler("tenant-tls"))
+
+	var testCases = []struct {
+		whenHost   string
+		expectBody string
+	}{
+		{whenHost: "www.example.com", expectBody: "exact tenant= env="},
+		{whenHost: "www.example.com:8080", expectBody: "tenant tenant=www env="},
+		{whenHost: "acme.example.com", expectBody: "tenant tenant=acme env="},
+		{whenHost: "ACME.Example.com", expectBody: "tenant tenant=ACME env="},
+		{whenHost: "acme.example.com:8080", expectBody: "tenant tenant=acme env="},
+		{whenHost: "acme.example.com:8443", expectBody: "tenant-tls tenant=acme env="},
+		{whenHost: "api.dev.example.com", expectBody: "env tenant= env=dev"},
+		{whenHost: "admin.dev.example.com", expectBody: "admin tenant= env=dev"},
+		{whenHost: "example.com", expectBody: "default tenant= env="},
+		{whenHost: "acme.example.org", expectBody: "default tenant= env="},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.whenHost, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Host = tc.whenHost
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tc.expectBody, rec.Body.String())
+		})
+	}
+}
+
+func TestEchoHost_invalidPattern(t *testing.T) {
+	e := New()
+	assert.PanicsWithValue(t, `echo: invalid host pattern "api-*.example.com"`, func() {
+		e.Host("api-*.example.com")
+	})
+}
+
+func TestEchoHost_patternAddedWithUpdateRoutes(t *testing.T) {
+	e := New()
+	err := e.UpdateRoutes(func() error {
+		e.Host("{tenant}.example.com").GET("/", func(c Context) error {
+			return c.String(http.StatusOK, c.HostParam("tenant"))
+		})
+		return nil
+	})
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Host = "acme.example.com"
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, "acme", rec.Body.String())
+	assert.Contains(t, e.Routers(), "{tenant}.example.com")
+}
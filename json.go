@@ -4,13 +4,58 @@
 package echo
 
 import (
+	"bytes"
+	"encoding"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 )
 
 // DefaultJSONSerializer implements JSON encoding using encoding/json.
-type DefaultJSONSerializer struct{}
+//
+// Zero value decodes request bodies same way as `json.Decoder` does. Fields of the serializer make decoding stricter.
+// Request bodies violating these rules are rejected with `*HTTPError` with status 400, that has `*JSONDecodeError`
+// with the path of the offending field as internal error.
+type DefaultJSONSerializer struct {
+	// DisallowUnknownFields makes decoding fail when JSON object has key that does not match any field of the destination
+	// struct.
+	DisallowUnknownFields bool
+	// DisallowDuplicateKeys makes decoding fail when JSON object has the same key more than once.
+	DisallowDuplicateKeys bool
+	// DisallowTrailingData makes decoding fail when request body has anything else than whitespace after the JSON value.
+	DisallowTrailingData bool
+	// UseNumber decodes numbers to `interface{}` destinations as `json.Number` instead of `float64`.
+	UseNumber bool
+	// MaxDepth limits nesting depth of JSON arrays and objects, i.e. `{"a":[1]}` has depth 2. Zero means no limit.
+	MaxDepth int
+	// MaxTokens limits number of JSON tokens (delimiters, object keys and values) in request body. Zero means no limit.
+	MaxTokens int
+}
+
+// JSONDecodeError is error for JSON request body rejected by one of DefaultJSONSerializer strictness rules.
+type JSONDecodeError struct {
+	// Field is path to offending field, i.e. `items[1].name`. Empty when error is not related to a field.
+	Field string
+	// Offset is number of bytes read from request body when error was detected.
+	Offset int64
+	// Reason describes which rule was violated.
+	Reason string
+}
+
+// Error returns error message.
+func (e *JSONDecodeError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("JSON decode error: %s, offset=%v", e.Reason, e.Offset)
+	}
+	return fmt.Sprintf("JSON decode error: %s, field=%v, offset=%v", e.Reason, e.Field, e.Offset)
+}
 
 // Serialize converts an interface into a json and writes it to the response.
 // You can optionally use the indent parameter to produce pretty JSONs.
@@ -24,11 +69,321 @@ func (d DefaultJSONSerializer) Serialize(c Context, i interface{}, indent string
 
 // Deserialize reads a JSON from a request body and converts it into an interface.
 func (d DefaultJSONSerializer) Deserialize(c Context, i interface{}) error {
-	err := json.NewDecoder(c.Request().Body).Decode(i)
+	var body io.Reader = c.Request().Body
+	if d.DisallowUnknownFields || d.DisallowDuplicateKeys || d.DisallowTrailingData || d.MaxDepth > 0 || d.MaxTokens > 0 {
+		// these rules are checked before decoding, so destination is not partially filled when body is rejected
+		b, err := io.ReadAll(body)
+		if err != nil {
+			return err
+		}
+		if err := d.scan(b, reflect.TypeOf(i)); err != nil {
+			return jsonDecodeError(err)
+		}
+		body = bytes.NewReader(b)
+	}
+
+	dec := json.NewDecoder(body)
+	if d.DisallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+	if d.UseNumber {
+		dec.UseNumber()
+	}
+	err := dec.Decode(i)
+	if err != nil && d.DisallowUnknownFields {
+		// json.Decoder returns unknown field error without a type, i.e. for fields the scan does not know to be unknown
+		if name, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+			if n, uErr := strconv.Unquote(name); uErr == nil {
+				name = n
+			}
+			err = &JSONDecodeError{Field: name, Offset: dec.InputOffset(), Reason: "unknown field"}
+		}
+	}
+	return jsonDecodeError(err)
+}
+
+func jsonDecodeError(err error) error {
+	if err == nil {
+		return nil
+	}
 	if ute, ok := err.(*json.UnmarshalTypeError); ok {
 		return NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unmarshal type error: expected=%v, got=%v, field=%v, offset=%v", ute.Type, ute.Value, ute.Field, ute.Offset)).SetInternal(err)
 	} else if se, ok := err.(*json.SyntaxError); ok {
 		return NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Syntax error: offset=%v, error=%v", se.Offset, se.Error())).SetInternal(err)
+	} else if de, ok := err.(*JSONDecodeError); ok {
+		return NewHTTPError(http.StatusBadRequest, de.Error()).SetInternal(err)
 	}
 	return err
 }
+
+// jsonScanFrame is JSON array or object being scanned.
+type jsonScanFrame struct {
+	path  string
+	typ   reflect.Type // nil when destination type is not known
+	array bool
+	index int
+	keys  map[string]struct{}
+	// key is the last object key, hasKey is true until its value is scanned
+	key      string
+	hasKey   bool
+	elemType reflect.Type
+}
+
+// scan checks JSON value in b against unknown field, duplicate key, trailing data, depth and token count rules. Value
+// is matched against destination type t to find unknown fields.
+func (d DefaultJSONSerializer) scan(b []byte, t reflect.Type) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	var stack []*jsonScanFrame
+	tokens := 0
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		tokens++
+		if d.MaxTokens > 0 && tokens > d.MaxTokens {
+			return &JSONDecodeError{Offset: dec.InputOffset(), Reason: fmt.Sprintf("maximum number of tokens %d exceeded", d.MaxTokens)}
+		}
+
+		var parent *jsonScanFrame
+		if len(stack) > 0 {
+			parent = stack[len(stack)-1]
+		}
+		if parent != nil && !parent.array && !parent.hasKey {
+			if _, ok := token.(json.Delim); ok { // end of object
+				stack = stack[:len(stack)-1]
+				if len(stack) == 0 {
+					return d.scanTrailingData(dec)
+				}
+				continue
+			}
+			if err := d.scanKey(parent, token.(string), dec.InputOffset()); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if delim, ok := token.(json.Delim); ok && delim == ']' {
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				return d.scanTrailingData(dec)
+			}
+			continue
+		}
+
+		path, typ := "", t
+		if parent != nil {
+			path, typ = parent.valuePath(), parent.elemType
+			if parent.array {
+				parent.index++
+			} else {
+				parent.hasKey = false
+			}
+		}
+		typ = jsonDestinationType(typ)
+
+		delim, ok := token.(json.Delim)
+		if !ok {
+			if parent == nil {
+				return d.scanTrailingData(dec) // scalar as top level value
+			}
+			continue
+		}
+		if d.MaxDepth > 0 && len(stack)+1 > d.MaxDepth {
+			return &JSONDecodeError{Field: path, Offset: dec.InputOffset(), Reason: fmt.Sprintf("maximum nesting depth %d exceeded", d.MaxDepth)}
+		}
+		frame := &jsonScanFrame{path: path, typ: typ, array: delim == '['}
+		if frame.array {
+			if typ != nil && (typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array) {
+				frame.elemType = typ.Elem()
+			}
+		} else if d.DisallowDuplicateKeys {
+			frame.keys = map[string]struct{}{}
+		}
+		stack = append(stack, frame)
+	}
+}
+
+func (d DefaultJSONSerializer) scanTrailingData(dec *json.Decoder) error {
+	if !d.DisallowTrailingData {
+		return nil
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return &JSONDecodeError{Offset: dec.InputOffset(), Reason: "unexpected data after JSON value"}
+	}
+	return nil
+}
+
+// scanKey checks object key against duplicate key and unknown field rules and resolves type of its value.
+func (d DefaultJSONSerializer) scanKey(f *jsonScanFrame, key string, offset int64) error {
+	f.key = key
+	f.hasKey = true
+	f.elemType = nil
+	if f.keys != nil {
+		// encoding/json matches keys to struct fields case-insensitively, so `{"name":"a","NAME":"b"}` sets the same field
+		// twice
+		k := key
+		if f.typ != nil && f.typ.Kind() == reflect.Struct {
+			k = strings.ToLower(key)
+		}
+		if _, ok := f.keys[k]; ok {
+			return &JSONDecodeError{Field: f.valuePath(), Offset: offset, Reason: "duplicate key"}
+		}
+		f.keys[k] = struct{}{}
+	}
+	if f.typ == nil {
+		return nil
+	}
+	switch f.typ.Kind() {
+	case reflect.Map:
+		f.elemType = f.typ.Elem()
+	case reflect.Struct:
+		ft, ok := jsonStructFields(f.typ)[strings.ToLower(key)]
+		if !ok && d.DisallowUnknownFields {
+			return &JSONDecodeError{Field: f.valuePath(), Offset: offset, Reason: "unknown field"}
+		}
+		f.elemType = ft
+	}
+	return nil
+}
+
+// valuePath returns path of the value currently scanned in array or object.
+func (f *jsonScanFrame) valuePath() string {
+	if f.array {
+		return f.path + "[" + strconv.Itoa(f.index) + "]"
+	}
+	if f.path == "" {
+		return f.key
+	}
+	return f.path + "." + f.key
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// jsonDestinationType dereferences pointer types. Returns nil for types that unmarshal JSON themselves, as their fields
+// are not known.
+func jsonDestinationType(t reflect.Type) reflect.Type {
+	for t != nil {
+		if t.Implements(jsonUnmarshalerType) || t.Implements(textUnmarshalerType) ||
+			reflect.PointerTo(t).Implements(jsonUnmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+			return nil
+		}
+		switch t.Kind() {
+		case reflect.Pointer:
+			t = t.Elem()
+		case reflect.Interface:
+			return nil
+		default:
+			return t
+		}
+	}
+	return nil
+}
+
+var jsonFieldsCache sync.Map // map[reflect.Type]map[string]reflect.Type
+
+// jsonStructFields returns types of struct fields by lowercase JSON names, including fields of embedded structs same
+// way as encoding/json resolves them.
+func jsonStructFields(t reflect.Type) map[string]reflect.Type {
+	if fields, ok := jsonFieldsCache.Load(t); ok {
+		return fields.(map[string]reflect.Type)
+	}
+	fields := map[string]reflect.Type{}
+	for _, f := range collectJSONFields(t) {
+		name := strings.ToLower(f.name)
+		if _, ok := fields[name]; !ok {
+			fields[name] = f.typ
+		}
+	}
+	jsonFieldsCache.Store(t, fields)
+	return fields
+}
+
+// jsonField is JSON field of struct or of its embedded struct.
+type jsonField struct {
+	name   string
+	typ    reflect.Type
+	tagged bool
+	index  []int
+}
+
+// collectJSONFields returns fields of struct that encoding/json decodes, in order of their index sequence. Fields of
+// embedded structs are visited breadth first and resolved with the same rules as encoding/json does: field at the
+// shallowest depth wins, at the same depth field with JSON tag wins and other fields with the same name are ambiguous
+// and are dropped.
+func collectJSONFields(t reflect.Type) []jsonField {
+	var all []jsonField
+	current := []jsonField{{typ: t}}
+	visited := map[reflect.Type]bool{}
+	for len(current) > 0 {
+		var next []jsonField
+		levelTypes := map[reflect.Type]bool{}
+		for _, s := range current {
+			if visited[s.typ] {
+				continue
+			}
+			levelTypes[s.typ] = true
+			for i := 0; i < s.typ.NumField(); i++ {
+				f := s.typ.Field(i)
+				tag := f.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				index := append(append([]int(nil), s.index...), i)
+				name, _, _ := strings.Cut(tag, ",")
+				if f.Anonymous && name == "" {
+					ft := f.Type
+					if ft.Kind() == reflect.Pointer {
+						ft = ft.Elem()
+					}
+					if ft.Kind() == reflect.Struct {
+						next = append(next, jsonField{typ: ft, index: index})
+						continue
+					}
+				}
+				if !f.IsExported() {
+					continue
+				}
+				tagged := name != ""
+				if !tagged {
+					name = f.Name
+				}
+				all = append(all, jsonField{name: name, typ: f.Type, tagged: tagged, index: index})
+			}
+		}
+		for typ := range levelTypes {
+			visited[typ] = true
+		}
+		current = next
+	}
+
+	sort.SliceStable(all, func(i, j int) bool {
+		if all[i].name != all[j].name {
+			return all[i].name < all[j].name
+		}
+		if len(all[i].index) != len(all[j].index) {
+			return len(all[i].index) < len(all[j].index)
+		}
+		return all[i].tagged && !all[j].tagged
+	})
+	fields := make([]jsonField, 0, len(all))
+	for i := 0; i < len(all); {
+		j := i + 1
+		for j < len(all) && all[j].name == all[i].name {
+			j++
+		}
+		// fields with the same name are sorted by depth and tagged fields first, so the first field is dominant unless
+		// the next one has the same depth and tag status
+		if j == i+1 || len(all[i+1].index) > len(all[i].index) || (all[i].tagged && !all[i+1].tagged) {
+			fields = append(fields, all[i])
+		}
+		i = j
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return slices.Compare(fields[i].index, fields[j].index) < 0
+	})
+	return fields
+}
@@ -4,9 +4,11 @@
 package echo
 
 import (
+	"encoding/json"
 	"github.com/stretchr/testify/assert"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -98,3 +100,164 @@ func TestDefaultJSONCodec_Decode(t *testing.T) {
 	assert.EqualError(t, err, "code=400, message=Unmarshal type error: expected=string, got=number, field=id, offset=7, internal=json: cannot unmarshal number into Go struct field .id of type string")
 
 }
+
+func TestDefaultJSONSerializer_DeserializeStrict(t *testing.T) {
+	type item struct {
+		SKU string `json:"sku"`
+	}
+	type embedded struct {
+		Note string `json:"note"`
+	}
+	type order struct {
+		ID    int                    `json:"id"`
+		Items []item                 `json:"items"`
+		Meta  map[string]interface{} `json:"meta"`
+		Raw   json.RawMessage        `json:"raw"`
+		Any   interface{}            `json:"any"`
+		embedded
+	}
+	var testCases = []struct {
+		name        string
+		given       DefaultJSONSerializer
+		whenBody    string
+		expect      order
+		expectError string
+	}{
+		{
+			name:     "ok, zero value is lenient",
+			whenBody: `{"id":1,"id":2,"unknown":true} garbage`,
+			expect:   order{ID: 2},
+		},
+		{
+			name:     "ok, all rules",
+			given:    DefaultJSONSerializer{DisallowUnknownFields: true, DisallowDuplicateKeys: true, DisallowTrailingData: true, MaxDepth: 3, MaxTokens: 40},
+			whenBody: `{"ID":1,"items":[{"sku":"A"}],"meta":{"x":1},"raw":{"a":{"b":1}},"any":{"c":1},"note":"n"} `,
+			expect: order{
+				ID:       1,
+				Items:    []item{{SKU: "A"}},
+				Meta:     map[string]interface{}{"x": float64(1)},
+				Raw:      json.RawMessage(`{"a":{"b":1}}`),
+				Any:      map[string]interface{}{"c": float64(1)},
+				embedded: embedded{Note: "n"},
+			},
+		},
+		{
+			name:     "ok, use number",
+			given:    DefaultJSONSerializer{UseNumber: true},
+			whenBody: `{"meta":{"x":1.5}}`,
+			expect:   order{Meta: map[string]interface{}{"x": json.Number("1.5")}},
+		},
+		{
+			name:        "nok, unknown field",
+			given:       DefaultJSONSerializer{DisallowUnknownFields: true},
+			whenBody:    `{"id":1,"items":[{"sku":"A"},{"sku":"B","qty":2}]}`,
+			expectError: "code=400, message=JSON decode error: unknown field, field=items[1].qty, offset=45, internal=JSON decode error: unknown field, field=items[1].qty, offset=45",
+		},
+		{
+			name:        "nok, duplicate key",
+			given:       DefaultJSONSerializer{DisallowDuplicateKeys: true},
+			whenBody:    `{"meta":{"x":1,"x":2}}`,
+			expectError: "code=400, message=JSON decode error: duplicate key, field=meta.x, offset=18, internal=JSON decode error: duplicate key, field=meta.x, offset=18",
+		},
+		{
+			name:        "nok, duplicate key differing in case",
+			given:       DefaultJSONSerializer{DisallowDuplicateKeys: true},
+			whenBody:    `{"note":"a","NOTE":"b"}`,
+			expectError: "code=400, message=JSON decode error: duplicate key, field=NOTE, offset=18, internal=JSON decode error: duplicate key, field=NOTE, offset=18",
+		},
+		{
+			name:        "nok, trailing data",
+			given:       DefaultJSONSerializer{DisallowTrailingData: true},
+			whenBody:    `{"id":1}{"id":2}`,
+			expectError: "code=400, message=JSON decode error: unexpected data after JSON value, offset=9, internal=JSON decode error: unexpected data after JSON value, offset=9",
+		},
+		{
+			name:        "nok, max depth",
+			given:       DefaultJSONSerializer{MaxDepth: 2},
+			whenBody:    `{"any":[[1]]}`,
+			expectError: "code=400, message=JSON decode error: maximum nesting depth 2 exceeded, field=any[0], offset=9, internal=JSON decode error: maximum nesting depth 2 exceeded, field=any[0], offset=9",
+		},
+		{
+			name:        "nok, max tokens",
+			given:       DefaultJSONSerializer{MaxTokens: 4},
+			whenBody:    `{"id":1,"items":[]}`,
+			expectError: "code=400, message=JSON decode error: maximum number of tokens 4 exceeded, offset=17, internal=JSON decode error: maximum number of tokens 4 exceeded, offset=17",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.whenBody))
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			var dest order
+			err := tc.given.Deserialize(c, &dest)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+				assert.Equal(t, order{}, dest)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, dest)
+		})
+	}
+}
+
+func TestDefaultJSONSerializer_DeserializeStrictUnknownFieldOfInterface(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"id":1,"qty":2}`))
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	// fields of struct behind interface are not known before decoding, so the unknown field is found by json.Decoder
+	var dest interface{} = &user{}
+	err := DefaultJSONSerializer{DisallowUnknownFields: true}.Deserialize(c, &dest)
+	assert.EqualError(t, err, "code=400, message=JSON decode error: unknown field, field=qty, offset=16, internal=JSON decode error: unknown field, field=qty, offset=16")
+	var de *JSONDecodeError
+	assert.ErrorAs(t, err, &de)
+}
+
+func TestJSONStructFields(t *testing.T) {
+	type a struct {
+		X    string
+		Name string
+	}
+	type b struct {
+		X     string
+		Title string
+	}
+	type c struct {
+		Y string `json:"y"`
+	}
+	type d struct {
+		Y string
+	}
+	type dest struct {
+		a
+		b
+		c
+		d
+		ID int `json:"id"`
+	}
+
+	fields := jsonStructFields(reflect.TypeOf(dest{}))
+
+	// X is ambiguous at the same depth and is dropped as encoding/json does
+	assert.Equal(t, map[string]reflect.Type{
+		"name":  reflect.TypeOf(""),
+		"title": reflect.TypeOf(""),
+		"y":     reflect.TypeOf(""),
+		"id":    reflect.TypeOf(0),
+	}, fields)
+}
+
+func TestDefaultJSONSerializer_DeserializeStrictSyntaxError(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"id":}`))
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	err := DefaultJSONSerializer{MaxTokens: 10}.Deserialize(c, &user{})
+	var he *HTTPError
+	assert.ErrorAs(t, err, &he)
+	assert.Equal(t, http.StatusBadRequest, he.Code)
+	assert.ErrorAs(t, err, new(*json.SyntaxError))
+}
@@ -4,6 +4,7 @@
 package echo
 
 import (
+	stdContext "context"
 	"log"
 	"log/slog"
 )
@@ -14,6 +15,35 @@ type Logger interface {
 	Info(msg string, args ...any)
 	Warn(msg string, args ...any)
 	Error(msg string, args ...any)
+	// With returns child logger that includes given attributes (key-value pairs) in every message.
+	With(args ...any) Logger
+}
+
+// LogLevel is level of the log message.
+type LogLevel uint8
+
+// Log levels
+const (
+	// LogLevelNone means that message is not logged.
+	LogLevelNone LogLevel = iota
+	LogLevelDebug
+	LogLevelInfo
+	LogLevelWarn
+	LogLevelError
+)
+
+// logWithLevel logs message with Logger method that matches the level.
+func logWithLevel(l Logger, level LogLevel, msg string, args ...any) {
+	switch level {
+	case LogLevelDebug:
+		l.Debug(msg, args...)
+	case LogLevelInfo:
+		l.Info(msg, args...)
+	case LogLevelWarn:
+		l.Warn(msg, args...)
+	case LogLevelError:
+		l.Error(msg, args...)
+	}
 }
 
 // LoggerWriter implements the io.Writer interface.
@@ -35,11 +65,65 @@ func (e *Echo) SetLogger(logger Logger) {
 	e.StdLogger = log.New(&LoggerWriter{logger}, "echo: ", 0)
 }
 
+// DefaultRequestLogger creates request logger from `Echo.Logger` with request ID, method, route path and remote IP
+// attributes. Request ID is taken from response or request `X-Request-Id` header and is omitted when request does not
+// have it, so `RequestID` middleware should be executed before the logger is first used.
+func DefaultRequestLogger(c Context) Logger {
+	req := c.Request()
+	args := make([]any, 0, 8)
+	rid := c.Response().Header().Get(HeaderXRequestID)
+	if rid == "" {
+		rid = req.Header.Get(HeaderXRequestID)
+	}
+	if rid != "" {
+		args = append(args, "request_id", rid)
+	}
+	args = append(args, "method", req.Method, "route", c.Path(), "remote_ip", c.RealIP())
+	return c.Echo().Logger.With(args...)
+}
+
+type loggerContextKey struct{}
+
+// ContextWithLogger returns copy of the context that carries the logger.
+func ContextWithLogger(ctx stdContext.Context, l Logger) stdContext.Context {
+	return stdContext.WithValue(ctx, loggerContextKey{}, l)
+}
+
+// LoggerFromContext returns logger carried by the context. When `Echo.RequestLogger` is set, request context carries
+// the request logger for the route handler. Request logger is created when route handler is entered, so group and
+// route middleware, i.e. `RequestID`, affect it the same way as they affect `Context.Logger`. Returns false when
+// context does not have logger.
+func LoggerFromContext(ctx stdContext.Context) (Logger, bool) {
+	l, ok := ctx.Value(loggerContextKey{}).(Logger)
+	return l, ok
+}
+
+// withRequestLogger adds request logger to the request context before the handler is executed. Logger is created on
+// the request goroutine, so goroutines started by handler with request context do not access the context.
+func withRequestLogger(h HandlerFunc) HandlerFunc {
+	return func(c Context) error {
+		l := c.Logger()
+		r := c.Request()
+		c.SetRequest(r.WithContext(ContextWithLogger(r.Context(), l)))
+		return h(c)
+	}
+}
+
 // SlogLogger is an implementation of the Logger interface based on
 type SlogLogger struct {
 	Logger *slog.Logger
 }
 
+// With returns child logger with given attributes. If a custom logger is not set, the default slog logger is used as
+// parent.
+func (l *SlogLogger) With(args ...any) Logger {
+	parent := l.Logger
+	if parent == nil {
+		parent = slog.Default()
+	}
+	return &SlogLogger{Logger: parent.With(args...)}
+}
+
 // Debug logs a debug-level message. If a custom logger is set, it uses
 // that logger to log the message; otherwise, it falls back to the
 // default slog package. The message and any additional arguments are
@@ -0,0 +1,165 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"bytes"
+	stdContext "context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlogLogger_With(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := &SlogLogger{Logger: slog.New(slog.NewTextHandler(buf, nil))}
+
+	child := l.With("user", "jon")
+	child.Info("hello", "n", 1)
+	l.Info("parent")
+
+	assert.Equal(t, "level=INFO msg=hello user=jon n=1\nlevel=INFO msg=parent\n", stripLogTime(buf.String()))
+}
+
+func TestDefaultRequestLogger(t *testing.T) {
+	buf := new(bytes.Buffer)
+	e := New()
+	e.SetLogger(&SlogLogger{Logger: slog.New(slog.NewTextHandler(buf, nil))})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	req.Header.Set(HeaderXRequestID, "abc")
+	req.RemoteAddr = "192.0.2.1:1234"
+	c := e.NewContext(req, httptest.NewRecorder())
+	c.SetPath("/users/:id")
+
+	DefaultRequestLogger(c).Info("hello")
+
+	assert.Equal(t, "level=INFO msg=hello request_id=abc method=GET route=/users/:id remote_ip=192.0.2.1\n", stripLogTime(buf.String()))
+}
+
+func TestEcho_RequestLogger(t *testing.T) {
+	buf := new(bytes.Buffer)
+	e := New()
+	e.SetLogger(&SlogLogger{Logger: slog.New(slog.NewTextHandler(buf, nil))})
+	e.RequestLogger = DefaultRequestLogger
+
+	e.Use(func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			c.Response().Header().Set(HeaderXRequestID, "generated")
+			return next(c)
+		}
+	})
+	e.GET("/users/:id", func(c Context) error {
+		c.Logger().Info("handler")
+		l, ok := LoggerFromContext(c.Request().Context())
+		assert.True(t, ok)
+		assert.Same(t, c.Logger(), l)
+		l.Info("from context")
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	expect := "level=INFO msg=handler request_id=generated method=GET route=/users/:id remote_ip=192.0.2.1\n" +
+		"level=INFO msg=\"from context\" request_id=generated method=GET route=/users/:id remote_ip=192.0.2.1\n"
+	assert.Equal(t, expect, stripLogTime(buf.String()))
+}
+
+func TestEcho_RequestLoggerWithGroupMiddleware(t *testing.T) {
+	buf := new(bytes.Buffer)
+	e := New()
+	e.SetLogger(&SlogLogger{Logger: slog.New(slog.NewTextHandler(buf, nil))})
+	e.RequestLogger = DefaultRequestLogger
+
+	g := e.Group("/api", func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			c.Response().Header().Set(HeaderXRequestID, "group")
+			return next(c)
+		}
+	})
+	var ctx stdContext.Context
+	g.GET("/users/:id", func(c Context) error {
+		ctx = c.Request().Context()
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/users/1", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	// logger is used after request has ended, i.e. by goroutine started by handler
+	l, ok := LoggerFromContext(ctx)
+	assert.True(t, ok)
+	l.Info("after request")
+
+	expect := "level=INFO msg=\"after request\" request_id=group method=GET route=/api/users/:id remote_ip=192.0.2.1\n"
+	assert.Equal(t, expect, stripLogTime(buf.String()))
+}
+
+func TestEcho_RequestLoggerUsedByGoroutine(t *testing.T) {
+	buf := new(bytes.Buffer)
+	e := New()
+	e.SetLogger(&SlogLogger{Logger: slog.New(slog.NewTextHandler(buf, nil))})
+	e.RequestLogger = DefaultRequestLogger
+
+	e.GET("/", func(c Context) error {
+		done := make(chan struct{})
+		go func(ctx stdContext.Context) {
+			defer close(done)
+			l, _ := LoggerFromContext(ctx)
+			l.Info("goroutine")
+		}(c.Request().Context())
+		c.Response().Header().Set(HeaderXRequestID, "late")
+		<-done
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "level=INFO msg=goroutine method=GET route=/ remote_ip=192.0.2.1\n", stripLogTime(buf.String()))
+}
+
+func TestEcho_RequestLoggerNotSet(t *testing.T) {
+	e := New()
+	e.GET("/", func(c Context) error {
+		assert.Same(t, e.Logger, c.Logger())
+		_, ok := LoggerFromContext(c.Request().Context())
+		assert.False(t, ok)
+		return nil
+	})
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+func TestLoggerFromContext(t *testing.T) {
+	_, ok := LoggerFromContext(stdContext.Background())
+	assert.False(t, ok)
+
+	l := &SlogLogger{}
+	result, ok := LoggerFromContext(ContextWithLogger(stdContext.Background(), l))
+	assert.True(t, ok)
+	assert.Same(t, l, result)
+}
+
+// stripLogTime removes `time=...` attribute from text handler output.
+func stripLogTime(s string) string {
+	var out bytes.Buffer
+	for _, line := range bytes.SplitAfter([]byte(s), []byte("\n")) {
+		if bytes.HasPrefix(line, []byte("time=")) {
+			if i := bytes.IndexByte(line, ' '); i >= 0 {
+				line = line[i+1:]
+			}
+		}
+		out.Write(line)
+	}
+	return out.String()
+}
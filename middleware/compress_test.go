@@ -365,3 +365,48 @@ func BenchmarkGzip(b *testing.B) {
 		h(c)
 	}
 }
+
+func TestGzipEventStream(t *testing.T) {
+	e := echo.New()
+	e.Use(Gzip())
+	e.GET("/", func(c echo.Context) error {
+		return c.EventStream(func(es *echo.EventStream) error {
+			if err := es.Send(echo.Event{ID: "1", Data: "first"}); err != nil {
+				return err
+			}
+			return es.Send(echo.Event{ID: "2", Data: "second"})
+		})
+	})
+	e.GET("/empty", func(c echo.Context) error {
+		return c.EventStream(func(es *echo.EventStream) error {
+			return nil
+		})
+	})
+
+	for _, tc := range []struct {
+		whenURL    string
+		expectBody string
+	}{
+		{whenURL: "/", expectBody: "id: 1\ndata: first\n\nid: 2\ndata: second\n\n"},
+		{whenURL: "/empty", expectBody: ""},
+	} {
+		t.Run(tc.whenURL, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.whenURL, nil)
+			req.Header.Set(echo.HeaderAcceptEncoding, gzipScheme)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.True(t, rec.Flushed)
+			assert.Equal(t, echo.MIMETextEventStream, rec.Header().Get(echo.HeaderContentType))
+			assert.Equal(t, gzipScheme, rec.Header().Get(echo.HeaderContentEncoding))
+
+			r, err := gzip.NewReader(rec.Body)
+			if assert.NoError(t, err) {
+				b, err := io.ReadAll(r)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectBody, string(b))
+			}
+		})
+	}
+}
@@ -4,8 +4,12 @@
 package middleware
 
 import (
+	"bufio"
 	"context"
 	"errors"
+	"net"
+	"net/http"
+	"sync"
 	"time"
 
 	"github.com/labstack/echo/v4"
@@ -25,6 +29,10 @@ type ContextTimeoutConfig struct {
 
 // ContextTimeout returns a middleware which returns error (503 Service Unavailable error) to client
 // when underlying method returns context.DeadlineExceeded error.
+//
+// Deadline is removed from request context when handler flushes the response before the deadline, as flushed response
+// is a stream that can not be answered with 503 anymore. This way Server-Sent Events sent with `Context.EventStream`
+// last until the client disconnects or handler returns.
 func ContextTimeout(timeout time.Duration) echo.MiddlewareFunc {
 	return ContextTimeoutWithConfig(ContextTimeoutConfig{Timeout: timeout})
 }
@@ -61,10 +69,14 @@ func (config ContextTimeoutConfig) ToMiddleware() (echo.MiddlewareFunc, error) {
 				return next(c)
 			}
 
-			timeoutContext, cancel := context.WithTimeout(c.Request().Context(), config.Timeout)
-			defer cancel()
+			timeoutContext := newDeadlineContext(c.Request().Context(), config.Timeout)
+			defer timeoutContext.cancel(context.Canceled)
 
 			c.SetRequest(c.Request().WithContext(timeoutContext))
+			res := c.Response()
+			originalWriter := res.Writer
+			res.Writer = &streamDetectingWriter{ResponseWriter: originalWriter, ctx: timeoutContext}
+			defer func() { res.Writer = originalWriter }()
 
 			if err := next(c); err != nil {
 				return config.ErrorHandler(err, c)
@@ -73,3 +85,106 @@ func (config ContextTimeoutConfig) ToMiddleware() (echo.MiddlewareFunc, error) {
 		}
 	}, nil
 }
+
+// deadlineContext is context that is cancelled when its deadline passes, like context created with
+// `context.WithTimeout`, but its deadline can be removed before it passes.
+type deadlineContext struct {
+	context.Context // parent
+
+	deadline   time.Time
+	done       chan struct{}
+	timer      *time.Timer
+	stopParent func() bool
+
+	lock    sync.Mutex
+	err     error
+	stopped bool
+}
+
+func newDeadlineContext(parent context.Context, timeout time.Duration) *deadlineContext {
+	ctx := &deadlineContext{
+		Context:  parent,
+		deadline: time.Now().Add(timeout),
+		done:     make(chan struct{}),
+	}
+	// lock makes cancel, that can be called by timer or parent immediately, wait until fields are set
+	ctx.lock.Lock()
+	defer ctx.lock.Unlock()
+	ctx.timer = time.AfterFunc(timeout, func() { ctx.cancel(context.DeadlineExceeded) })
+	ctx.stopParent = context.AfterFunc(parent, func() { ctx.cancel(parent.Err()) })
+	return ctx
+}
+
+func (c *deadlineContext) Deadline() (time.Time, bool) {
+	c.lock.Lock()
+	stopped := c.stopped
+	c.lock.Unlock()
+
+	parentDeadline, ok := c.Context.Deadline()
+	if stopped || (ok && parentDeadline.Before(c.deadline)) {
+		return parentDeadline, ok
+	}
+	return c.deadline, true
+}
+
+func (c *deadlineContext) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *deadlineContext) Err() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.err
+}
+
+// stopDeadline removes deadline from the context. Returns false when context is already cancelled.
+func (c *deadlineContext) stopDeadline() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.err != nil {
+		return false
+	}
+	if !c.stopped && !c.timer.Stop() {
+		return false // deadline has passed, timer is about to cancel the context
+	}
+	c.stopped = true
+	return true
+}
+
+func (c *deadlineContext) cancel(err error) {
+	c.lock.Lock()
+	if c.err != nil {
+		c.lock.Unlock()
+		return
+	}
+	c.err = err
+	close(c.done)
+	timer, stopParent := c.timer, c.stopParent
+	c.lock.Unlock()
+
+	timer.Stop()
+	stopParent()
+}
+
+// streamDetectingWriter removes deadline of the context when response is flushed.
+type streamDetectingWriter struct {
+	http.ResponseWriter
+	ctx *deadlineContext
+}
+
+func (w *streamDetectingWriter) Flush() {
+	_ = w.FlushError()
+}
+
+func (w *streamDetectingWriter) FlushError() error {
+	w.ctx.stopDeadline()
+	return http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func (w *streamDetectingWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+func (w *streamDetectingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
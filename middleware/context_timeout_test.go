@@ -226,3 +226,55 @@ func sleepWithContext(ctx context.Context, d time.Duration) error {
 		return nil
 	}
 }
+
+func TestContextTimeoutEventStream(t *testing.T) {
+	var testCases = []struct {
+		name           string
+		whenStartAfter time.Duration
+		expectErr      string
+		expectBody     string
+	}{
+		{
+			name:       "ok, stream started before deadline outlives timeout",
+			expectBody: "data: first\n\ndata: second\n\n",
+		},
+		{
+			name:           "nok, handler times out before stream is started",
+			whenStartAfter: 50 * time.Millisecond,
+			expectErr:      "code=503, message=Service Unavailable, internal=context deadline exceeded",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := ContextTimeoutWithConfig(ContextTimeoutConfig{
+				Timeout: 10 * time.Millisecond,
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/events", nil)
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(req, rec)
+
+			err := m(func(c echo.Context) error {
+				if err := sleepWithContext(c.Request().Context(), tc.whenStartAfter); err != nil {
+					return err
+				}
+				return c.EventStream(func(es *echo.EventStream) error {
+					if err := es.Send(echo.Event{Data: "first"}); err != nil {
+						return err
+					}
+					time.Sleep(30 * time.Millisecond) // longer than timeout
+					_, hasDeadline := c.Request().Context().Deadline()
+					assert.False(t, hasDeadline)
+					return es.Send(echo.Event{Data: "second"})
+				})
+			})(c)
+
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectBody, rec.Body.String())
+		})
+	}
+}
@@ -5,7 +5,6 @@ package middleware
 
 import (
 	"net/http"
-	"regexp"
 	"strconv"
 	"strings"
 
@@ -147,26 +146,7 @@ func CORSWithConfig(config CORSConfig) echo.MiddlewareFunc {
 		config.AllowMethods = DefaultCORSConfig.AllowMethods
 	}
 
-	allowOriginPatterns := make([]*regexp.Regexp, 0, len(config.AllowOrigins))
-	for _, origin := range config.AllowOrigins {
-		if origin == "*" {
-			continue // "*" is handled differently and does not need regexp
-		}
-		pattern := regexp.QuoteMeta(origin)
-		pattern = strings.ReplaceAll(pattern, "\\*", ".*")
-		pattern = strings.ReplaceAll(pattern, "\\?", ".")
-		pattern = "^" + pattern + "$"
-
-		re, err := regexp.Compile(pattern)
-		if err != nil {
-			// this is to preserve previous behaviour - invalid patterns were just ignored.
-			// If we would turn this to panic, users with invalid patterns
-			// would have applications crashing in production due unrecovered panic.
-			// TODO: this should be turned to error/panic in `v5`
-			continue
-		}
-		allowOriginPatterns = append(allowOriginPatterns, re)
-	}
+	allowOriginMatcher := echo.NewOriginMatcher(config.AllowOrigins)
 
 	allowMethods := strings.Join(config.AllowMethods, ",")
 	allowHeaders := strings.Join(config.AllowHeaders, ",")
@@ -227,35 +207,9 @@ func CORSWithConfig(config CORSConfig) echo.MiddlewareFunc {
 				}
 			} else {
 				// Check allowed origins
-				for _, o := range config.AllowOrigins {
-					if o == "*" && config.AllowCredentials && config.UnsafeWildcardOriginWithAllowCredentials {
-						allowOrigin = origin
-						break
-					}
-					if o == "*" || o == origin {
-						allowOrigin = o
-						break
-					}
-					if matchSubdomain(origin, o) {
-						allowOrigin = origin
-						break
-					}
-				}
-
-				checkPatterns := false
-				if allowOrigin == "" {
-					// to avoid regex cost by invalid (long) domains (253 is domain name max limit)
-					if len(origin) <= (253+3+5) && strings.Contains(origin, "://") {
-						checkPatterns = true
-					}
-				}
-				if checkPatterns {
-					for _, re := range allowOriginPatterns {
-						if match := re.MatchString(origin); match {
-							allowOrigin = origin
-							break
-						}
-					}
+				allowOrigin = allowOriginMatcher.Match(origin)
+				if allowOrigin == "*" && config.AllowCredentials && config.UnsafeWildcardOriginWithAllowCredentials {
+					allowOrigin = origin
 				}
 			}
 
@@ -31,7 +31,7 @@ type CSRFConfig struct {
 	// - "header:X-CSRF-Token,query:csrf"
 	TokenLookup string `yaml:"token_lookup"`
 
-	// Context key to store generated CSRF token into context.
+	// Context key to store generated CSRF token into context. Token is also available with `CSRFTokenKey`.
 	// Optional. Default value "csrf".
 	ContextKey string `yaml:"context_key"`
 
@@ -73,6 +73,10 @@ type CSRFErrorHandler func(err error, c echo.Context) error
 // ErrCSRFInvalid is returned when CSRF check fails
 var ErrCSRFInvalid = echo.NewHTTPError(http.StatusForbidden, "invalid csrf token")
 
+// CSRFTokenKey is context key of the CSRF token generated or validated by CSRF middleware. Token is also readable
+// with `Context.Get("csrf")`.
+var CSRFTokenKey = echo.NewContextKey[string]("csrf")
+
 // DefaultCSRFConfig is the default CSRF middleware config.
 var DefaultCSRFConfig = CSRFConfig{
 	Skipper:        DefaultSkipper,
@@ -203,7 +207,10 @@ func CSRFWithConfig(config CSRFConfig) echo.MiddlewareFunc {
 			c.SetCookie(cookie)
 
 			// Store token in the context
-			c.Set(config.ContextKey, token)
+			CSRFTokenKey.Set(c, token)
+			if config.ContextKey != CSRFTokenKey.Name() {
+				c.Set(config.ContextKey, token)
+			}
 
 			// Protect clients from caching the response
 			c.Response().Header().Add(echo.HeaderVary, echo.HeaderCookie)
@@ -241,6 +241,22 @@ func TestCSRF(t *testing.T) {
 	if assert.NoError(t, h(c)) {
 		assert.Equal(t, http.StatusOK, rec.Code)
 	}
+	assert.Equal(t, token, CSRFTokenKey.Value(c))
+	assert.Equal(t, token, c.Get("csrf"))
+}
+
+func TestCSRF_customContextKey(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+	h := CSRFWithConfig(CSRFConfig{ContextKey: "token"})(func(c echo.Context) error {
+		return c.String(http.StatusOK, "test")
+	})
+
+	assert.NoError(t, h(c))
+	token := CSRFTokenKey.Value(c)
+	assert.Len(t, token, 32)
+	assert.Equal(t, token, c.Get("token"))
 }
 
 func TestCSRFSetSameSiteMode(t *testing.T) {
@@ -61,6 +61,10 @@ type ErrKeyAuthMissing struct {
 	Err error
 }
 
+// KeyAuthKey is context key of the key that KeyAuth middleware validated for the request. Key is also readable with
+// `Context.Get("key_auth")`.
+var KeyAuthKey = echo.NewContextKey[string]("key_auth")
+
 // DefaultKeyAuthConfig is the default KeyAuth middleware config.
 var DefaultKeyAuthConfig = KeyAuthConfig{
 	Skipper:    DefaultSkipper,
@@ -133,6 +137,7 @@ func KeyAuthWithConfig(config KeyAuthConfig) echo.MiddlewareFunc {
 						continue
 					}
 					if valid {
+						KeyAuthKey.Set(c, key)
 						return next(c)
 					}
 					lastValidatorErr = errors.New("invalid key")
@@ -43,6 +43,7 @@ func TestKeyAuth(t *testing.T) {
 
 	assert.NoError(t, err)
 	assert.True(t, handlerCalled)
+	assert.Equal(t, "valid-key", KeyAuthKey.Value(c))
 }
 
 func TestKeyAuthWithConfig(t *testing.T) {
@@ -75,7 +75,7 @@ type ProxyConfig struct {
 	// "^/api/.+?/(.*)":    "/v2/$1",
 	RegexRewrite map[*regexp.Regexp]string
 
-	// Context key to store selected ProxyTarget into context.
+	// Context key to store selected ProxyTarget into context. Target is also available with `ProxyTargetKey`.
 	// Optional. Default value "target".
 	ContextKey string
 
@@ -125,6 +125,17 @@ type roundRobinBalancer struct {
 	i int
 }
 
+var (
+	// ProxyTargetKey is context key of ProxyTarget selected for the request by Proxy middleware. Target is also readable
+	// with `Context.Get("target")`.
+	ProxyTargetKey = echo.NewContextKey[*ProxyTarget]("target")
+
+	// proxyErrorKey is context key of error that occurred while proxying the request to the target.
+	proxyErrorKey = echo.NewContextKey[error]("_error")
+	// roundRobinLastIndexKey is context key of index of the target round-robin balancer selected for the request.
+	roundRobinLastIndexKey = echo.NewContextKey[int]("_round_robin_last_index")
+)
+
 // DefaultProxyConfig is the default Proxy middleware config.
 var DefaultProxyConfig = ProxyConfig{
 	Skipper:    DefaultSkipper,
@@ -149,20 +160,20 @@ func proxyRaw(t *ProxyTarget, c echo.Context, config ProxyConfig) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		in, _, err := c.Response().Hijack()
 		if err != nil {
-			c.Set("_error", fmt.Errorf("proxy raw, hijack error=%w, url=%s", err, t.URL))
+			proxyErrorKey.Set(c, fmt.Errorf("proxy raw, hijack error=%w, url=%s", err, t.URL))
 			return
 		}
 		defer in.Close()
 		out, err := dialFunc(c.Request().Context(), "tcp", t.URL.Host)
 		if err != nil {
-			c.Set("_error", echo.NewHTTPError(http.StatusBadGateway, fmt.Sprintf("proxy raw, dial error=%v, url=%s", err, t.URL)))
+			proxyErrorKey.Set(c, echo.NewHTTPError(http.StatusBadGateway, fmt.Sprintf("proxy raw, dial error=%v, url=%s", err, t.URL)))
 			return
 		}
 
 		// Write header
 		err = r.Write(out)
 		if err != nil {
-			c.Set("_error", echo.NewHTTPError(http.StatusBadGateway, fmt.Sprintf("proxy raw, request header copy error=%v, url=%s", err, t.URL)))
+			proxyErrorKey.Set(c, echo.NewHTTPError(http.StatusBadGateway, fmt.Sprintf("proxy raw, request header copy error=%v, url=%s", err, t.URL)))
 			return
 		}
 
@@ -176,7 +187,7 @@ func proxyRaw(t *ProxyTarget, c echo.Context, config ProxyConfig) http.Handler {
 		go cp(in, out)
 		err = <-errCh
 		if err != nil && err != io.EOF {
-			c.Set("_error", fmt.Errorf("proxy raw, copy body error=%w, url=%s", err, t.URL))
+			proxyErrorKey.Set(c, fmt.Errorf("proxy raw, copy body error=%w, url=%s", err, t.URL))
 		}
 	})
 }
@@ -257,13 +268,11 @@ func (b *roundRobinBalancer) Next(c echo.Context) *ProxyTarget {
 		return b.targets[0]
 	}
 
-	var i int
-	const lastIdxKey = "_round_robin_last_index"
 	// This request is a retry, start from the index of the previous
 	// target to ensure we don't attempt to retry the request with
 	// the same failed target
-	if c.Get(lastIdxKey) != nil {
-		i = c.Get(lastIdxKey).(int)
+	i, ok := roundRobinLastIndexKey.Get(c)
+	if ok {
 		i++
 		if i >= len(b.targets) {
 			i = 0
@@ -277,7 +286,7 @@ func (b *roundRobinBalancer) Next(c echo.Context) *ProxyTarget {
 		b.i++
 	}
 
-	c.Set(lastIdxKey, i)
+	roundRobinLastIndexKey.Set(c, i)
 	return b.targets[i]
 }
 
@@ -362,13 +371,16 @@ func ProxyWithConfig(config ProxyConfig) echo.MiddlewareFunc {
 					tgt = config.Balancer.Next(c)
 				}
 
-				c.Set(config.ContextKey, tgt)
+				ProxyTargetKey.Set(c, tgt)
+				if config.ContextKey != ProxyTargetKey.Name() {
+					c.Set(config.ContextKey, tgt)
+				}
 
 				//If retrying a failed request, clear any previous errors from
 				//context here so that balancers have the option to check for
 				//errors that occurred using previous target
 				if retries < config.RetryCount {
-					c.Set("_error", nil)
+					proxyErrorKey.Delete(c)
 				}
 
 				// This is needed for ProxyConfig.ModifyResponse and/or ProxyConfig.Transport to be able to process the Request
@@ -383,8 +395,8 @@ func ProxyWithConfig(config ProxyConfig) echo.MiddlewareFunc {
 					proxyHTTP(tgt, c, config).ServeHTTP(res, req)
 				}
 
-				err, hasError := c.Get("_error").(error)
-				if !hasError {
+				err, hasError := proxyErrorKey.Get(c)
+				if !hasError || err == nil {
 					return nil
 				}
 
@@ -421,11 +433,11 @@ func proxyHTTP(tgt *ProxyTarget, c echo.Context, config ProxyConfig) http.Handle
 		if err == context.Canceled || strings.Contains(err.Error(), "operation was canceled") {
 			httpError := echo.NewHTTPError(StatusCodeContextCanceled, fmt.Sprintf("client closed connection: %v", err))
 			httpError.Internal = err
-			c.Set("_error", httpError)
+			proxyErrorKey.Set(c, httpError)
 		} else {
 			httpError := echo.NewHTTPError(http.StatusBadGateway, fmt.Sprintf("remote %s unreachable, could not forward: %v", desc, err))
 			httpError.Internal = err
-			c.Set("_error", httpError)
+			proxyErrorKey.Set(c, httpError)
 		}
 	}
 	proxy.Transport = config.Transport
@@ -115,6 +115,9 @@ func TestProxy(t *testing.T) {
 		return func(c echo.Context) (err error) {
 			next(c)
 			assert.Contains(t, targets, c.Get("target"), "target is not set in context")
+			target, ok := ProxyTargetKey.Get(c)
+			assert.True(t, ok)
+			assert.Contains(t, targets, target)
 			return nil
 		}
 	}
@@ -23,6 +23,10 @@ type RequestIDConfig struct {
 	TargetHeader string
 }
 
+// RequestIDKey is context key of the request id set by RequestID middleware. Request id is also readable with
+// `Context.Get("request_id")`.
+var RequestIDKey = echo.NewContextKey[string]("request_id")
+
 // DefaultRequestIDConfig is the default RequestID middleware config.
 var DefaultRequestIDConfig = RequestIDConfig{
 	Skipper:      DefaultSkipper,
@@ -61,6 +65,7 @@ func RequestIDWithConfig(config RequestIDConfig) echo.MiddlewareFunc {
 				rid = config.Generator()
 			}
 			res.Header().Set(config.TargetHeader, rid)
+			RequestIDKey.Set(c, rid)
 			if config.RequestIDHandler != nil {
 				config.RequestIDHandler(c, rid)
 			}
@@ -25,6 +25,8 @@ func TestRequestID(t *testing.T) {
 	h := rid(handler)
 	h(c)
 	assert.Len(t, rec.Header().Get(echo.HeaderXRequestID), 32)
+	assert.Equal(t, rec.Header().Get(echo.HeaderXRequestID), RequestIDKey.Value(c))
+	assert.Equal(t, RequestIDKey.Value(c), c.Get("request_id"))
 
 	// Custom generator and handler
 	customID := "customGenerator"
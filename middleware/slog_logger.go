@@ -0,0 +1,291 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// SlogRequestLoggerConfig is configuration for slog request logger middleware. Request values are extracted by
+// Request Logger middleware (see `RequestLoggerConfig`) and logged with `log/slog` as typed attributes.
+//
+// Example:
+//
+//	e.Use(middleware.SlogRequestLoggerWithConfig(middleware.SlogRequestLoggerConfig{
+//		RequestLoggerConfig: middleware.RequestLoggerConfig{
+//			LogMethod:  true,
+//			LogURI:     true,
+//			LogStatus:  true,
+//			LogLatency: true,
+//			LogError:   true,
+//			LogHeaders: []string{"Authorization"},
+//		},
+//		Logger:            slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+//		RequestGroup:      "request",
+//		ResponseGroup:     "response",
+//		SuccessSampleRate: 0.1,
+//		RedactHeaders:     []string{"Authorization"},
+//	}))
+type SlogRequestLoggerConfig struct {
+	// RequestLoggerConfig defines which values are extracted from request and response. LogValuesFunc is set by the
+	// middleware and is ignored.
+	RequestLoggerConfig
+
+	// Logger is used to log requests.
+	// Optional. Defaults to `slog.Default()`.
+	Logger *slog.Logger
+
+	// Message is the log message.
+	// Optional. Defaults to "request".
+	Message string
+
+	// LevelFunc decides log level of the request.
+	// Optional. Defaults to `DefaultSlogLevel`.
+	LevelFunc func(c echo.Context, v RequestLoggerValues) slog.Level
+
+	// RequestGroup is name of the attribute group for request values. Request values are logged as top level
+	// attributes when empty.
+	RequestGroup string
+	// ResponseGroup is name of the attribute group for response values (status, size and latency). Response values are
+	// logged as top level attributes when empty.
+	ResponseGroup string
+
+	// SuccessSampleRate is fraction (0, 1) of successful requests that are logged. Requests with an error or with
+	// status code 400 or higher are always logged. Zero or value of 1 and higher logs all requests.
+	SuccessSampleRate float64
+
+	// RedactHeaders is list of request headers which values are replaced with `[REDACTED]` in logged headers.
+	RedactHeaders []string
+	// RedactQueryParams is list of query parameters which values are replaced with `[REDACTED]` in logged query
+	// parameters and URI.
+	RedactQueryParams []string
+}
+
+const redactedValue = "[REDACTED]"
+
+// DefaultSlogRequestLoggerConfig is the default slog request logger middleware config.
+var DefaultSlogRequestLoggerConfig = SlogRequestLoggerConfig{
+	RequestLoggerConfig: RequestLoggerConfig{
+		Skipper:      DefaultSkipper,
+		LogLatency:   true,
+		LogRemoteIP:  true,
+		LogHost:      true,
+		LogMethod:    true,
+		LogURI:       true,
+		LogRoutePath: true,
+		LogRequestID: true,
+		LogUserAgent: true,
+		LogStatus:    true,
+		LogError:     true,
+
+		LogResponseSize: true,
+	},
+	Message: "request",
+}
+
+// SlogRequestLogger returns a middleware that logs requests with `slog.Default()` logger.
+func SlogRequestLogger() echo.MiddlewareFunc {
+	return SlogRequestLoggerWithConfig(DefaultSlogRequestLoggerConfig)
+}
+
+// SlogRequestLoggerWithConfig returns a slog request logger middleware with config.
+func SlogRequestLoggerWithConfig(config SlogRequestLoggerConfig) echo.MiddlewareFunc {
+	mw, err := config.ToMiddleware()
+	if err != nil {
+		panic(err)
+	}
+	return mw
+}
+
+// ToMiddleware converts SlogRequestLoggerConfig into middleware or returns an error for invalid configuration.
+func (config SlogRequestLoggerConfig) ToMiddleware() (echo.MiddlewareFunc, error) {
+	if config.Message == "" {
+		config.Message = DefaultSlogRequestLoggerConfig.Message
+	}
+	if config.LevelFunc == nil {
+		config.LevelFunc = DefaultSlogLevel
+	}
+	redactHeaders := make(map[string]struct{}, len(config.RedactHeaders))
+	for _, h := range config.RedactHeaders {
+		redactHeaders[http.CanonicalHeaderKey(h)] = struct{}{}
+	}
+	redactQueryParams := make(map[string]struct{}, len(config.RedactQueryParams))
+	for _, p := range config.RedactQueryParams {
+		redactQueryParams[p] = struct{}{}
+	}
+	sample := config.SuccessSampleRate > 0 && config.SuccessSampleRate < 1
+
+	config.LogValuesFunc = func(c echo.Context, v RequestLoggerValues) error {
+		logger := config.Logger
+		if logger == nil {
+			logger = slog.Default()
+		}
+		if sample && v.Error == nil && requestStatus(c, v) < http.StatusBadRequest && rand.Float64() >= config.SuccessSampleRate {
+			return nil
+		}
+
+		ctx := c.Request().Context()
+		level := config.LevelFunc(c, v)
+		if !logger.Enabled(ctx, level) {
+			return nil
+		}
+
+		reqAttrs := make([]slog.Attr, 0, 16)
+		if config.LogMethod {
+			reqAttrs = append(reqAttrs, slog.String("method", v.Method))
+		}
+		if config.LogURI {
+			reqAttrs = append(reqAttrs, slog.String("uri", redactURI(v.URI, redactQueryParams)))
+		}
+		if config.LogURIPath {
+			reqAttrs = append(reqAttrs, slog.String("path", v.URIPath))
+		}
+		if config.LogRoutePath {
+			reqAttrs = append(reqAttrs, slog.String("route", v.RoutePath))
+		}
+		if config.LogHost {
+			reqAttrs = append(reqAttrs, slog.String("host", v.Host))
+		}
+		if config.LogProtocol {
+			reqAttrs = append(reqAttrs, slog.String("protocol", v.Protocol))
+		}
+		if config.LogRemoteIP {
+			reqAttrs = append(reqAttrs, slog.String("remote_ip", v.RemoteIP))
+		}
+		if config.LogRequestID {
+			reqAttrs = append(reqAttrs, slog.String("request_id", v.RequestID))
+		}
+		if config.LogReferer {
+			reqAttrs = append(reqAttrs, slog.String("referer", v.Referer))
+		}
+		if config.LogUserAgent {
+			reqAttrs = append(reqAttrs, slog.String("user_agent", v.UserAgent))
+		}
+		if config.LogContentLength {
+			reqAttrs = append(reqAttrs, slog.String("content_length", v.ContentLength))
+		}
+		if len(v.Headers) > 0 {
+			reqAttrs = append(reqAttrs, valuesGroup("headers", config.LogHeaders, v.Headers, redactHeaders, http.CanonicalHeaderKey))
+		}
+		if len(v.QueryParams) > 0 {
+			reqAttrs = append(reqAttrs, valuesGroup("query", config.LogQueryParams, v.QueryParams, redactQueryParams, nil))
+		}
+		if len(v.FormValues) > 0 {
+			reqAttrs = append(reqAttrs, valuesGroup("form", config.LogFormValues, v.FormValues, nil, nil))
+		}
+
+		resAttrs := make([]slog.Attr, 0, 3)
+		if config.LogStatus {
+			resAttrs = append(resAttrs, slog.Int("status", v.Status))
+		}
+		if config.LogResponseSize {
+			resAttrs = append(resAttrs, slog.Int64("size", v.ResponseSize))
+		}
+		if config.LogLatency {
+			resAttrs = append(resAttrs, slog.Duration("latency", v.Latency))
+		}
+
+		attrs := make([]slog.Attr, 0, len(reqAttrs)+len(resAttrs)+1)
+		attrs = appendGroup(attrs, config.RequestGroup, reqAttrs)
+		attrs = appendGroup(attrs, config.ResponseGroup, resAttrs)
+		if v.Error != nil {
+			attrs = append(attrs, slog.String("error", v.Error.Error()))
+		}
+		logger.LogAttrs(ctx, level, config.Message, attrs...)
+		return nil
+	}
+	return config.RequestLoggerConfig.ToMiddleware()
+}
+
+// DefaultSlogLevel returns `slog.LevelError` for requests with status code 500 and higher, `slog.LevelWarn` for status
+// code 400 and higher, `slog.LevelError` for other requests with an error and `slog.LevelInfo` for the rest.
+func DefaultSlogLevel(c echo.Context, v RequestLoggerValues) slog.Level {
+	status := requestStatus(c, v)
+	switch {
+	case status >= http.StatusInternalServerError:
+		return slog.LevelError
+	case status >= http.StatusBadRequest:
+		return slog.LevelWarn
+	case v.Error != nil:
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// requestStatus returns logged status or, when status is not logged, status derived the same way RequestLogger does:
+// code of `*echo.HTTPError` returned by handler when the error has not been handled (response is not committed), or
+// response status.
+func requestStatus(c echo.Context, v RequestLoggerValues) int {
+	if v.Status != 0 {
+		return v.Status
+	}
+	res := c.Response()
+	var httpErr *echo.HTTPError
+	if !res.Committed && errors.As(v.Error, &httpErr) {
+		return httpErr.Code
+	}
+	return res.Status
+}
+
+func appendGroup(attrs []slog.Attr, group string, values []slog.Attr) []slog.Attr {
+	if len(values) == 0 {
+		return attrs
+	}
+	if group == "" {
+		return append(attrs, values...)
+	}
+	return append(attrs, slog.Attr{Key: group, Value: slog.GroupValue(values...)})
+}
+
+// valuesGroup creates attribute group from values in order of configured names. Values of redacted names are
+// replaced with `[REDACTED]`.
+func valuesGroup(group string, names []string, values map[string][]string, redact map[string]struct{}, canonical func(string) string) slog.Attr {
+	attrs := make([]slog.Attr, 0, len(values))
+	for _, name := range names {
+		if canonical != nil {
+			name = canonical(name)
+		}
+		vs, ok := values[name]
+		if !ok {
+			continue
+		}
+		if _, ok := redact[name]; ok {
+			redacted := make([]string, len(vs))
+			for i := range redacted {
+				redacted[i] = redactedValue
+			}
+			vs = redacted
+		}
+		attrs = append(attrs, slog.Any(name, vs))
+	}
+	return slog.Attr{Key: group, Value: slog.GroupValue(attrs...)}
+}
+
+// redactURI replaces values of redacted query parameters in URI with `[REDACTED]`. Order of query parameters is
+// preserved.
+func redactURI(uri string, redact map[string]struct{}) string {
+	path, query, ok := strings.Cut(uri, "?")
+	if !ok || len(redact) == 0 {
+		return uri
+	}
+	params := strings.Split(query, "&")
+	for i, param := range params {
+		key, _, _ := strings.Cut(param, "=")
+		if k, err := url.QueryUnescape(key); err == nil {
+			key = k
+		}
+		if _, ok := redact[key]; ok {
+			params[i] = param[:strings.IndexByte(param+"=", '=')] + "=" + redactedValue
+		}
+	}
+	return path + "?" + strings.Join(params, "&")
+}
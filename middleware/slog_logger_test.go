@@ -0,0 +1,246 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSlogLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+}
+
+func TestSlogRequestLogger(t *testing.T) {
+	buf := new(bytes.Buffer)
+	defaultLogger := slog.Default()
+	slog.SetDefault(newTestSlogLogger(buf))
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+	e := echo.New()
+	e.Use(SlogRequestLogger())
+	e.GET("/users/:id", func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1?lang=en", nil)
+	req.Header.Set(echo.HeaderXRequestID, "abc")
+	req.Header.Set("User-Agent", "test")
+	req.RemoteAddr = "192.0.2.1:1234"
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	var entry map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.NotNil(t, entry["latency"])
+	delete(entry, "latency")
+	assert.Equal(t, map[string]interface{}{
+		"level":      "INFO",
+		"msg":        "request",
+		"method":     "GET",
+		"uri":        "/users/1?lang=en",
+		"route":      "/users/:id",
+		"host":       "example.com",
+		"remote_ip":  "192.0.2.1",
+		"request_id": "abc",
+		"user_agent": "test",
+		"status":     float64(200),
+		"size":       float64(2),
+	}, entry)
+}
+
+func TestSlogRequestLoggerWithConfig_groupsAndRedaction(t *testing.T) {
+	buf := new(bytes.Buffer)
+	e := echo.New()
+	e.Use(SlogRequestLoggerWithConfig(SlogRequestLoggerConfig{
+		RequestLoggerConfig: RequestLoggerConfig{
+			LogURI:         true,
+			LogStatus:      true,
+			LogHeaders:     []string{"authorization", "X-Tenant"},
+			LogQueryParams: []string{"token", "lang"},
+		},
+		Logger:            newTestSlogLogger(buf),
+		Message:           "http",
+		RequestGroup:      "req",
+		ResponseGroup:     "res",
+		RedactHeaders:     []string{"Authorization"},
+		RedactQueryParams: []string{"token"},
+	}))
+	e.GET("/", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/?token=secret&lang=en&token=other", nil)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer secret")
+	req.Header.Set("X-Tenant", "acme")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	expect := `{"level":"INFO","msg":"http","req":{"uri":"/?token=[REDACTED]&lang=en&token=[REDACTED]",` +
+		`"headers":{"Authorization":["[REDACTED]"],"X-Tenant":["acme"]},"query":{"token":["[REDACTED]","[REDACTED]"],"lang":["en"]}},` +
+		`"res":{"status":204}}` + "\n"
+	assert.Equal(t, expect, buf.String())
+	assert.Equal(t, "Bearer secret", req.Header.Get(echo.HeaderAuthorization))
+}
+
+func TestSlogRequestLoggerWithConfig_level(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		whenHandler echo.HandlerFunc
+		expect      string
+	}{
+		{
+			name: "ok, info",
+			whenHandler: func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			},
+			expect: `{"level":"INFO","msg":"request","status":200}` + "\n",
+		},
+		{
+			name: "ok, warn on client error",
+			whenHandler: func(c echo.Context) error {
+				return c.NoContent(http.StatusNotFound)
+			},
+			expect: `{"level":"WARN","msg":"request","status":404}` + "\n",
+		},
+		{
+			name: "ok, error on returned error",
+			whenHandler: func(c echo.Context) error {
+				return errors.New("boom")
+			},
+			expect: `{"level":"ERROR","msg":"request","status":500,"error":"boom"}` + "\n",
+		},
+		{
+			name: "ok, warn on returned HTTPError with client error code",
+			whenHandler: func(c echo.Context) error {
+				return echo.ErrForbidden
+			},
+			expect: `{"level":"WARN","msg":"request","status":403,"error":"code=403, message=Forbidden"}` + "\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			e := echo.New()
+			e.Use(SlogRequestLoggerWithConfig(SlogRequestLoggerConfig{
+				RequestLoggerConfig: RequestLoggerConfig{
+					LogStatus:   true,
+					LogError:    true,
+					HandleError: true,
+				},
+				Logger: newTestSlogLogger(buf),
+			}))
+			e.GET("/", tc.whenHandler)
+
+			e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, tc.expect, buf.String())
+		})
+	}
+}
+
+func TestSlogRequestLoggerWithConfig_sampling(t *testing.T) {
+	var testCases = []struct {
+		name         string
+		whenRate     float64
+		whenPath     string
+		expectLogged func(t *testing.T, logged int)
+	}{
+		{
+			name:     "ok, zero rate logs all requests",
+			whenRate: 0,
+			whenPath: "/",
+			expectLogged: func(t *testing.T, logged int) {
+				assert.Equal(t, 1000, logged)
+			},
+		},
+		{
+			name:     "ok, rate of one logs all requests",
+			whenRate: 1,
+			whenPath: "/",
+			expectLogged: func(t *testing.T, logged int) {
+				assert.Equal(t, 1000, logged)
+			},
+		},
+		{
+			name:     "ok, fraction of successful requests is logged",
+			whenRate: 0.25,
+			whenPath: "/",
+			expectLogged: func(t *testing.T, logged int) {
+				// expected value is 250 with standard deviation of ~14
+				assert.InDelta(t, 250, logged, 100)
+			},
+		},
+		{
+			name:     "ok, failed requests are always logged",
+			whenRate: 0.25,
+			whenPath: "/fail",
+			expectLogged: func(t *testing.T, logged int) {
+				assert.Equal(t, 1000, logged)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			e := echo.New()
+			e.Use(SlogRequestLoggerWithConfig(SlogRequestLoggerConfig{
+				RequestLoggerConfig: RequestLoggerConfig{LogStatus: true},
+				Logger:              newTestSlogLogger(buf),
+				SuccessSampleRate:   tc.whenRate,
+			}))
+			e.GET("/", func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})
+			e.GET("/fail", func(c echo.Context) error {
+				return c.NoContent(http.StatusBadRequest)
+			})
+
+			for i := 0; i < 1000; i++ {
+				e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tc.whenPath, nil))
+			}
+
+			tc.expectLogged(t, bytes.Count(buf.Bytes(), []byte("\n")))
+		})
+	}
+}
+
+func TestSlogRequestLoggerWithConfig_statusFromErrorWhenStatusIsNotLogged(t *testing.T) {
+	buf := new(bytes.Buffer)
+	e := echo.New()
+	e.Use(SlogRequestLoggerWithConfig(SlogRequestLoggerConfig{
+		RequestLoggerConfig: RequestLoggerConfig{LogMethod: true, LogError: true},
+		Logger:              newTestSlogLogger(buf),
+	}))
+	e.GET("/", func(c echo.Context) error {
+		return echo.ErrForbidden
+	})
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, `{"level":"WARN","msg":"request","method":"GET","error":"code=403, message=Forbidden"}`+"\n", buf.String())
+}
+
+func TestRedactURI(t *testing.T) {
+	redact := map[string]struct{}{"token": {}, "api key": {}}
+
+	assert.Equal(t, "/path", redactURI("/path", redact))
+	assert.Equal(t, "/?a=1", redactURI("/?a=1", nil))
+	assert.Equal(t, "/?token=[REDACTED]&a=1&api+key=[REDACTED]&token=[REDACTED]", redactURI("/?token=x&a=1&api+key=y&token", redact))
+}
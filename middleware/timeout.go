@@ -4,11 +4,15 @@
 package middleware
 
 import (
+	"bytes"
 	"context"
-	"github.com/labstack/echo/v4"
+	"errors"
+	"io"
 	"net/http"
 	"sync"
 	"time"
+
+	"github.com/labstack/echo/v4"
 )
 
 // ---------------------------------------------------------------------------------------------------------------
@@ -89,6 +93,10 @@ var DefaultTimeoutConfig = TimeoutConfig{
 
 // Timeout returns a middleware which returns error (503 Service Unavailable error) to client immediately when handler
 // call runs for longer than its time limit. NB: timeout does not stop handler execution.
+//
+// Response is buffered until handler returns. When handler flushes the response before time limit, i.e. starts
+// Server-Sent Events stream with `Context.EventStream`, buffered response is sent to the client, time limit no longer
+// applies and middleware waits for handler to return.
 func Timeout() echo.MiddlewareFunc {
 	return TimeoutWithConfig(DefaultTimeoutConfig)
 }
@@ -107,6 +115,10 @@ func (config TimeoutConfig) ToMiddleware() (echo.MiddlewareFunc, error) {
 	if config.Skipper == nil {
 		config.Skipper = DefaultTimeoutConfig.Skipper
 	}
+	errorBody := config.ErrorMessage
+	if errorBody == "" {
+		errorBody = "<html><head><title>Timeout</title></head><body><h1>Timeout</h1></body></html>"
+	}
 
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
@@ -114,108 +126,181 @@ func (config TimeoutConfig) ToMiddleware() (echo.MiddlewareFunc, error) {
 				return next(c)
 			}
 
-			errChan := make(chan error, 1)
-			handlerWrapper := echoHandlerFuncWrapper{
-				writer:     &ignorableWriter{ResponseWriter: c.Response().Writer},
-				ctx:        c,
-				handler:    next,
-				errChan:    errChan,
-				errHandler: config.OnTimeoutRouteErrorHandler,
-			}
-			handler := http.TimeoutHandler(handlerWrapper, config.Timeout, config.ErrorMessage)
-			handler.ServeHTTP(handlerWrapper.writer, c.Request())
-
-			select {
-			case err := <-errChan:
-				return err
-			default:
-				return nil
+			res := c.Response()
+			originalWriter := res.Writer
+			ctx := newDeadlineContext(c.Request().Context(), config.Timeout)
+			defer ctx.cancel(context.Canceled)
+			tw := &timeoutWriter{w: originalWriter, header: make(http.Header), ctx: ctx}
+
+			// replace echo.Context Request with the one with timeout to let later middlewares/handler on the chain
+			// handle properly it's cancellation. Writes of handler after timeout return http.ErrHandlerTimeout.
+			c.SetRequest(c.Request().WithContext(ctx))
+			res.Writer = tw
+
+			done := make(chan error, 1)
+			panicChan := make(chan interface{}, 1)
+			go func() {
+				defer func() {
+					if p := recover(); p != nil {
+						panicChan <- p
+					}
+				}()
+				err := next(c)
+				if tw.hasTimedOut() {
+					if err != nil && config.OnTimeoutRouteErrorHandler != nil {
+						config.OnTimeoutRouteErrorHandler(err, c)
+					}
+					return // on timeout we can not send handler error to client because response has already been sent
+				}
+				done <- err
+			}()
+
+			timeout := ctx.Done()
+			for {
+				select {
+				case p := <-panicChan:
+					// restore original writer and panic again so it could be handled with global middleware Recover()
+					res.Writer = originalWriter
+					panic(p)
+				case err := <-done:
+					res.Writer = originalWriter
+					return tw.finish(err)
+				case <-timeout:
+					if tw.timeout(errorBody) {
+						// response writer stays timeoutWriter, so handler that still runs can not send headers/data
+						return nil
+					}
+					timeout = nil // response is streamed, wait for handler to return
+				}
 			}
 		}
 	}, nil
 }
 
-type echoHandlerFuncWrapper struct {
-	writer     *ignorableWriter
-	ctx        echo.Context
-	handler    echo.HandlerFunc
-	errHandler func(err error, c echo.Context)
-	errChan    chan error
+// timeoutWriter buffers response of handler until handler returns or flushes the response.
+type timeoutWriter struct {
+	w   http.ResponseWriter
+	ctx *deadlineContext
+
+	lock        sync.Mutex
+	header      http.Header
+	buf         bytes.Buffer
+	code        int
+	wroteHeader bool
+	timedOut    bool
+	streaming   bool
 }
 
-func (t echoHandlerFuncWrapper) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
-	// replace echo.Context Request with the one provided by TimeoutHandler to let later middlewares/handler on the chain
-	// handle properly it's cancellation
-	t.ctx.SetRequest(r)
+func (tw *timeoutWriter) Header() http.Header {
+	tw.lock.Lock()
+	defer tw.lock.Unlock()
+	if tw.streaming {
+		return tw.w.Header()
+	}
+	return tw.header
+}
 
-	// replace writer with TimeoutHandler custom one. This will guarantee that
-	// `writes by h to its ResponseWriter will return ErrHandlerTimeout.`
-	originalWriter := t.ctx.Response().Writer
-	t.ctx.Response().Writer = rw
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.lock.Lock()
+	defer tw.lock.Unlock()
+	if tw.timedOut || tw.streaming || tw.wroteHeader {
+		return
+	}
+	tw.wroteHeader = true
+	tw.code = code
+}
 
-	// in case of panic we restore original writer and call panic again
-	// so it could be handled with global middleware Recover()
-	defer func() {
-		if err := recover(); err != nil {
-			t.ctx.Response().Writer = originalWriter
-			panic(err)
-		}
-	}()
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.lock.Lock()
+	defer tw.lock.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if tw.streaming {
+		return tw.w.Write(b)
+	}
+	if !tw.wroteHeader {
+		tw.wroteHeader = true
+		tw.code = http.StatusOK
+	}
+	return tw.buf.Write(b)
+}
 
-	err := t.handler(t.ctx)
-	if ctxErr := r.Context().Err(); ctxErr == context.DeadlineExceeded {
-		if err != nil && t.errHandler != nil {
-			t.errHandler(err, t.ctx)
-		}
-		return // on timeout we can not send handler error to client because `http.TimeoutHandler` has already sent headers
+func (tw *timeoutWriter) Flush() {
+	_ = tw.FlushError()
+}
+
+// FlushError sends buffered response to the client and stops time limit of the request. Following writes are sent to
+// the client without buffering.
+func (tw *timeoutWriter) FlushError() error {
+	tw.lock.Lock()
+	defer tw.lock.Unlock()
+	if tw.timedOut {
+		return http.ErrHandlerTimeout
 	}
-	if err != nil {
-		// This is needed as `http.TimeoutHandler` will write status code by itself on error and after that our tries to write
-		// status code will not work anymore as Echo.Response thinks it has been already "committed" and further writes
-		// create errors in log about `superfluous response.WriteHeader call from`
-		t.writer.Ignore(true)
-		t.ctx.Response().Writer = originalWriter // make sure we restore writer before we signal original coroutine about the error
-		// we pass error from handler to middlewares up in handler chain to act on it if needed.
-		t.errChan <- err
-		return
+	if !tw.streaming {
+		if !tw.ctx.stopDeadline() {
+			return http.ErrHandlerTimeout // time limit has passed, response is about to be replaced with timeout error
+		}
+		tw.streaming = true
+		tw.writeBufferedLocked()
 	}
-	// we restore original writer only for cases we did not timeout. On timeout we have already sent response to client
-	// and should not anymore send additional headers/data
-	// so on timeout writer stays what http.TimeoutHandler uses and prevents writing headers/body
-	t.ctx.Response().Writer = originalWriter
+	return http.NewResponseController(tw.w).Flush()
 }
 
-// ignorableWriter is ResponseWriter implementations that allows us to mark writer to ignore further write calls. This
-// is handy in cases when you do not have direct control of code being executed (3rd party middleware) but want to make
-// sure that external code will not be able to write response to the client.
-// Writer is coroutine safe for writes.
-type ignorableWriter struct {
-	http.ResponseWriter
+func (tw *timeoutWriter) hasTimedOut() bool {
+	tw.lock.Lock()
+	defer tw.lock.Unlock()
+	return tw.timedOut
+}
 
-	lock         sync.Mutex
-	ignoreWrites bool
+// timeout sends timeout error to client. Returns false when response is streamed and handler can not be timed out.
+func (tw *timeoutWriter) timeout(errorBody string) bool {
+	tw.lock.Lock()
+	defer tw.lock.Unlock()
+	if tw.streaming {
+		return false
+	}
+	tw.timedOut = true
+	tw.w.WriteHeader(http.StatusServiceUnavailable)
+	if errors.Is(tw.ctx.Err(), context.DeadlineExceeded) {
+		_, _ = io.WriteString(tw.w, errorBody)
+	}
+	return true
 }
 
-func (w *ignorableWriter) Ignore(ignore bool) {
-	w.lock.Lock()
-	w.ignoreWrites = ignore
-	w.lock.Unlock()
+// finish sends buffered response to the client after handler has returned. Buffered response is discarded when handler
+// returned error, so middlewares upstream of timeout middleware can handle the error.
+func (tw *timeoutWriter) finish(err error) error {
+	tw.lock.Lock()
+	defer tw.lock.Unlock()
+	if tw.streaming {
+		return err
+	}
+	if err != nil {
+		// This is needed as otherwise error handler could not write status code as Echo.Response thinks it has been
+		// already "committed"
+		copyHeader(tw.w.Header(), tw.header)
+		return err
+	}
+	tw.writeBufferedLocked()
+	return nil
 }
 
-func (w *ignorableWriter) WriteHeader(code int) {
-	w.lock.Lock()
-	defer w.lock.Unlock()
-	if w.ignoreWrites {
-		return
+func (tw *timeoutWriter) writeBufferedLocked() {
+	copyHeader(tw.w.Header(), tw.header)
+	if !tw.wroteHeader {
+		tw.code = http.StatusOK
+	}
+	tw.w.WriteHeader(tw.code)
+	if tw.buf.Len() > 0 {
+		_, _ = tw.w.Write(tw.buf.Bytes())
+		tw.buf.Reset()
 	}
-	w.ResponseWriter.WriteHeader(code)
 }
 
-func (w *ignorableWriter) Write(b []byte) (int, error) {
-	w.lock.Lock()
-	defer w.lock.Unlock()
-	if w.ignoreWrites {
-		return len(b), nil
+func copyHeader(dst, src http.Header) {
+	for k, v := range src {
+		dst[k] = v
 	}
-	return w.ResponseWriter.Write(b)
 }
@@ -492,3 +492,55 @@ func startServer(e *echo.Echo) (*http.Server, string, error) {
 		return nil, "", err
 	}
 }
+
+func TestTimeoutEventStream(t *testing.T) {
+	var testCases = []struct {
+		name           string
+		whenStartAfter time.Duration
+		expectCode     int
+		expectBody     string
+	}{
+		{
+			name:       "ok, stream started before timeout outlives timeout",
+			expectCode: http.StatusOK,
+			expectBody: "data: first\n\ndata: second\n\n",
+		},
+		{
+			name:           "nok, handler times out before stream is started",
+			whenStartAfter: 50 * time.Millisecond,
+			expectCode:     http.StatusServiceUnavailable,
+			expectBody:     "<html><head><title>Timeout</title></head><body><h1>Timeout</h1></body></html>",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.Use(TimeoutWithConfig(TimeoutConfig{
+				Timeout: 10 * time.Millisecond,
+			}))
+			handlerDone := make(chan struct{})
+			e.GET("/events", func(c echo.Context) error {
+				defer close(handlerDone)
+				time.Sleep(tc.whenStartAfter)
+				return c.EventStream(func(es *echo.EventStream) error {
+					if err := es.Send(echo.Event{Data: "first"}); err != nil {
+						return err
+					}
+					time.Sleep(30 * time.Millisecond) // longer than timeout
+					return es.Send(echo.Event{Data: "second"})
+				})
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/events", nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			<-handlerDone
+
+			assert.Equal(t, tc.expectCode, rec.Code)
+			assert.Equal(t, tc.expectBody, rec.Body.String())
+			if tc.expectCode == http.StatusOK {
+				assert.Equal(t, echo.MIMETextEventStream, rec.Header().Get(echo.HeaderContentType))
+			}
+		})
+	}
+}
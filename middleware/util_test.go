@@ -10,94 +10,6 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func Test_matchScheme(t *testing.T) {
-	tests := []struct {
-		domain, pattern string
-		expected        bool
-	}{
-		{
-			domain:   "http://example.com",
-			pattern:  "http://example.com",
-			expected: true,
-		},
-		{
-			domain:   "https://example.com",
-			pattern:  "https://example.com",
-			expected: true,
-		},
-		{
-			domain:   "http://example.com",
-			pattern:  "https://example.com",
-			expected: false,
-		},
-		{
-			domain:   "https://example.com",
-			pattern:  "http://example.com",
-			expected: false,
-		},
-	}
-
-	for _, v := range tests {
-		assert.Equal(t, v.expected, matchScheme(v.domain, v.pattern))
-	}
-}
-
-func Test_matchSubdomain(t *testing.T) {
-	tests := []struct {
-		domain, pattern string
-		expected        bool
-	}{
-		{
-			domain:   "http://aaa.example.com",
-			pattern:  "http://*.example.com",
-			expected: true,
-		},
-		{
-			domain:   "http://bbb.aaa.example.com",
-			pattern:  "http://*.example.com",
-			expected: true,
-		},
-		{
-			domain:   "http://bbb.aaa.example.com",
-			pattern:  "http://*.aaa.example.com",
-			expected: true,
-		},
-		{
-			domain:   "http://aaa.example.com:8080",
-			pattern:  "http://*.example.com:8080",
-			expected: true,
-		},
-
-		{
-			domain:   "http://fuga.hoge.com",
-			pattern:  "http://*.example.com",
-			expected: false,
-		},
-		{
-			domain:   "http://ccc.bbb.example.com",
-			pattern:  "http://*.aaa.example.com",
-			expected: false,
-		},
-		{
-			domain: `http://1234567890.1234567890.1234567890.1234567890.1234567890.1234567890.1234567890.1234567890.1234567890.1234567890\
-      .1234567890.1234567890.1234567890.1234567890.1234567890.1234567890.1234567890.1234567890.1234567890.1234567890\
-      .1234567890.1234567890.1234567890.1234567890.1234567890.1234567890.1234567890.1234567890.1234567890.1234567890\
-      .1234567890.1234567890.1234567890.1234567890.1234567890.1234567890.1234567890.1234567890.1234567890.1234567890.example.com`,
-			pattern:  "http://*.example.com",
-			expected: false,
-		},
-		{
-			domain:   "http://ccc.bbb.example.com",
-			pattern:  "http://example.com",
-			expected: false,
-		},
-	}
-
-	for _, v := range tests {
-		assert.Equal(t, v.expected, matchSubdomain(v.domain, v.pattern))
-	}
-}
-
 func TestRandomString(t *testing.T) {
 	var testCases = []struct {
 		name       string
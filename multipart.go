@@ -0,0 +1,403 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"bufio"
+	stdContext "context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// MultipartConfig defines limits for streamed multipart/form-data request body. Zero values mean no limit, except for
+// MaxValuesSize.
+type MultipartConfig struct {
+	// MaxPartSize is maximum number of bytes in single part (form value or file).
+	MaxPartSize int64
+	// MaxTotalSize is maximum number of bytes in request body, including part headers and parts that are skipped.
+	MaxTotalSize int64
+	// MaxValuesSize is maximum number of bytes in all form values (parts that are not files) that `MultipartReader.Bind`
+	// reads to memory. Defaults to 32 MB.
+	MaxValuesSize int64
+	// MaxParts is maximum number of parts (form values and files).
+	MaxParts int
+	// MaxFiles is maximum number of file parts.
+	MaxFiles int
+	// AllowedContentTypes lists content types that file parts are allowed to have, i.e. `image/png` or `image/*`.
+	// Content type is sniffed from first 512 bytes of the file with `http.DetectContentType`, the one sent by client
+	// is not trusted. Empty list allows all content types.
+	AllowedContentTypes []string
+	// TempDir is directory where spooled files are written to. Defaults to `os.TempDir()`.
+	TempDir string
+}
+
+var (
+	// ErrMultipartPartTooLarge is returned when multipart part is bigger than `MultipartConfig.MaxPartSize`.
+	ErrMultipartPartTooLarge = NewHTTPError(http.StatusRequestEntityTooLarge, "multipart part too large")
+	// ErrMultipartTooLarge is returned when request body is bigger than `MultipartConfig.MaxTotalSize` or form values
+	// are together bigger than `MultipartConfig.MaxValuesSize`.
+	ErrMultipartTooLarge = NewHTTPError(http.StatusRequestEntityTooLarge, "multipart body too large")
+	// ErrMultipartTooManyParts is returned when request has more parts than `MultipartConfig.MaxParts`.
+	ErrMultipartTooManyParts = NewHTTPError(http.StatusRequestEntityTooLarge, "too many multipart parts")
+	// ErrMultipartTooManyFiles is returned when request has more files than `MultipartConfig.MaxFiles`.
+	ErrMultipartTooManyFiles = NewHTTPError(http.StatusRequestEntityTooLarge, "too many multipart files")
+	// ErrMultipartContentType is returned when content type of file is not in `MultipartConfig.AllowedContentTypes`.
+	ErrMultipartContentType = NewHTTPError(http.StatusUnsupportedMediaType, "multipart file content type not allowed")
+)
+
+// sniffLen is number of bytes http.DetectContentType considers.
+const sniffLen = 512
+
+// MultipartReader iterates over parts of multipart/form-data request body without buffering the whole body to memory
+// or disk, as `Context.MultipartForm` does. Files that are spooled to disk with `MultipartPart.Spool` or bound with
+// `MultipartReader.Bind` are removed when request ends or when `MultipartReader.Close` is called.
+//
+// Example:
+//
+//	e.POST("/upload", func(c echo.Context) error {
+//		mr, err := c.MultipartReader(echo.MultipartConfig{MaxPartSize: 10 << 20, AllowedContentTypes: []string{"image/*"}})
+//		if err != nil {
+//			return err
+//		}
+//		for {
+//			part, err := mr.NextPart()
+//			if err == io.EOF {
+//				break
+//			} else if err != nil {
+//				return err
+//			}
+//			if _, err := io.Copy(storage, part); err != nil {
+//				return err
+//			}
+//		}
+//		return c.NoContent(http.StatusCreated)
+//	})
+type MultipartReader struct {
+	reader *multipart.Reader
+	config MultipartConfig
+
+	parts int
+	files int
+
+	lock    sync.Mutex
+	closers []io.Closer
+	paths   []string
+}
+
+// MultipartPart is single part of streamed multipart/form-data request body. Reading the part fails when it exceeds
+// size limits of `MultipartConfig`. Part can be read only until `MultipartReader.NextPart` is called again.
+type MultipartPart struct {
+	*multipart.Part
+	reader      io.Reader
+	contentType string
+	mr          *MultipartReader
+}
+
+// MultipartFile is file part spooled to temporary file on disk.
+type MultipartFile struct {
+	// FieldName is form field name of the part.
+	FieldName string
+	// Filename is file name sent by the client.
+	Filename string
+	// Header is MIME header of the part.
+	Header textproto.MIMEHeader
+	// ContentType is sniffed content type of the file.
+	ContentType string
+	// Size is file size in bytes.
+	Size int64
+	// Path is path to the temporary file.
+	Path string
+}
+
+func (c *context) MultipartReader(config MultipartConfig) (*MultipartReader, error) {
+	return NewMultipartReader(c, config)
+}
+
+// NewMultipartReader creates MultipartReader for request of the context. Returns error with status 400 when request
+// is not multipart/form-data request.
+func NewMultipartReader(c Context, config MultipartConfig) (*MultipartReader, error) {
+	req := c.Request()
+	if config.MaxTotalSize > 0 {
+		req.Body = http.MaxBytesReader(c.Response(), req.Body, config.MaxTotalSize)
+	}
+	reader, err := req.MultipartReader()
+	if err != nil {
+		return nil, NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	mr := &MultipartReader{reader: reader, config: config}
+	if ctx, ok := c.(*context); ok {
+		ctx.onRequestEnd(func() { mr.Close() })
+	} else {
+		// context is wrapped, rely on request context being canceled at request end
+		stdContext.AfterFunc(c.Request().Context(), func() { mr.Close() })
+	}
+	return mr, nil
+}
+
+// NextPart returns next part of request body. Returns `io.EOF` when there are no more parts.
+func (r *MultipartReader) NextPart() (*MultipartPart, error) {
+	p, err := r.reader.NextPart()
+	if err == io.EOF {
+		return nil, err
+	} else if err != nil {
+		if isMaxBytesError(err) {
+			return nil, ErrMultipartTooLarge
+		}
+		return nil, NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	r.parts++
+	if r.config.MaxParts > 0 && r.parts > r.config.MaxParts {
+		p.Close()
+		return nil, ErrMultipartTooManyParts
+	}
+
+	part := &MultipartPart{Part: p, mr: r}
+	part.reader = &multipartPartReader{part: p, mr: r}
+	if p.FileName() == "" {
+		part.contentType = p.Header.Get(HeaderContentType)
+		return part, nil
+	}
+
+	r.files++
+	if r.config.MaxFiles > 0 && r.files > r.config.MaxFiles {
+		p.Close()
+		return nil, ErrMultipartTooManyFiles
+	}
+	buffered := bufio.NewReaderSize(part.reader, sniffLen)
+	head, err := buffered.Peek(sniffLen)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+	part.reader = buffered
+	part.contentType = http.DetectContentType(head)
+	if !isContentTypeAllowed(part.contentType, r.config.AllowedContentTypes) {
+		p.Close()
+		return nil, ErrMultipartContentType
+	}
+	return part, nil
+}
+
+// Bind reads all remaining parts and binds them to fields of struct i with `form` tags. Form values are bound same way
+// as `DefaultBinder` binds them. Files are spooled to disk and bound to fields of type `io.Reader`, `*MultipartFile`
+// and `[]*MultipartFile`.
+func (r *MultipartReader) Bind(i interface{}) error {
+	maxValuesSize := r.config.MaxValuesSize
+	if maxValuesSize <= 0 {
+		maxValuesSize = defaultMemory
+	}
+	values := map[string][]string{}
+	files := map[string][]*MultipartFile{}
+	for {
+		part, err := r.NextPart()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		name := part.FormName()
+		if part.FileName() == "" {
+			b, err := io.ReadAll(io.LimitReader(part, maxValuesSize+1))
+			if err != nil {
+				return err
+			}
+			maxValuesSize -= int64(len(b))
+			if maxValuesSize < 0 {
+				return ErrMultipartTooLarge
+			}
+			values[name] = append(values[name], string(b))
+			continue
+		}
+		file, err := part.Spool()
+		if err != nil {
+			return err
+		}
+		files[name] = append(files[name], file)
+	}
+
+	if err := new(DefaultBinder).bindData(i, values, "form", nil); err != nil {
+		return newBindDataError(err)
+	}
+	val := reflect.ValueOf(i)
+	if val.Kind() != reflect.Pointer || val.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	return r.bindFiles(val.Elem(), files)
+}
+
+var (
+	ioReaderType                  = reflect.TypeOf((*io.Reader)(nil)).Elem()
+	multipartFilePointerType      = reflect.TypeOf((*MultipartFile)(nil))
+	multipartFilePointerSliceType = reflect.TypeOf([]*MultipartFile(nil))
+)
+
+func (r *MultipartReader) bindFiles(val reflect.Value, files map[string][]*MultipartFile) error {
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		typeField := typ.Field(i)
+		structField := val.Field(i)
+		if !structField.CanSet() {
+			continue
+		}
+		name, _, _ := strings.Cut(typeField.Tag.Get("form"), ",")
+		if name == "" {
+			if structField.Kind() == reflect.Struct {
+				if err := r.bindFiles(structField, files); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		fieldFiles := files[name]
+		if len(fieldFiles) == 0 {
+			continue
+		}
+		switch typeField.Type {
+		case ioReaderType:
+			f, err := fieldFiles[0].open(r)
+			if err != nil {
+				return err
+			}
+			structField.Set(reflect.ValueOf(f))
+		case multipartFilePointerType:
+			structField.Set(reflect.ValueOf(fieldFiles[0]))
+		case multipartFilePointerSliceType:
+			structField.Set(reflect.ValueOf(fieldFiles))
+		}
+	}
+	return nil
+}
+
+// Close closes files opened by Bind and removes all spooled files. Close is called automatically when request ends.
+func (r *MultipartReader) Close() error {
+	r.lock.Lock()
+	closers, paths := r.closers, r.paths
+	r.closers, r.paths = nil, nil
+	r.lock.Unlock()
+
+	var errs []error
+	for _, c := range closers {
+		if err := c.Close(); err != nil && !errors.Is(err, os.ErrClosed) {
+			errs = append(errs, err)
+		}
+	}
+	for _, p := range paths {
+		if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ContentType returns content type of the part. For files it is sniffed from the file contents, for form values it
+// is `Content-Type` header of the part.
+func (p *MultipartPart) ContentType() string {
+	return p.contentType
+}
+
+// Read reads the part body. Returns `ErrMultipartPartTooLarge` or `ErrMultipartTooLarge` when size limits are exceeded.
+func (p *MultipartPart) Read(b []byte) (int, error) {
+	return p.reader.Read(b)
+}
+
+// Spool writes rest of the part to a temporary file in `MultipartConfig.TempDir`. The file is removed when request
+// ends.
+func (p *MultipartPart) Spool() (*MultipartFile, error) {
+	f, err := os.CreateTemp(p.mr.config.TempDir, "echo-multipart-*")
+	if err != nil {
+		return nil, err
+	}
+	p.mr.track(nil, f.Name())
+
+	size, err := io.Copy(f, p)
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &MultipartFile{
+		FieldName:   p.FormName(),
+		Filename:    p.FileName(),
+		Header:      p.Header,
+		ContentType: p.contentType,
+		Size:        size,
+		Path:        f.Name(),
+	}, nil
+}
+
+// Open opens the spooled file for reading.
+func (f *MultipartFile) Open() (*os.File, error) {
+	return os.Open(f.Path)
+}
+
+func (f *MultipartFile) open(r *MultipartReader) (*os.File, error) {
+	file, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	r.track(file, "")
+	return file, nil
+}
+
+func (r *MultipartReader) track(closer io.Closer, path string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if closer != nil {
+		r.closers = append(r.closers, closer)
+	}
+	if path != "" {
+		r.paths = append(r.paths, path)
+	}
+}
+
+// multipartPartReader counts bytes read from part and enforces part size limit.
+type multipartPartReader struct {
+	part *multipart.Part
+	mr   *MultipartReader
+	n    int64
+}
+
+func (r *multipartPartReader) Read(b []byte) (int, error) {
+	n, err := r.part.Read(b)
+	r.n += int64(n)
+	if r.mr.config.MaxPartSize > 0 && r.n > r.mr.config.MaxPartSize {
+		return n, ErrMultipartPartTooLarge
+	}
+	if err != nil && isMaxBytesError(err) {
+		return n, ErrMultipartTooLarge
+	}
+	return n, err
+}
+
+func isMaxBytesError(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.As(err, &maxBytesErr)
+}
+
+func isContentTypeAllowed(contentType string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, a := range allowed {
+		if a == mediaType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(a, "/*"); ok && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
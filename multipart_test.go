@@ -0,0 +1,383 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testPNG = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+type testMultipartField struct {
+	name     string
+	filename string
+	content  []byte
+}
+
+func newMultipartRequest(fields ...testMultipartField) *http.Request {
+	body := new(bytes.Buffer)
+	mw := multipart.NewWriter(body)
+	for _, f := range fields {
+		if f.filename == "" {
+			mw.WriteField(f.name, string(f.content))
+			continue
+		}
+		w, _ := mw.CreateFormFile(f.name, f.filename)
+		w.Write(f.content)
+	}
+	mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/", body)
+	req.Header.Set(HeaderContentType, mw.FormDataContentType())
+	return req
+}
+
+func TestMultipartReader_NextPart(t *testing.T) {
+	req := newMultipartRequest(
+		testMultipartField{name: "title", content: []byte("cat")},
+		testMultipartField{name: "image", filename: "cat.png", content: testPNG},
+	)
+	c := New().NewContext(req, httptest.NewRecorder())
+
+	mr, err := c.MultipartReader(MultipartConfig{})
+	assert.NoError(t, err)
+
+	part, err := mr.NextPart()
+	assert.NoError(t, err)
+	assert.Equal(t, "title", part.FormName())
+	value, err := io.ReadAll(part)
+	assert.NoError(t, err)
+	assert.Equal(t, "cat", string(value))
+
+	part, err = mr.NextPart()
+	assert.NoError(t, err)
+	assert.Equal(t, "image", part.FormName())
+	assert.Equal(t, "cat.png", part.FileName())
+	assert.Equal(t, "image/png", part.ContentType())
+	content, err := io.ReadAll(part)
+	assert.NoError(t, err)
+	assert.Equal(t, testPNG, content)
+
+	_, err = mr.NextPart()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestMultipartReader_Limits(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		givenConfig MultipartConfig
+		whenFields  []testMultipartField
+		expectError error
+	}{
+		{
+			name:        "ok, within limits",
+			givenConfig: MultipartConfig{MaxPartSize: 16, MaxTotalSize: 1024, MaxParts: 2, MaxFiles: 1, AllowedContentTypes: []string{"image/*"}},
+			whenFields: []testMultipartField{
+				{name: "title", content: []byte("cat")},
+				{name: "image", filename: "cat.png", content: testPNG},
+			},
+		},
+		{
+			name:        "nok, part too large",
+			givenConfig: MultipartConfig{MaxPartSize: 2},
+			whenFields:  []testMultipartField{{name: "title", content: []byte("cat")}},
+			expectError: ErrMultipartPartTooLarge,
+		},
+		{
+			name:        "nok, sniffed file too large",
+			givenConfig: MultipartConfig{MaxPartSize: 15},
+			whenFields:  []testMultipartField{{name: "image", filename: "cat.png", content: testPNG}},
+			expectError: ErrMultipartPartTooLarge,
+		},
+		{
+			name:        "nok, total too large",
+			givenConfig: MultipartConfig{MaxTotalSize: 5},
+			whenFields: []testMultipartField{
+				{name: "title", content: []byte("cat")},
+				{name: "tag", content: []byte("pet")},
+			},
+			expectError: ErrMultipartTooLarge,
+		},
+		{
+			name:        "nok, too many parts",
+			givenConfig: MultipartConfig{MaxParts: 1},
+			whenFields: []testMultipartField{
+				{name: "title", content: []byte("cat")},
+				{name: "tag", content: []byte("pet")},
+			},
+			expectError: ErrMultipartTooManyParts,
+		},
+		{
+			name:        "nok, too many files",
+			givenConfig: MultipartConfig{MaxFiles: 1},
+			whenFields: []testMultipartField{
+				{name: "image", filename: "a.png", content: testPNG},
+				{name: "image", filename: "b.png", content: testPNG},
+			},
+			expectError: ErrMultipartTooManyFiles,
+		},
+		{
+			name:        "nok, content type not allowed",
+			givenConfig: MultipartConfig{AllowedContentTypes: []string{"image/*"}},
+			whenFields:  []testMultipartField{{name: "image", filename: "cat.png", content: []byte("plain text")}},
+			expectError: ErrMultipartContentType,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := New().NewContext(newMultipartRequest(tc.whenFields...), httptest.NewRecorder())
+			mr, err := c.MultipartReader(tc.givenConfig)
+			assert.NoError(t, err)
+
+			for {
+				var part *MultipartPart
+				part, err = mr.NextPart()
+				if err != nil {
+					break
+				}
+				if _, err = io.Copy(io.Discard, part); err != nil {
+					break
+				}
+			}
+			if tc.expectError == nil {
+				assert.Equal(t, io.EOF, err)
+			} else {
+				assert.ErrorIs(t, err, tc.expectError)
+			}
+		})
+	}
+}
+
+func TestMultipartReader_MaxTotalSizeCountsSkippedParts(t *testing.T) {
+	req := newMultipartRequest(
+		testMultipartField{name: "skipped", content: bytes.Repeat([]byte("a"), 2048)},
+		testMultipartField{name: "title", content: []byte("cat")},
+	)
+	c := New().NewContext(req, httptest.NewRecorder())
+	mr, err := c.MultipartReader(MultipartConfig{MaxTotalSize: 1024})
+	assert.NoError(t, err)
+
+	_, err = mr.NextPart()
+	assert.NoError(t, err)
+	_, err = mr.NextPart() // first part is drained without being read by the caller
+	assert.ErrorIs(t, err, ErrMultipartTooLarge)
+}
+
+func TestMultipartReader_BindMaxValuesSize(t *testing.T) {
+	type form struct {
+		Title string `form:"title"`
+		Tag   string `form:"tag"`
+	}
+	var testCases = []struct {
+		name        string
+		givenConfig MultipartConfig
+		whenFields  []testMultipartField
+		expectError error
+	}{
+		{
+			name:        "ok, within limit",
+			givenConfig: MultipartConfig{MaxValuesSize: 6},
+			whenFields:  []testMultipartField{{name: "title", content: []byte("cat")}, {name: "tag", content: []byte("pet")}},
+		},
+		{
+			name:        "nok, values together too large",
+			givenConfig: MultipartConfig{MaxValuesSize: 5},
+			whenFields:  []testMultipartField{{name: "title", content: []byte("cat")}, {name: "tag", content: []byte("pet")}},
+			expectError: ErrMultipartTooLarge,
+		},
+		{
+			name:        "nok, default limit",
+			whenFields:  []testMultipartField{{name: "title", content: bytes.Repeat([]byte("a"), defaultMemory+1)}},
+			expectError: ErrMultipartTooLarge,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := New().NewContext(newMultipartRequest(tc.whenFields...), httptest.NewRecorder())
+			mr, err := c.MultipartReader(tc.givenConfig)
+			assert.NoError(t, err)
+
+			var f form
+			err = mr.Bind(&f)
+			if tc.expectError == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, form{Title: "cat", Tag: "pet"}, f)
+			} else {
+				assert.ErrorIs(t, err, tc.expectError)
+			}
+		})
+	}
+}
+
+func TestMultipartReader_NotMultipart(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+	req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+	c := New().NewContext(req, httptest.NewRecorder())
+
+	_, err := c.MultipartReader(MultipartConfig{})
+	assert.EqualError(t, err, "code=400, message=request Content-Type isn't multipart/form-data, internal=request Content-Type isn't multipart/form-data")
+}
+
+func TestMultipartReader_Bind(t *testing.T) {
+	type upload struct {
+		Title       string           `form:"title"`
+		Image       io.Reader        `form:"image"`
+		Attachments []*MultipartFile `form:"attachments"`
+	}
+	tmpDir := t.TempDir()
+	e := New()
+	e.POST("/", func(c Context) error {
+		mr, err := c.MultipartReader(MultipartConfig{TempDir: tmpDir})
+		if err != nil {
+			return err
+		}
+		var u upload
+		if err := mr.Bind(&u); err != nil {
+			return err
+		}
+		assert.Equal(t, "cat", u.Title)
+		image, err := io.ReadAll(u.Image)
+		assert.NoError(t, err)
+		assert.Equal(t, testPNG, image)
+
+		if assert.Len(t, u.Attachments, 2) {
+			assert.Equal(t, "a.txt", u.Attachments[0].Filename)
+			assert.Equal(t, "text/plain; charset=utf-8", u.Attachments[0].ContentType)
+			assert.Equal(t, int64(1), u.Attachments[0].Size)
+			assert.Equal(t, tmpDir, filepath.Dir(u.Attachments[0].Path))
+		}
+		entries, _ := os.ReadDir(tmpDir)
+		assert.Len(t, entries, 3)
+		return c.NoContent(http.StatusCreated)
+	})
+
+	req := newMultipartRequest(
+		testMultipartField{name: "title", content: []byte("cat")},
+		testMultipartField{name: "image", filename: "cat.png", content: testPNG},
+		testMultipartField{name: "attachments", filename: "a.txt", content: []byte("a")},
+		testMultipartField{name: "attachments", filename: "b.txt", content: []byte("b")},
+	)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	entries, err := os.ReadDir(tmpDir)
+	assert.NoError(t, err)
+	assert.Empty(t, entries, "spooled files are removed when request ends")
+}
+
+func TestMultipartReader_RemovesFilesWhenHandlerPanics(t *testing.T) {
+	tmpDir := t.TempDir()
+	e := New()
+	e.POST("/", func(c Context) error {
+		mr, err := c.MultipartReader(MultipartConfig{TempDir: tmpDir})
+		if err != nil {
+			return err
+		}
+		part, err := mr.NextPart()
+		if err != nil {
+			return err
+		}
+		if _, err := part.Spool(); err != nil {
+			return err
+		}
+		panic("boom")
+	})
+
+	req := newMultipartRequest(testMultipartField{name: "image", filename: "cat.png", content: testPNG})
+	assert.PanicsWithValue(t, "boom", func() {
+		e.ServeHTTP(httptest.NewRecorder(), req)
+	})
+
+	entries, err := os.ReadDir(tmpDir)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestMultipartReader_RemovesFilesOutsideServeHTTP(t *testing.T) {
+	var testCases = []struct {
+		name    string
+		whenEnd func(e *Echo, c Context)
+	}{
+		{
+			name: "reset",
+			whenEnd: func(e *Echo, c Context) {
+				c.Reset(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+			},
+		},
+		{
+			name:    "release",
+			whenEnd: func(e *Echo, c Context) { e.ReleaseContext(c) },
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			e := New()
+			c := e.AcquireContext()
+			c.Reset(newMultipartRequest(testMultipartField{name: "image", filename: "cat.png", content: testPNG}), httptest.NewRecorder())
+
+			mr, err := c.MultipartReader(MultipartConfig{TempDir: tmpDir})
+			assert.NoError(t, err)
+			part, err := mr.NextPart()
+			assert.NoError(t, err)
+			file, err := part.Spool()
+			assert.NoError(t, err)
+
+			tc.whenEnd(e, c)
+
+			_, err = os.Stat(file.Path)
+			assert.True(t, os.IsNotExist(err))
+		})
+	}
+}
+
+func TestMultipartPart_Spool(t *testing.T) {
+	tmpDir := t.TempDir()
+	c := New().NewContext(newMultipartRequest(testMultipartField{name: "image", filename: "cat.png", content: testPNG}), httptest.NewRecorder())
+	mr, err := c.MultipartReader(MultipartConfig{TempDir: tmpDir})
+	assert.NoError(t, err)
+
+	part, err := mr.NextPart()
+	assert.NoError(t, err)
+	file, err := part.Spool()
+	assert.NoError(t, err)
+	assert.Equal(t, &MultipartFile{
+		FieldName:   "image",
+		Filename:    "cat.png",
+		Header:      part.Header,
+		ContentType: "image/png",
+		Size:        int64(len(testPNG)),
+		Path:        file.Path,
+	}, file)
+
+	f, err := file.Open()
+	assert.NoError(t, err)
+	content, err := io.ReadAll(f)
+	assert.NoError(t, err)
+	assert.Equal(t, testPNG, content)
+	f.Close()
+
+	assert.NoError(t, mr.Close())
+	_, err = os.Stat(file.Path)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestIsContentTypeAllowed(t *testing.T) {
+	assert.True(t, isContentTypeAllowed("text/plain; charset=utf-8", nil))
+	assert.True(t, isContentTypeAllowed("text/plain; charset=utf-8", []string{"text/plain"}))
+	assert.True(t, isContentTypeAllowed("image/png", []string{"application/pdf", "image/*"}))
+	assert.False(t, isContentTypeAllowed("image/png", []string{"image/jpeg"}))
+	assert.False(t, isContentTypeAllowed("application/octet-stream", []string{"image/*"}))
+}
@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TemplateData is value for `Context.Negotiate` that is rendered with `Echo.Renderer` template when client accepts
+// `text/html` and Data is encoded with registered Codec for all other negotiated media types.
+type TemplateData struct {
+	// Name is name of the template to render
+	Name string
+	// Data is data passed to template or encoded with Codec
+	Data interface{}
+}
+
+type acceptRange struct {
+	typ     string
+	subtype string
+	q       float64
+}
+
+// specificity returns how specific the match of range and media type is. 0 means that range does not match media type.
+func (r acceptRange) specificity(typ, subtype string) int {
+	switch {
+	case r.typ == "*" && r.subtype == "*":
+		return 1
+	case r.typ == typ && r.subtype == "*":
+		return 2
+	case r.typ == typ && r.subtype == subtype:
+		return 3
+	}
+	return 0
+}
+
+// parseAccept parses `Accept` header value into list of media ranges. Ranges with invalid quality values are ignored.
+func parseAccept(accept string) []acceptRange {
+	ranges := make([]acceptRange, 0, strings.Count(accept, ",")+1)
+	for _, part := range strings.Split(accept, ",") {
+		mediaRange, params, _ := strings.Cut(part, ";")
+		typ, subtype, ok := strings.Cut(strings.ToLower(strings.TrimSpace(mediaRange)), "/")
+		if !ok || typ == "" || subtype == "" || (typ == "*" && subtype != "*") {
+			continue
+		}
+		r := acceptRange{typ: typ, subtype: subtype, q: 1}
+		valid := true
+		for _, param := range strings.Split(params, ";") {
+			k, v, _ := strings.Cut(param, "=")
+			if !strings.EqualFold(strings.TrimSpace(k), "q") {
+				continue
+			}
+			q, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil || q < 0 || q > 1 {
+				valid = false
+				break
+			}
+			r.q = q
+		}
+		if valid {
+			ranges = append(ranges, r)
+		}
+	}
+	return ranges
+}
+
+// NegotiateContentType returns offer that best matches given `Accept` header value. Offers are considered in order of
+// server preference - when multiple offers have same quality the first of them wins. Empty `Accept` header is treated
+// as `*/*`. Empty string is returned when none of the offers is acceptable.
+func NegotiateContentType(accept string, offers ...string) string {
+	if len(offers) == 0 {
+		return ""
+	}
+	if strings.TrimSpace(accept) == "" {
+		return offers[0]
+	}
+	ranges := parseAccept(accept)
+
+	best := ""
+	bestQ := 0.0
+	for _, offer := range offers {
+		typ, subtype, ok := strings.Cut(normalizeMediaType(offer), "/")
+		if !ok {
+			continue
+		}
+		specificity := 0
+		q := 0.0
+		for _, r := range ranges {
+			if s := r.specificity(typ, subtype); s > specificity {
+				specificity = s
+				q = r.q
+			}
+		}
+		if q > bestQ {
+			best = offer
+			bestQ = q
+		}
+	}
+	return best
+}
+
+// negotiableMediaTypes returns media types that Context.Negotiate offers when handler does not provide offers. These
+// are all concrete (non-wildcard) media types from `Echo.Codecs` and `text/html` for TemplateData when renderer is registered.
+func (e *Echo) negotiableMediaTypes(i interface{}) []string {
+	offers := make([]string, 0, len(e.Codecs.MediaTypes())+1)
+	for _, mt := range e.Codecs.MediaTypes() {
+		if !strings.Contains(mt, "*") {
+			offers = append(offers, mt)
+		}
+	}
+	if _, ok := i.(TemplateData); ok && e.Renderer != nil {
+		offers = append(offers, MIMETextHTML)
+	}
+	return offers
+}
+
+func (c *context) Negotiate(code int, i interface{}, offers ...string) error {
+	if len(offers) == 0 {
+		offers = c.echo.negotiableMediaTypes(i)
+	}
+	c.response.Header().Add(HeaderVary, HeaderAccept)
+
+	contentType := NegotiateContentType(c.request.Header.Get(HeaderAccept), offers...)
+	if contentType == "" {
+		return ErrNotAcceptable
+	}
+
+	td, isTemplate := i.(TemplateData)
+	if isTemplate {
+		i = td.Data
+	}
+	mediaType := normalizeMediaType(contentType)
+	switch {
+	case mediaType == MIMETextHTML && isTemplate:
+		return c.Render(code, td.Name, td.Data)
+	case mediaType == MIMEApplicationJSON:
+		return c.JSON(code, i)
+	}
+	if _, ok := c.echo.Codecs.Lookup(mediaType); ok {
+		return c.Encode(code, contentType, i)
+	}
+	if mediaType == MIMETextPlain {
+		if contentType == mediaType {
+			contentType = MIMETextPlainCharsetUTF8
+		}
+		return c.Blob(code, contentType, []byte(fmt.Sprint(i)))
+	}
+	return ErrCodecNotRegistered
+}
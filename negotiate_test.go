@@ -0,0 +1,268 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateContentType(t *testing.T) {
+	var testCases = []struct {
+		name       string
+		whenAccept string
+		whenOffers []string
+		expect     string
+	}{
+		{
+			name:       "ok, empty accept picks first offer",
+			whenAccept: "",
+			whenOffers: []string{MIMEApplicationJSON, MIMEApplicationXML},
+			expect:     MIMEApplicationJSON,
+		},
+		{
+			name:       "ok, exact match",
+			whenAccept: "application/xml",
+			whenOffers: []string{MIMEApplicationJSON, MIMEApplicationXML},
+			expect:     MIMEApplicationXML,
+		},
+		{
+			name:       "ok, highest quality wins",
+			whenAccept: "application/json;q=0.5, application/xml;q=0.9",
+			whenOffers: []string{MIMEApplicationJSON, MIMEApplicationXML},
+			expect:     MIMEApplicationXML,
+		},
+		{
+			name:       "ok, server preference on equal quality",
+			whenAccept: "application/xml, application/json",
+			whenOffers: []string{MIMEApplicationJSON, MIMEApplicationXML},
+			expect:     MIMEApplicationJSON,
+		},
+		{
+			name:       "ok, wildcard",
+			whenAccept: "*/*",
+			whenOffers: []string{MIMEApplicationXML, MIMEApplicationJSON},
+			expect:     MIMEApplicationXML,
+		},
+		{
+			name:       "ok, type wildcard",
+			whenAccept: "text/*",
+			whenOffers: []string{MIMEApplicationJSON, MIMETextHTMLCharsetUTF8},
+			expect:     MIMETextHTMLCharsetUTF8,
+		},
+		{
+			name:       "ok, more specific range overrides quality of wildcard",
+			whenAccept: "application/*;q=0.9, application/json;q=0.1, */*;q=0.5",
+			whenOffers: []string{MIMEApplicationJSON, MIMEApplicationXML},
+			expect:     MIMEApplicationXML,
+		},
+		{
+			name:       "ok, browser accept",
+			whenAccept: "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+			whenOffers: []string{MIMEApplicationJSON, MIMETextHTML},
+			expect:     MIMETextHTML,
+		},
+		{
+			name:       "ok, case insensitive",
+			whenAccept: "Application/JSON",
+			whenOffers: []string{MIMEApplicationXML, MIMEApplicationJSON},
+			expect:     MIMEApplicationJSON,
+		},
+		{
+			name:       "nok, q=0 means not acceptable",
+			whenAccept: "application/json;q=0, application/xml;q=0",
+			whenOffers: []string{MIMEApplicationJSON, MIMEApplicationXML},
+			expect:     "",
+		},
+		{
+			name:       "nok, q=0 excludes from wildcard",
+			whenAccept: "application/json;q=0, */*",
+			whenOffers: []string{MIMEApplicationJSON},
+			expect:     "",
+		},
+		{
+			name:       "nok, no match",
+			whenAccept: "image/png",
+			whenOffers: []string{MIMEApplicationJSON, MIMEApplicationXML},
+			expect:     "",
+		},
+		{
+			name:       "nok, invalid ranges are ignored",
+			whenAccept: "json, */json, application/json;q=2",
+			whenOffers: []string{MIMEApplicationJSON},
+			expect:     "",
+		},
+		{
+			name:       "nok, no offers",
+			whenAccept: "*/*",
+			expect:     "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expect, NegotiateContentType(tc.whenAccept, tc.whenOffers...))
+		})
+	}
+}
+
+type testNegotiateRenderer struct{}
+
+func (testNegotiateRenderer) Render(w io.Writer, name string, data interface{}, c Context) error {
+	_, err := w.Write([]byte("<p>" + name + ":" + data.(user).Name + "</p>"))
+	return err
+}
+
+func TestContext_Negotiate(t *testing.T) {
+	var testCases = []struct {
+		name              string
+		whenAccept        string
+		whenData          interface{}
+		whenOffers        []string
+		expectBody        string
+		expectContentType string
+		expectErr         error
+	}{
+		{
+			name:              "ok, defaults to json",
+			whenData:          user{1, "Jon Snow"},
+			expectBody:        userJSON + "\n",
+			expectContentType: MIMEApplicationJSON,
+		},
+		{
+			name:              "ok, xml",
+			whenAccept:        "application/xml",
+			whenData:          user{1, "Jon Snow"},
+			expectBody:        xml.Header + userXML,
+			expectContentType: MIMEApplicationXML,
+		},
+		{
+			name:              "ok, template rendered for html",
+			whenAccept:        "text/html,*/*;q=0.8",
+			whenData:          TemplateData{Name: "user", Data: user{1, "Jon Snow"}},
+			expectBody:        "<p>user:Jon Snow</p>",
+			expectContentType: MIMETextHTMLCharsetUTF8,
+		},
+		{
+			name:              "ok, template data encoded for json",
+			whenAccept:        "application/json",
+			whenData:          TemplateData{Name: "user", Data: user{1, "Jon Snow"}},
+			expectBody:        userJSON + "\n",
+			expectContentType: MIMEApplicationJSON,
+		},
+		{
+			name:              "ok, text/plain offer",
+			whenAccept:        "text/plain",
+			whenData:          "hello",
+			whenOffers:        []string{MIMEApplicationJSON, MIMETextPlain},
+			expectBody:        "hello",
+			expectContentType: MIMETextPlainCharsetUTF8,
+		},
+		{
+			name:       "nok, not acceptable",
+			whenAccept: "image/png",
+			whenData:   user{1, "Jon Snow"},
+			expectErr:  ErrNotAcceptable,
+		},
+		{
+			name:       "nok, offer without codec",
+			whenAccept: MIMEApplicationMsgpack,
+			whenData:   user{1, "Jon Snow"},
+			whenOffers: []string{MIMEApplicationMsgpack},
+			expectErr:  ErrCodecNotRegistered,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.Renderer = testNegotiateRenderer{}
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.whenAccept != "" {
+				req.Header.Set(HeaderAccept, tc.whenAccept)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := c.Negotiate(http.StatusOK, tc.whenData, tc.whenOffers...)
+			assert.Equal(t, HeaderAccept, rec.Header().Get(HeaderVary))
+			if tc.expectErr != nil {
+				assert.ErrorIs(t, err, tc.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectContentType, rec.Header().Get(HeaderContentType))
+			assert.Equal(t, tc.expectBody, rec.Body.String())
+		})
+	}
+}
+
+func TestDefaultHTTPErrorHandler_NegotiatesContentType(t *testing.T) {
+	var testCases = []struct {
+		name              string
+		givenDebug        bool
+		whenAccept        string
+		whenPath          string
+		expectBody        string
+		expectContentType string
+	}{
+		{
+			name:              "ok, xml",
+			whenAccept:        "application/xml",
+			whenPath:          "/badrequest",
+			expectBody:        xml.Header + `<error><message>Invalid request</message></error>`,
+			expectContentType: MIMEApplicationXML,
+		},
+		{
+			name:              "ok, xml with debug",
+			givenDebug:        true,
+			whenAccept:        "text/xml",
+			whenPath:          "/badrequest",
+			expectBody:        xml.Header + "<error>\n  <error>code=400, message=Invalid request</error>\n  <message>Invalid request</message>\n</error>",
+			expectContentType: MIMETextXML,
+		},
+		{
+			name:              "ok, falls back to json when nothing is acceptable",
+			whenAccept:        "image/png",
+			whenPath:          "/badrequest",
+			expectBody:        `{"message":"Invalid request"}` + "\n",
+			expectContentType: MIMEApplicationJSON,
+		},
+		{
+			name:              "ok, custom messages are always json",
+			whenAccept:        "application/xml",
+			whenPath:          "/custom",
+			expectBody:        `{"code":33}` + "\n",
+			expectContentType: MIMEApplicationJSON,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.Debug = tc.givenDebug
+			e.GET("/badrequest", func(c Context) error {
+				return NewHTTPError(http.StatusBadRequest, "Invalid request")
+			})
+			e.GET("/custom", func(c Context) error {
+				return NewHTTPError(http.StatusBadRequest, map[string]interface{}{"code": 33})
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tc.whenPath, nil)
+			req.Header.Set(HeaderAccept, tc.whenAccept)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Equal(t, tc.expectContentType, rec.Header().Get(HeaderContentType))
+			assert.Equal(t, tc.expectBody, rec.Body.String())
+		})
+	}
+}
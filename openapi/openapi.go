@@ -0,0 +1,375 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+// Package openapi generates OpenAPI 3.1 documents from routes registered to Echo.
+//
+// Paths, parameters and request bodies are derived from routes and from request types of handlers registered with
+// `echo.HandleRoute`. Struct fields with `param`, `query` and `header` tags become parameters, fields with `form` tags
+// become form body and remaining fields are described as JSON body (same way `echo.DefaultBinder` binds them).
+// Responses are derived from response type recorded by `echo.HandleRoute` and responses documented with
+// `Route.WithResponse`.
+//
+// `Handler` serves document as JSON. Document types have `yaml` struct tags, so a document returned by `Generate` can
+// be served as YAML by marshalling it with a YAML library, i.e. gopkg.in/yaml.v3.
+//
+// Example:
+//
+//	echo.HandleRoute(e, http.MethodGet, "/users/:id", getUser).WithSummary("Get user").WithTags("users")
+//	e.GET("/openapi.json", openapi.Handler(e, openapi.Config{Info: openapi.Info{Title: "Users API", Version: "1.0.0"}}))
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Version is OpenAPI specification version of generated documents.
+const Version = "3.1.0"
+
+// Config defines the config for document generation.
+type Config struct {
+	// Info is metadata about the API. Title defaults to "API" and Version to "1.0.0".
+	Info Info
+
+	// Servers lists servers that provide the API.
+	// Optional.
+	Servers []Server
+
+	// Skipper defines a function to skip routes from the document. Routes for "route not found" handlers are
+	// always skipped.
+	// Optional.
+	Skipper func(route *echo.Route) bool
+}
+
+// Document is OpenAPI document. See https://spec.openapis.org/oas/v3.1.0#openapi-object
+type Document struct {
+	OpenAPI    string               `json:"openapi" yaml:"openapi"`
+	Info       Info                 `json:"info" yaml:"info"`
+	Servers    []Server             `json:"servers,omitempty" yaml:"servers,omitempty"`
+	Paths      map[string]*PathItem `json:"paths" yaml:"paths"`
+	Components *Components          `json:"components,omitempty" yaml:"components,omitempty"`
+}
+
+// Info is metadata about the API.
+type Info struct {
+	Title       string `json:"title" yaml:"title"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Version     string `json:"version" yaml:"version"`
+}
+
+// Server is server that provides the API.
+type Server struct {
+	URL         string `json:"url" yaml:"url"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// PathItem describes operations available on a single path.
+type PathItem struct {
+	Get     *Operation `json:"get,omitempty" yaml:"get,omitempty"`
+	Put     *Operation `json:"put,omitempty" yaml:"put,omitempty"`
+	Post    *Operation `json:"post,omitempty" yaml:"post,omitempty"`
+	Delete  *Operation `json:"delete,omitempty" yaml:"delete,omitempty"`
+	Options *Operation `json:"options,omitempty" yaml:"options,omitempty"`
+	Head    *Operation `json:"head,omitempty" yaml:"head,omitempty"`
+	Patch   *Operation `json:"patch,omitempty" yaml:"patch,omitempty"`
+	Trace   *Operation `json:"trace,omitempty" yaml:"trace,omitempty"`
+}
+
+// Operation describes a single API operation on a path.
+type Operation struct {
+	Tags        []string             `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Summary     string               `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Description string               `json:"description,omitempty" yaml:"description,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty" yaml:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses" yaml:"responses"`
+}
+
+// Parameter describes a single operation parameter.
+type Parameter struct {
+	Name     string  `json:"name" yaml:"name"`
+	In       string  `json:"in" yaml:"in"`
+	Required bool    `json:"required,omitempty" yaml:"required,omitempty"`
+	Schema   *Schema `json:"schema,omitempty" yaml:"schema,omitempty"`
+}
+
+// RequestBody describes request body.
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty" yaml:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content" yaml:"content"`
+}
+
+// Response describes a single response.
+type Response struct {
+	Description string                `json:"description" yaml:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty" yaml:"content,omitempty"`
+}
+
+// MediaType describes body of given media type.
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty" yaml:"schema,omitempty"`
+}
+
+// Components holds reusable schemas.
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas,omitempty" yaml:"schemas,omitempty"`
+}
+
+// Schema is JSON Schema of a value.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty" yaml:"type,omitempty"`
+	Format               string             `json:"format,omitempty" yaml:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	Items                *Schema            `json:"items,omitempty" yaml:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty" yaml:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty" yaml:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+}
+
+// Generate builds OpenAPI document from routes.
+func Generate(routes []*echo.Route, config Config) *Document {
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    config.Info,
+		Servers: config.Servers,
+		Paths:   map[string]*PathItem{},
+	}
+	if doc.Info.Title == "" {
+		doc.Info.Title = "API"
+	}
+	if doc.Info.Version == "" {
+		doc.Info.Version = "1.0.0"
+	}
+
+	g := &generator{schemas: map[string]*Schema{}, names: map[reflect.Type]string{}}
+	for _, route := range sortedRoutes(routes) {
+		if route.Method == echo.RouteNotFound || (config.Skipper != nil && config.Skipper(route)) {
+			continue
+		}
+		path, pathParams := templatePath(route.Path)
+		item := doc.Paths[path]
+		if item == nil {
+			item = &PathItem{}
+		}
+		if !item.set(route.Method, g.operation(route, pathParams)) {
+			continue // methods that OpenAPI does not support (i.e. PROPFIND) are not documented
+		}
+		doc.Paths[path] = item
+	}
+	if len(g.schemas) > 0 {
+		doc.Components = &Components{Schemas: g.schemas}
+	}
+	return doc
+}
+
+// Handler returns handler that serves OpenAPI document of routes registered to the default router of e as JSON.
+func Handler(e *echo.Echo, config Config) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, Generate(e.Routes(), config))
+	}
+}
+
+// sortedRoutes sorts routes by path and method so generated documents are stable.
+func sortedRoutes(routes []*echo.Route) []*echo.Route {
+	sorted := make([]*echo.Route, len(routes))
+	copy(sorted, routes)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Path == sorted[j].Path {
+			return sorted[i].Method < sorted[j].Method
+		}
+		return sorted[i].Path < sorted[j].Path
+	})
+	return sorted
+}
+
+// pathParam is path parameter of route path with its constraint (i.e. `int` for `:id<int>`).
+type pathParam struct {
+	name       string
+	constraint string
+	// wildcard is true for `*` param. It is named `wildcard` in path template as `*` is not valid template expression.
+	wildcard bool
+}
+
+// bindName returns name the param value is bound with, i.e. by `param` struct tag.
+func (p pathParam) bindName() string {
+	if p.wildcard {
+		return "*"
+	}
+	return p.name
+}
+
+// templatePath converts Echo route path to OpenAPI path template, i.e. `/users/:id<int>/*` to
+// `/users/{id}/{wildcard}`, and returns path parameters.
+func templatePath(path string) (string, []pathParam) {
+	var params []pathParam
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		switch {
+		case path[i] == ':' && (i == 0 || path[i-1] == '/'):
+			p, end := parsePathParam(path, i+1)
+			params = append(params, p)
+			b.WriteString("{" + p.name + "}")
+			i = end - 1
+		case path[i] == '*' && i > 0 && path[i-1] == '/' && (i+1 == len(path) || path[i+1] == '/'):
+			p := pathParam{name: wildcardParamName(params), wildcard: true}
+			params = append(params, p)
+			b.WriteString("{" + p.name + "}")
+		default:
+			b.WriteByte(path[i])
+		}
+	}
+	return b.String(), params
+}
+
+// wildcardParamName returns name for `*` param that is not used by other params of the path.
+func wildcardParamName(params []pathParam) string {
+	name := "wildcard"
+	for i := 2; slices.ContainsFunc(params, func(p pathParam) bool { return p.name == name }); i++ {
+		name = "wildcard" + strconv.Itoa(i)
+	}
+	return name
+}
+
+// parsePathParam parses path parameter starting at index i and returns it with index where the parameter ends.
+func parsePathParam(path string, i int) (pathParam, int) {
+	start := i
+	for ; i < len(path) && path[i] != '/' && path[i] != '<'; i++ {
+	}
+	p := pathParam{name: path[start:i]}
+	if i < len(path) && path[i] == '<' {
+		depth := 0
+		for j := i; j < len(path); j++ {
+			if path[j] == '<' {
+				depth++
+			} else if path[j] == '>' {
+				depth--
+				if depth == 0 {
+					p.constraint = path[i+1 : j]
+					return p, j + 1
+				}
+			}
+		}
+	}
+	return p, i
+}
+
+// constraintSchema describes values accepted by path parameter constraint.
+func constraintSchema(constraint string) *Schema {
+	switch constraint {
+	case "":
+		return &Schema{Type: "string"}
+	case "int":
+		return &Schema{Type: "integer", Format: "int64"}
+	case "uuid":
+		return &Schema{Type: "string", Format: "uuid"}
+	}
+	if isConstraintName(constraint) {
+		return &Schema{Type: "string"} // user registered constraint that can not be described
+	}
+	return &Schema{Type: "string", Pattern: "^(?:" + constraint + ")$"}
+}
+
+func isConstraintName(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || i > 0 && '0' <= c && c <= '9') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func (p *PathItem) set(method string, op *Operation) bool {
+	switch method {
+	case http.MethodGet:
+		p.Get = op
+	case http.MethodPut:
+		p.Put = op
+	case http.MethodPost:
+		p.Post = op
+	case http.MethodDelete:
+		p.Delete = op
+	case http.MethodOptions:
+		p.Options = op
+	case http.MethodHead:
+		p.Head = op
+	case http.MethodPatch:
+		p.Patch = op
+	case http.MethodTrace:
+		p.Trace = op
+	default:
+		return false
+	}
+	return true
+}
+
+func (g *generator) operation(route *echo.Route, pathParams []pathParam) *Operation {
+	info := route.Info()
+	op := &Operation{
+		Tags:        info.Tags,
+		Summary:     info.Summary,
+		Description: info.Description,
+		Responses:   map[string]*Response{},
+	}
+
+	fields := requestFields{}
+	if info.RequestType != nil {
+		fields = g.requestFields(info.RequestType)
+	}
+	for _, p := range pathParams {
+		schema := fields.path[p.bindName()]
+		if schema == nil {
+			schema = constraintSchema(p.constraint)
+		}
+		op.Parameters = append(op.Parameters, &Parameter{Name: p.name, In: "path", Required: true, Schema: schema})
+	}
+	op.Parameters = append(op.Parameters, fields.query...)
+	op.Parameters = append(op.Parameters, fields.header...)
+
+	if hasBody(route.Method) {
+		content := map[string]*MediaType{}
+		if fields.json != nil {
+			content[echo.MIMEApplicationJSON] = &MediaType{Schema: fields.json}
+		}
+		if fields.form != nil {
+			content[echo.MIMEApplicationForm] = &MediaType{Schema: fields.form}
+			content[echo.MIMEMultipartForm] = &MediaType{Schema: fields.form}
+		}
+		if len(content) > 0 {
+			op.RequestBody = &RequestBody{Content: content}
+		}
+	}
+
+	if len(info.Responses) > 0 {
+		for code, t := range info.Responses {
+			op.Responses[strconv.Itoa(code)] = g.response(code, t)
+		}
+	} else {
+		op.Responses[strconv.Itoa(http.StatusOK)] = g.response(http.StatusOK, info.ResponseType)
+	}
+	return op
+}
+
+func (g *generator) response(code int, body reflect.Type) *Response {
+	res := &Response{Description: http.StatusText(code)}
+	if res.Description == "" {
+		res.Description = "Status " + strconv.Itoa(code)
+	}
+	if body != nil {
+		res.Content = map[string]*MediaType{
+			echo.MIMEApplicationJSON: {Schema: g.schema(body)},
+		}
+	}
+	return res
+}
+
+func hasBody(method string) bool {
+	return method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch
+}
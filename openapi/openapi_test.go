@@ -0,0 +1,289 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type testAddress struct {
+	City string `json:"city"`
+}
+
+type testUser struct {
+	ID        int64        `json:"id"`
+	Name      string       `json:"name"`
+	Email     string       `json:"email,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+	Address   *testAddress `json:"address,omitempty"`
+	Friends   []*testUser  `json:"friends,omitempty"`
+	secret    string
+}
+
+type testUpdateUserRequest struct {
+	ID      int64             `param:"id"`
+	DryRun  bool              `query:"dry_run"`
+	TraceID string            `header:"X-Trace-Id,required"`
+	Name    string            `json:"name"`
+	Labels  map[string]string `json:"labels"`
+	Ignored string            `json:"-"`
+}
+
+type testUploadRequest struct {
+	Title string `form:"title,required"`
+	Tags  []string
+}
+
+func TestGenerate(t *testing.T) {
+	e := echo.New()
+	echo.HandleRoute(e, http.MethodPut, "/users/:id", func(c echo.Context, req testUpdateUserRequest) (testUser, error) {
+		return testUser{}, nil
+	}).WithSummary("Update user").WithTags("users")
+	e.GET("/users/:id", func(c echo.Context) error {
+		return nil
+	}).WithDescription("Returns user").
+		WithResponse(http.StatusOK, testUser{}).
+		WithResponse(http.StatusNotFound, nil)
+	echo.HandleRoute(e, http.MethodPost, "/files/*", func(c echo.Context, req testUploadRequest) (string, error) {
+		return "", nil
+	})
+	e.RouteNotFound("/*", func(c echo.Context) error {
+		return nil
+	})
+	e.GET("/internal", func(c echo.Context) error {
+		return nil
+	})
+
+	doc := Generate(e.Routes(), Config{
+		Info:    Info{Title: "Test API", Version: "2.0.0"},
+		Skipper: func(route *echo.Route) bool { return route.Path == "/internal" },
+	})
+
+	b, err := json.Marshal(doc)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"openapi": "3.1.0",
+		"info": {"title": "Test API", "version": "2.0.0"},
+		"paths": {
+			"/files/{wildcard}": {
+				"post": {
+					"parameters": [{"name": "wildcard", "in": "path", "required": true, "schema": {"type": "string"}}],
+					"requestBody": {
+						"content": {
+							"application/json": {"schema": {"type": "object", "properties": {"Tags": {"type": "array", "items": {"type": "string"}}}}},
+							"application/x-www-form-urlencoded": {"schema": {"type": "object", "properties": {"title": {"type": "string"}}, "required": ["title"]}},
+							"multipart/form-data": {"schema": {"type": "object", "properties": {"title": {"type": "string"}}, "required": ["title"]}}
+						}
+					},
+					"responses": {"200": {"description": "OK", "content": {"application/json": {"schema": {"type": "string"}}}}}
+				}
+			},
+			"/users/{id}": {
+				"get": {
+					"description": "Returns user",
+					"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+					"responses": {
+						"200": {"description": "OK", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/testUser"}}}},
+						"404": {"description": "Not Found"}
+					}
+				},
+				"put": {
+					"tags": ["users"],
+					"summary": "Update user",
+					"parameters": [
+						{"name": "id", "in": "path", "required": true, "schema": {"type": "integer", "format": "int64"}},
+						{"name": "dry_run", "in": "query", "schema": {"type": "boolean"}},
+						{"name": "X-Trace-Id", "in": "header", "required": true, "schema": {"type": "string"}}
+					],
+					"requestBody": {
+						"content": {
+							"application/json": {
+								"schema": {
+									"type": "object",
+									"properties": {
+										"name": {"type": "string"},
+										"labels": {"type": "object", "additionalProperties": {"type": "string"}}
+									}
+								}
+							}
+						}
+					},
+					"responses": {"200": {"description": "OK", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/testUser"}}}}}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"testAddress": {"type": "object", "properties": {"city": {"type": "string"}}, "required": ["city"]},
+				"testUser": {
+					"type": "object",
+					"properties": {
+						"id": {"type": "integer", "format": "int64"},
+						"name": {"type": "string"},
+						"email": {"type": "string"},
+						"created_at": {"type": "string", "format": "date-time"},
+						"address": {"$ref": "#/components/schemas/testAddress"},
+						"friends": {"type": "array", "items": {"$ref": "#/components/schemas/testUser"}}
+					},
+					"required": ["id", "name", "created_at"]
+				}
+			}
+		}
+	}`, string(b))
+}
+
+func TestTemplatePath(t *testing.T) {
+	var testCases = []struct {
+		when         string
+		expectPath   string
+		expectParams []pathParam
+	}{
+		{when: "/", expectPath: "/"},
+		{when: "/users/:id", expectPath: "/users/{id}", expectParams: []pathParam{{name: "id"}}},
+		{
+			when:         "/users/:id/files/*",
+			expectPath:   "/users/{id}/files/{wildcard}",
+			expectParams: []pathParam{{name: "id"}, {name: "wildcard", wildcard: true}},
+		},
+		{
+			when:         "/:wildcard/*",
+			expectPath:   "/{wildcard}/{wildcard2}",
+			expectParams: []pathParam{{name: "wildcard"}, {name: "wildcard2", wildcard: true}},
+		},
+		{
+			when:         "/users/:id<int>/posts/:slug<[a-z/]+>",
+			expectPath:   "/users/{id}/posts/{slug}",
+			expectParams: []pathParam{{name: "id", constraint: "int"}, {name: "slug", constraint: "[a-z/]+"}},
+		},
+		{when: "/static", expectPath: "/static"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.when, func(t *testing.T) {
+			path, params := templatePath(tc.when)
+			assert.Equal(t, tc.expectPath, path)
+			assert.Equal(t, tc.expectParams, params)
+		})
+	}
+}
+
+func TestConstraintSchema(t *testing.T) {
+	var testCases = []struct {
+		when   string
+		expect *Schema
+	}{
+		{when: "", expect: &Schema{Type: "string"}},
+		{when: "int", expect: &Schema{Type: "integer", Format: "int64"}},
+		{when: "uuid", expect: &Schema{Type: "string", Format: "uuid"}},
+		{when: "even", expect: &Schema{Type: "string"}},
+		{when: "[a-z]+", expect: &Schema{Type: "string", Pattern: "^(?:[a-z]+)$"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.when, func(t *testing.T) {
+			assert.Equal(t, tc.expect, constraintSchema(tc.when))
+		})
+	}
+}
+
+func TestGenerator_schemaComponentNames(t *testing.T) {
+	type Page[T any] struct {
+		Items []T `json:"items"`
+	}
+	g := &generator{schemas: map[string]*Schema{}, names: map[reflect.Type]string{}}
+
+	s := g.schema(reflect.TypeOf(Page[testUser]{}))
+
+	assert.Equal(t, "#/components/schemas/Page_github.com_labstack_echo_v4_openapi.testUser_", s.Ref)
+	assert.Contains(t, g.schemas, "testUser")
+}
+
+type testTree map[string]testTree
+
+type testList []testList
+
+type testNode struct {
+	Children testNodes `json:"children"`
+}
+
+type testNodes []testNode
+
+func TestGenerator_schemaRecursiveTypes(t *testing.T) {
+	var testCases = []struct {
+		name          string
+		when          interface{}
+		expect        *Schema
+		expectSchemas map[string]*Schema
+	}{
+		{
+			name:   "map of itself",
+			when:   testTree{},
+			expect: &Schema{Ref: "#/components/schemas/testTree"},
+			expectSchemas: map[string]*Schema{
+				"testTree": {Type: "object", AdditionalProperties: &Schema{Ref: "#/components/schemas/testTree"}},
+			},
+		},
+		{
+			name:   "slice of itself",
+			when:   testList{},
+			expect: &Schema{Ref: "#/components/schemas/testList"},
+			expectSchemas: map[string]*Schema{
+				"testList": {Type: "array", Items: &Schema{Ref: "#/components/schemas/testList"}},
+			},
+		},
+		{
+			name:   "slice recurring through struct",
+			when:   testNodes{},
+			expect: &Schema{Ref: "#/components/schemas/testNodes"},
+			expectSchemas: map[string]*Schema{
+				"testNode": {
+					Type:       "object",
+					Properties: map[string]*Schema{"children": {Ref: "#/components/schemas/testNodes"}},
+					Required:   []string{"children"},
+				},
+				"testNodes": {Type: "array", Items: &Schema{Ref: "#/components/schemas/testNode"}},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := &generator{schemas: map[string]*Schema{}, names: map[reflect.Type]string{}}
+
+			s := g.schema(reflect.TypeOf(tc.when))
+
+			assert.Equal(t, tc.expect, s)
+			assert.Equal(t, tc.expectSchemas, g.schemas)
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	e := echo.New()
+	e.GET("/users/:id", func(c echo.Context) error {
+		return nil
+	}).WithSummary("Get user")
+	h := Handler(e, Config{Info: Info{Title: "Test API"}})
+
+	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	rec := httptest.NewRecorder()
+	err := h(e.NewContext(req, rec))
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, echo.MIMEApplicationJSON, rec.Header().Get(echo.HeaderContentType))
+
+	doc := Document{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Equal(t, "3.1.0", doc.OpenAPI)
+	assert.Equal(t, Info{Title: "Test API", Version: "1.0.0"}, doc.Info)
+	assert.Equal(t, "Get user", doc.Paths["/users/{id}"].Get.Summary)
+}
@@ -0,0 +1,263 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package openapi
+
+import (
+	"encoding"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+	invalidComponentChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+)
+
+// generator builds schemas and keeps track of named struct types and recursive named types that are described as
+// reusable components.
+type generator struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+	// building contains named slice, array and map types which schemas are being built.
+	building map[reflect.Type]bool
+}
+
+// requestFields are request type fields grouped by where `echo.DefaultBinder` binds them from.
+type requestFields struct {
+	path   map[string]*Schema
+	query  []*Parameter
+	header []*Parameter
+	form   *Schema
+	json   *Schema
+}
+
+type structField struct {
+	name      string
+	omitEmpty bool
+}
+
+func (g *generator) requestFields(t reflect.Type) requestFields {
+	fields := requestFields{path: map[string]*Schema{}}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		if t.Kind() != reflect.Interface {
+			fields.json = g.schema(t)
+		}
+		return fields
+	}
+
+	walkFields(t, func(f reflect.StructField) {
+		bound := false
+		if name := tagName(f, "param"); name != "" {
+			fields.path[name] = g.schema(f.Type)
+			bound = true
+		}
+		if name := tagName(f, "query"); name != "" {
+			fields.query = append(fields.query, &Parameter{Name: name, In: "query", Required: tagRequired(f, "query"), Schema: g.schema(f.Type)})
+			bound = true
+		}
+		if name := tagName(f, "header"); name != "" {
+			fields.header = append(fields.header, &Parameter{Name: name, In: "header", Required: tagRequired(f, "header"), Schema: g.schema(f.Type)})
+			bound = true
+		}
+		if name := tagName(f, "form"); name != "" {
+			if fields.form == nil {
+				fields.form = &Schema{Type: "object", Properties: map[string]*Schema{}}
+			}
+			fields.form.Properties[name] = g.schema(f.Type)
+			if tagRequired(f, "form") {
+				fields.form.Required = append(fields.form.Required, name)
+			}
+			bound = true
+		}
+
+		// fields without json tag that are bound from other sources are not part of the JSON body
+		jsonTag, hasJSONTag := f.Tag.Lookup("json")
+		if (bound && !hasJSONTag) || jsonTag == "-" {
+			return
+		}
+		sf, ok := jsonField(f)
+		if !ok {
+			return
+		}
+		if fields.json == nil {
+			fields.json = &Schema{Type: "object", Properties: map[string]*Schema{}}
+		}
+		fields.json.Properties[sf.name] = g.schema(f.Type)
+	})
+	return fields
+}
+
+// schema returns schema for type. Named struct types and recursive named types are added to components and referenced.
+func (g *generator) schema(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+	if t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return g.container(t, func() *Schema {
+			return &Schema{Type: "array", Items: g.schema(t.Elem())}
+		})
+	case reflect.Map:
+		return g.container(t, func() *Schema {
+			return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+		})
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.objectSchema(t)
+		}
+		return g.component(t)
+	}
+	return &Schema{}
+}
+
+func (g *generator) component(t reflect.Type) *Schema {
+	if _, ok := g.names[t]; !ok {
+		s := g.addComponent(t) // registered before building properties to allow recursive types
+		*s = *g.objectSchema(t)
+	}
+	return componentRef(g.names[t])
+}
+
+// container returns schema built with build for slice, array or map type. Named types that contain themselves, i.e.
+// `type Tree map[string]Tree`, are added to components and referenced where they recur.
+func (g *generator) container(t reflect.Type, build func() *Schema) *Schema {
+	if t.Name() == "" {
+		return build() // unnamed type can recur only through named type
+	}
+	if name, ok := g.names[t]; ok {
+		return componentRef(name)
+	}
+	if g.building[t] {
+		g.addComponent(t)
+		return componentRef(g.names[t])
+	}
+
+	if g.building == nil {
+		g.building = map[reflect.Type]bool{}
+	}
+	g.building[t] = true
+	s := build()
+	delete(g.building, t)
+
+	if name, ok := g.names[t]; ok {
+		*g.schemas[name] = *s
+		return componentRef(name)
+	}
+	return s
+}
+
+// addComponent adds empty schema for type to components under unique name derived from type name.
+func (g *generator) addComponent(t reflect.Type) *Schema {
+	base := invalidComponentChars.ReplaceAllString(t.Name(), "_")
+	name := base
+	for i := 2; g.schemas[name] != nil; i++ {
+		name = base + strconv.Itoa(i)
+	}
+	g.names[t] = name
+	s := &Schema{}
+	g.schemas[name] = s
+	return s
+}
+
+func componentRef(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// objectSchema describes struct type as JSON object. Fields without `omitempty` option are required as they are
+// always present in marshalled JSON.
+func (g *generator) objectSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	walkFields(t, func(f reflect.StructField) {
+		sf, ok := jsonField(f)
+		if !ok {
+			return
+		}
+		s.Properties[sf.name] = g.schema(f.Type)
+		if !sf.omitEmpty {
+			s.Required = append(s.Required, sf.name)
+		}
+	})
+	return s
+}
+
+// walkFields calls fn for exported fields of struct type. Fields of embedded structs without json name are walked
+// as they were fields of the outer struct (same as `encoding/json` does).
+func walkFields(t reflect.Type, fn func(f reflect.StructField)) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct && tagName(f, "json") == "" {
+				walkFields(ft, fn)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		fn(f)
+	}
+}
+
+func jsonField(f reflect.StructField) (structField, bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return structField{}, false
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = f.Name
+	}
+	return structField{
+		name:      name,
+		omitEmpty: strings.Contains(","+opts+",", ",omitempty,") || strings.Contains(","+opts+",", ",omitzero,"),
+	}, true
+}
+
+// tagRequired checks if binding tag has `required` option, i.e. `query:"id,required"`.
+func tagRequired(f reflect.StructField, tag string) bool {
+	_, opts, _ := strings.Cut(f.Tag.Get(tag), ",")
+	return strings.Contains(","+opts+",", ",required,")
+}
+
+func tagName(f reflect.StructField, tag string) string {
+	name, _, _ := strings.Cut(f.Tag.Get(tag), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
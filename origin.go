@@ -0,0 +1,130 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"regexp"
+	"strings"
+)
+
+// OriginMatcher matches request `Origin` header values against list of allowed origins. It is used by the CORS
+// middleware and by WebSocket origin check.
+//
+// Allowed origin can be:
+//   - "*" that allows any origin,
+//   - exact origin, i.e. "https://example.com",
+//   - origin with wildcard subdomain, i.e. "https://*.example.com",
+//   - pattern where '*' and '?' are converted to regex fragments '.*' and '.' accordingly.
+//
+// Security: use extreme caution when handling the origin, and carefully
+// validate any logic. Remember that attackers may register hostile domain names.
+// See https://blog.portswigger.net/2016/10/exploiting-cors-misconfigurations-for.html
+type OriginMatcher struct {
+	allowOrigins []string
+	patterns     []*regexp.Regexp
+}
+
+// NewOriginMatcher creates new instance of OriginMatcher for given allowed origins.
+func NewOriginMatcher(allowOrigins []string) *OriginMatcher {
+	patterns := make([]*regexp.Regexp, 0, len(allowOrigins))
+	for _, origin := range allowOrigins {
+		if origin == "*" {
+			continue // "*" is handled differently and does not need regexp
+		}
+		pattern := regexp.QuoteMeta(origin)
+		pattern = strings.ReplaceAll(pattern, "\\*", ".*")
+		pattern = strings.ReplaceAll(pattern, "\\?", ".")
+		pattern = "^" + pattern + "$"
+
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			// this is to preserve previous behaviour - invalid patterns were just ignored.
+			// If we would turn this to panic, users with invalid patterns
+			// would have applications crashing in production due unrecovered panic.
+			// TODO: this should be turned to error/panic in `v5`
+			continue
+		}
+		patterns = append(patterns, re)
+	}
+	return &OriginMatcher{
+		allowOrigins: allowOrigins,
+		patterns:     patterns,
+	}
+}
+
+// Match returns the allowed origin value that matched the origin. Returns "*" when origin matched wildcard, origin
+// itself when it matched specific allowed origin or pattern and empty string when origin is not allowed.
+func (m *OriginMatcher) Match(origin string) string {
+	if origin == "" {
+		return ""
+	}
+	for _, o := range m.allowOrigins {
+		if o == "*" || o == origin {
+			return o
+		}
+		if matchSubdomain(origin, o) {
+			return origin
+		}
+	}
+
+	// to avoid regex cost by invalid (long) domains (253 is domain name max limit)
+	if len(origin) > (253+3+5) || !strings.Contains(origin, "://") {
+		return ""
+	}
+	for _, re := range m.patterns {
+		if re.MatchString(origin) {
+			return origin
+		}
+	}
+	return ""
+}
+
+func matchScheme(domain, pattern string) bool {
+	didx := strings.Index(domain, ":")
+	pidx := strings.Index(pattern, ":")
+	return didx != -1 && pidx != -1 && domain[:didx] == pattern[:pidx]
+}
+
+// matchSubdomain compares authority with wildcard
+func matchSubdomain(domain, pattern string) bool {
+	if !matchScheme(domain, pattern) {
+		return false
+	}
+	didx := strings.Index(domain, "://")
+	pidx := strings.Index(pattern, "://")
+	if didx == -1 || pidx == -1 {
+		return false
+	}
+	domAuth := domain[didx+3:]
+	// to avoid long loop by invalid long domain
+	if len(domAuth) > 253 {
+		return false
+	}
+	patAuth := pattern[pidx+3:]
+
+	domComp := strings.Split(domAuth, ".")
+	patComp := strings.Split(patAuth, ".")
+	for i := len(domComp)/2 - 1; i >= 0; i-- {
+		opp := len(domComp) - 1 - i
+		domComp[i], domComp[opp] = domComp[opp], domComp[i]
+	}
+	for i := len(patComp)/2 - 1; i >= 0; i-- {
+		opp := len(patComp) - 1 - i
+		patComp[i], patComp[opp] = patComp[opp], patComp[i]
+	}
+
+	for i, v := range domComp {
+		if len(patComp) <= i {
+			return false
+		}
+		p := patComp[i]
+		if p == "*" {
+			return true
+		}
+		if p != v {
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOriginMatcher_Match(t *testing.T) {
+	var testCases = []struct {
+		name         string
+		givenOrigins []string
+		whenOrigin   string
+		expect       string
+	}{
+		{
+			name:         "ok, wildcard",
+			givenOrigins: []string{"*"},
+			whenOrigin:   "https://example.com",
+			expect:       "*",
+		},
+		{
+			name:         "ok, exact",
+			givenOrigins: []string{"https://example.com"},
+			whenOrigin:   "https://example.com",
+			expect:       "https://example.com",
+		},
+		{
+			name:         "ok, subdomain",
+			givenOrigins: []string{"https://*.example.com"},
+			whenOrigin:   "https://api.example.com",
+			expect:       "https://api.example.com",
+		},
+		{
+			name:         "ok, pattern",
+			givenOrigins: []string{"https://example.co?"},
+			whenOrigin:   "https://example.com",
+			expect:       "https://example.com",
+		},
+		{
+			name:         "nok, scheme mismatch",
+			givenOrigins: []string{"https://example.com"},
+			whenOrigin:   "http://example.com",
+			expect:       "",
+		},
+		{
+			name:         "nok, empty origin",
+			givenOrigins: []string{"*"},
+			whenOrigin:   "",
+			expect:       "",
+		},
+		{
+			name:         "nok, no allowed origins",
+			givenOrigins: nil,
+			whenOrigin:   "https://example.com",
+			expect:       "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := NewOriginMatcher(tc.givenOrigins)
+			assert.Equal(t, tc.expect, m.Match(tc.whenOrigin))
+		})
+	}
+}
+
+func Test_matchScheme(t *testing.T) {
+	tests := []struct {
+		domain, pattern string
+		expected        bool
+	}{
+		{
+			domain:   "http://example.com",
+			pattern:  "http://example.com",
+			expected: true,
+		},
+		{
+			domain:   "https://example.com",
+			pattern:  "https://example.com",
+			expected: true,
+		},
+		{
+			domain:   "http://example.com",
+			pattern:  "https://example.com",
+			expected: false,
+		},
+		{
+			domain:   "https://example.com",
+			pattern:  "http://example.com",
+			expected: false,
+		},
+	}
+
+	for _, v := range tests {
+		assert.Equal(t, v.expected, matchScheme(v.domain, v.pattern))
+	}
+}
+
+func Test_matchSubdomain(t *testing.T) {
+	tests := []struct {
+		domain, pattern string
+		expected        bool
+	}{
+		{
+			domain:   "http://aaa.example.com",
+			pattern:  "http://*.example.com",
+			expected: true,
+		},
+		{
+			domain:   "http://bbb.aaa.example.com",
+			pattern:  "http://*.example.com",
+			expected: true,
+		},
+		{
+			domain:   "http://bbb.aaa.example.com",
+			pattern:  "http://*.aaa.example.com",
+			expected: true,
+		},
+		{
+			domain:   "http://aaa.example.com:8080",
+			pattern:  "http://*.example.com:8080",
+			expected: true,
+		},
+
+		{
+			domain:   "http://fuga.hoge.com",
+			pattern:  "http://*.example.com",
+			expected: false,
+		},
+		{
+			domain:   "http://ccc.bbb.example.com",
+			pattern:  "http://*.aaa.example.com",
+			expected: false,
+		},
+		{
+			domain: `http://1234567890.1234567890.1234567890.1234567890.1234567890.1234567890.1234567890.1234567890.1234567890.1234567890\
+      .1234567890.1234567890.1234567890.1234567890.1234567890.1234567890.1234567890.1234567890.1234567890.1234567890\
+      .1234567890.1234567890.1234567890.1234567890.1234567890.1234567890.1234567890.1234567890.1234567890.1234567890\
+      .1234567890.1234567890.1234567890.1234567890.1234567890.1234567890.1234567890.1234567890.1234567890.1234567890.example.com`,
+			pattern:  "http://*.example.com",
+			expected: false,
+		},
+		{
+			domain:   "http://ccc.bbb.example.com",
+			pattern:  "http://example.com",
+			expected: false,
+		},
+	}
+
+	for _, v := range tests {
+		assert.Equal(t, v.expected, matchSubdomain(v.domain, v.pattern))
+	}
+}
@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// ParamConstraintFunc reports whether path parameter value satisfies the constraint.
+type ParamConstraintFunc func(value string) bool
+
+// ParamConstraintRegistry holds named path parameter constraints that can be used in route paths as
+// `:name<constraint>`. Constraints are evaluated by Router when request path is matched - when value does not satisfy
+// the constraint Router tries other routes and results 404 Not Found when no other route matches.
+//
+// Constraint that is not registered name is compiled as regular expression that must match the whole value, i.e.
+// `/posts/:slug<[a-z0-9-]+>`. Constraints are resolved when route is added so names must be registered before routes
+// using them.
+type ParamConstraintRegistry struct {
+	constraints map[string]ParamConstraintFunc
+}
+
+// paramConstraint is constraint of a single path parameter in router tree.
+type paramConstraint struct {
+	// expr is constraint as written in route path between `<` and `>`. Param nodes with same expr are shared.
+	expr  string
+	match ParamConstraintFunc
+}
+
+var (
+	defaultParamConstraints = NewParamConstraintRegistry()
+
+	paramConstraintName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// NewParamConstraintRegistry creates new instance of ParamConstraintRegistry with default constraints:
+//   - `int` matches base 10 integers that fit into int64,
+//   - `uuid` matches UUIDs in canonical `xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx` form.
+func NewParamConstraintRegistry() *ParamConstraintRegistry {
+	r := &ParamConstraintRegistry{constraints: map[string]ParamConstraintFunc{}}
+	r.Register("int", isIntParam)
+	r.Register("uuid", isUUIDParam)
+	return r
+}
+
+// Register adds named constraint. Constraint with the same name is replaced.
+//
+// Example: `e.ParamConstraints.Register("even", func(v string) bool { n, err := strconv.Atoi(v); return err == nil && n%2 == 0 })`
+func (r *ParamConstraintRegistry) Register(name string, fn ParamConstraintFunc) {
+	r.constraints[name] = fn
+}
+
+// RegisterPattern adds named constraint that matches values with regular expression. Expression must match the whole
+// value. Panics when expression is not valid.
+//
+// Example: `e.ParamConstraints.RegisterPattern("slug", "[a-z0-9-]+")`
+func (r *ParamConstraintRegistry) RegisterPattern(name string, pattern string) {
+	re := regexp.MustCompile(`^(?:` + pattern + `)$`)
+	r.Register(name, re.MatchString)
+}
+
+// Lookup returns named constraint. Nil registry contains only default constraints.
+func (r *ParamConstraintRegistry) Lookup(name string) (ParamConstraintFunc, bool) {
+	if r == nil {
+		r = defaultParamConstraints
+	}
+	fn, ok := r.constraints[name]
+	return fn, ok
+}
+
+// compile resolves constraint expression to registered named constraint or to regular expression.
+func (r *ParamConstraintRegistry) compile(expr string) (*paramConstraint, error) {
+	if fn, ok := r.Lookup(expr); ok {
+		return &paramConstraint{expr: expr, match: fn}, nil
+	}
+	if paramConstraintName.MatchString(expr) {
+		return nil, fmt.Errorf("unknown constraint %q", expr)
+	}
+	re, err := regexp.Compile(`^(?:` + expr + `)$`)
+	if err != nil {
+		return nil, err
+	}
+	return &paramConstraint{expr: expr, match: re.MatchString}, nil
+}
+
+func isIntParam(value string) bool {
+	_, err := strconv.ParseInt(value, 10, 64)
+	return err == nil
+}
+
+func isUUIDParam(value string) bool {
+	if len(value) != 36 {
+		return false
+	}
+	for i := 0; i < len(value); i++ {
+		switch i {
+		case 8, 13, 18, 23:
+			if value[i] != '-' {
+				return false
+			}
+		default:
+			c := value[i]
+			if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// paramEnd returns index where name of path parameter starting at index i ends and index where the parameter
+// including optional `<constraint>` ends.
+func paramEnd(path string, i int) (nameEnd int, end int) {
+	for ; i < len(path) && path[i] != '/' && path[i] != '<'; i++ {
+	}
+	nameEnd = i
+	if i < len(path) && path[i] == '<' {
+		depth := 0
+		for ; i < len(path); i++ {
+			if path[i] == '<' {
+				depth++
+			} else if path[i] == '>' {
+				depth--
+				if depth == 0 {
+					i++
+					break
+				}
+			}
+		}
+	}
+	return nameEnd, i
+}
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParamConstraintRegistry_defaults(t *testing.T) {
+	var testCases = []struct {
+		name       string
+		whenValue  string
+		expectInt  bool
+		expectUUID bool
+	}{
+		{name: "ok, integer", whenValue: "123", expectInt: true},
+		{name: "ok, negative integer", whenValue: "-1", expectInt: true},
+		{name: "ok, uuid", whenValue: "0B5D8AB8-3c59-4bcb-9e7c-3fb9a1d4d7c1", expectUUID: true},
+		{name: "nok, empty", whenValue: ""},
+		{name: "nok, integer overflow", whenValue: "9223372036854775808"},
+		{name: "nok, uuid without dashes", whenValue: "0b5d8ab83c594bcb9e7c3fb9a1d4d7c1aaaa"},
+		{name: "nok, uuid with invalid character", whenValue: "0b5d8ab8-3c59-4bcb-9e7c-3fb9a1d4d7cx"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := NewParamConstraintRegistry()
+
+			isInt, ok := r.Lookup("int")
+			assert.True(t, ok)
+			assert.Equal(t, tc.expectInt, isInt(tc.whenValue))
+
+			isUUID, ok := r.Lookup("uuid")
+			assert.True(t, ok)
+			assert.Equal(t, tc.expectUUID, isUUID(tc.whenValue))
+		})
+	}
+}
+
+func TestParamConstraintRegistry_RegisterPattern(t *testing.T) {
+	r := NewParamConstraintRegistry()
+	r.RegisterPattern("slug", "[a-z0-9-]+")
+
+	fn, ok := r.Lookup("slug")
+	assert.True(t, ok)
+	assert.True(t, fn("hello-world-1"))
+	assert.False(t, fn("Hello"))
+	assert.False(t, fn("hello world"))
+}
+
+func TestParamConstraintRegistry_LookupNil(t *testing.T) {
+	var r *ParamConstraintRegistry
+
+	_, ok := r.Lookup("int")
+	assert.True(t, ok)
+
+	_, ok = r.Lookup("slug")
+	assert.False(t, ok)
+}
+
+func TestParamConstraintRegistry_compile(t *testing.T) {
+	var testCases = []struct {
+		whenExpr    string
+		whenValue   string
+		expectMatch bool
+		expectError string
+	}{
+		{whenExpr: "int", whenValue: "1", expectMatch: true},
+		{whenExpr: "[a-z]+", whenValue: "abc", expectMatch: true},
+		{whenExpr: "[a-z]+", whenValue: "abc1", expectMatch: false},
+		{whenExpr: "a|b", whenValue: "ab", expectMatch: false},
+		{whenExpr: "missing", expectError: `unknown constraint "missing"`},
+		{whenExpr: "(", expectError: "error parsing regexp: missing closing ): `^(?:()$`"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.whenExpr, func(t *testing.T) {
+			c, err := NewParamConstraintRegistry().compile(tc.whenExpr)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.whenExpr, c.expr)
+			assert.Equal(t, tc.expectMatch, c.match(tc.whenValue))
+		})
+	}
+}
+
+func TestParamEnd(t *testing.T) {
+	var testCases = []struct {
+		whenPath      string
+		expectNameEnd int
+		expectEnd     int
+	}{
+		{whenPath: ":id", expectNameEnd: 3, expectEnd: 3},
+		{whenPath: ":id/files", expectNameEnd: 3, expectEnd: 3},
+		{whenPath: ":id<int>/files", expectNameEnd: 3, expectEnd: 8},
+		{whenPath: ":path<[a-z/]+>", expectNameEnd: 5, expectEnd: 14},
+		{whenPath: ":id<(?P<n>[0-9]+)>", expectNameEnd: 3, expectEnd: 18},
+		{whenPath: ":id<int", expectNameEnd: 3, expectEnd: 7},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.whenPath, func(t *testing.T) {
+			nameEnd, end := paramEnd(tc.whenPath, 1)
+			assert.Equal(t, tc.expectNameEnd, nameEnd)
+			assert.Equal(t, tc.expectEnd, end)
+		})
+	}
+}
@@ -0,0 +1,250 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+)
+
+// Problem details media types. See RFC 9457 https://www.rfc-editor.org/rfc/rfc9457
+const (
+	MIMEApplicationProblemJSON = "application/problem+json"
+	MIMEApplicationProblemXML  = "application/problem+xml"
+)
+
+// problemDetailsXMLNamespace is XML namespace for problem details documents. See RFC 9457, appendix B.
+const problemDetailsXMLNamespace = "urn:ietf:rfc:7807"
+
+// ProblemDetails is machine-readable error response as defined in RFC 9457 https://www.rfc-editor.org/rfc/rfc9457
+//
+// ProblemDetails can be returned as an error from handler or carried as `HTTPError.Message`. In both cases
+// the HTTP error handlers send it as `application/problem+json` (or `application/problem+xml` when client accepts only XML).
+type ProblemDetails struct {
+	// Type is URI reference that identifies the problem type. When empty `about:blank` is assumed.
+	Type string
+	// Title is short, human-readable summary of the problem type.
+	Title string
+	// Status is HTTP status code for this occurrence of the problem.
+	Status int
+	// Detail is human-readable explanation specific to this occurrence of the problem.
+	Detail string
+	// Instance is URI reference that identifies the specific occurrence of the problem.
+	Instance string
+	// Extensions are additional members of the problem details object. Keys that collide with standard
+	// members are ignored when encoding.
+	Extensions map[string]interface{}
+}
+
+// InvalidParam describes single request field that failed binding or validation. Problem details list these fields
+// in `invalid-params` extension member.
+type InvalidParam struct {
+	Name   string `json:"name" xml:"name"`
+	Reason string `json:"reason" xml:"reason"`
+}
+
+// InvalidParamsError is implemented by errors that know which request fields caused them, i.e. `BindingError` or
+// validation errors returned by `Validator`.
+type InvalidParamsError interface {
+	error
+	InvalidParams() []InvalidParam
+}
+
+// NewProblemDetails creates new instance of ProblemDetails for status code. Title is set to status text.
+func NewProblemDetails(status int) *ProblemDetails {
+	return &ProblemDetails{Status: status, Title: http.StatusText(status)}
+}
+
+// Error makes it compatible with `error` interface.
+func (p *ProblemDetails) Error() string {
+	if p.Detail == "" {
+		return fmt.Sprintf("status=%d, title=%v", p.Status, p.Title)
+	}
+	return fmt.Sprintf("status=%d, title=%v, detail=%v", p.Status, p.Title, p.Detail)
+}
+
+// WithExtension sets extension member to problem details and returns problem details for chaining.
+func (p *ProblemDetails) WithExtension(key string, value interface{}) *ProblemDetails {
+	if p.Extensions == nil {
+		p.Extensions = map[string]interface{}{}
+	}
+	p.Extensions[key] = value
+	return p
+}
+
+// withDefaultStatus returns copy of problem details with status set to given code when problem details has no status.
+func (p *ProblemDetails) withDefaultStatus(code int) *ProblemDetails {
+	result := *p
+	if result.Status == 0 {
+		result.Status = code
+	}
+	return &result
+}
+
+func (p *ProblemDetails) members() map[string]interface{} {
+	m := make(map[string]interface{}, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		m[k] = v
+	}
+	setOrDelete := func(key string, value interface{}, isSet bool) {
+		if isSet {
+			m[key] = value
+		} else {
+			delete(m, key)
+		}
+	}
+	setOrDelete("type", p.Type, p.Type != "")
+	setOrDelete("title", p.Title, p.Title != "")
+	setOrDelete("status", p.Status, p.Status != 0)
+	setOrDelete("detail", p.Detail, p.Detail != "")
+	setOrDelete("instance", p.Instance, p.Instance != "")
+	return m
+}
+
+// MarshalJSON encodes problem details as JSON object with extension members on the same level as standard members.
+func (p *ProblemDetails) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.members())
+}
+
+// MarshalXML encodes problem details as `<problem>` element in `urn:ietf:rfc:7807` namespace. Slices are encoded
+// as list of `<i>` elements as described in RFC 9457 appendix B.
+func (p *ProblemDetails) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
+	members := p.members()
+	keys := make([]string, 0, len(members))
+	for k := range members {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	start = xml.StartElement{
+		Name: xml.Name{Local: "problem"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: problemDetailsXMLNamespace}},
+	}
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, k := range keys {
+		el := xml.StartElement{Name: xml.Name{Local: k}}
+		v := reflect.ValueOf(members[k])
+		if (v.Kind() != reflect.Slice && v.Kind() != reflect.Array) || v.Type().Elem().Kind() == reflect.Uint8 {
+			if err := enc.EncodeElement(members[k], el); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := enc.EncodeToken(el); err != nil {
+			return err
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := enc.EncodeElement(v.Index(i).Interface(), xml.StartElement{Name: xml.Name{Local: "i"}}); err != nil {
+				return err
+			}
+		}
+		if err := enc.EncodeToken(el.End()); err != nil {
+			return err
+		}
+	}
+	return enc.EncodeToken(start.End())
+}
+
+// ProblemDetailsHTTPErrorHandler is HTTP error handler that sends all errors as RFC 9457 problem details. It can be
+// enabled with `e.HTTPErrorHandler = e.ProblemDetailsHTTPErrorHandler`.
+//
+// Errors are converted to problem details as follows:
+//   - `*ProblemDetails` (returned as error or as `HTTPError.Message`) is sent as is,
+//   - `*HTTPError` status code is used as status and string/error message as detail,
+//   - errors implementing `InvalidParamsError` (i.e. `*BindingError`) list offending fields in `invalid-params`
+//     extension member and result status 400 when they are not wrapped in `*HTTPError`,
+//   - errors matching `Echo.ErrorRegistry` mapping use mapped status and message,
+//   - all other errors result status 500.
+func (e *Echo) ProblemDetailsHTTPErrorHandler(err error, c Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	problem := e.problemDetailsFromError(c, err)
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(problem.Status)
+	} else {
+		err = writeProblemDetails(c, problem.Status, problem)
+	}
+	if err != nil {
+		e.Logger.Error(fmt.Sprintf("%v", err))
+	}
+}
+
+func (e *Echo) problemDetailsFromError(c Context, err error) *ProblemDetails {
+	var problem *ProblemDetails
+	if errors.As(err, &problem) {
+		return problem.withDefaultStatus(http.StatusInternalServerError)
+	}
+
+	status := http.StatusInternalServerError
+	var message interface{}
+	var he *HTTPError
+	var be *BindingError
+	if errors.As(err, &be) {
+		he = be.HTTPError
+	} else if errors.As(err, &he) {
+		if herr, ok := he.Internal.(*HTTPError); ok {
+			he = herr
+		}
+	} else if mapped, ok := e.mapError(c, err); ok {
+		he = mapped
+	}
+	if he != nil {
+		if p, ok := he.Message.(*ProblemDetails); ok {
+			return p.withDefaultStatus(he.Code)
+		}
+		status = he.Code
+		message = he.Message
+	}
+
+	var ipe InvalidParamsError
+	hasInvalidParams := errors.As(err, &ipe)
+	if hasInvalidParams && he == nil {
+		status = http.StatusBadRequest
+	}
+
+	problem = NewProblemDetails(status)
+	switch m := message.(type) {
+	case nil:
+	case string:
+		if m != problem.Title {
+			problem.Detail = m
+		}
+	case error:
+		problem.Detail = m.Error()
+	default:
+		problem.WithExtension("message", m)
+	}
+	if hasInvalidParams {
+		problem.WithExtension("invalid-params", ipe.InvalidParams())
+	}
+	if e.Debug {
+		problem.WithExtension("error", err.Error())
+	}
+	return problem
+}
+
+// writeProblemDetails sends problem details as `application/problem+xml` when client accepts XML but not JSON and as
+// `application/problem+json` in all other cases.
+func writeProblemDetails(c Context, code int, problem *ProblemDetails) error {
+	contentType := MIMEApplicationProblemJSON
+	accepted := NegotiateContentType(
+		c.Request().Header.Get(HeaderAccept),
+		MIMEApplicationProblemJSON, MIMEApplicationJSON, MIMEApplicationProblemXML, MIMEApplicationXML, MIMETextXML,
+	)
+	switch accepted {
+	case MIMEApplicationProblemXML, MIMEApplicationXML, MIMETextXML:
+		contentType = MIMEApplicationProblemXML
+	}
+	c.Response().Header().Add(HeaderVary, HeaderAccept)
+	return c.Encode(code, contentType, problem)
+}
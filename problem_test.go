@@ -0,0 +1,241 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testValidationError struct {
+	params []InvalidParam
+}
+
+func (e *testValidationError) Error() string {
+	return "validation failed"
+}
+
+func (e *testValidationError) InvalidParams() []InvalidParam {
+	return e.params
+}
+
+func TestProblemDetails_MarshalJSON(t *testing.T) {
+	p := &ProblemDetails{
+		Type:     "https://example.com/probs/out-of-credit",
+		Title:    "You do not have enough credit.",
+		Status:   http.StatusForbidden,
+		Detail:   "Your current balance is 30, but that costs 50.",
+		Instance: "/account/12345/msgs/abc",
+		Extensions: map[string]interface{}{
+			"balance": 30,
+			"title":   "ignored as it collides with standard member",
+		},
+	}
+
+	b, err := json.Marshal(p)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "https://example.com/probs/out-of-credit",
+		"title": "You do not have enough credit.",
+		"status": 403,
+		"detail": "Your current balance is 30, but that costs 50.",
+		"instance": "/account/12345/msgs/abc",
+		"balance": 30
+	}`, string(b))
+
+	b, err = json.Marshal(NewProblemDetails(http.StatusNotFound))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"status":404,"title":"Not Found"}`, string(b))
+}
+
+func TestProblemDetails_MarshalXML(t *testing.T) {
+	p := NewProblemDetails(http.StatusBadRequest).
+		WithExtension("invalid-params", []InvalidParam{{Name: "age", Reason: "must be positive"}})
+	p.Detail = "invalid input"
+
+	b, err := xml.Marshal(p)
+	assert.NoError(t, err)
+	expect := `<problem xmlns="urn:ietf:rfc:7807">` +
+		`<detail>invalid input</detail>` +
+		`<invalid-params><i><name>age</name><reason>must be positive</reason></i></invalid-params>` +
+		`<status>400</status>` +
+		`<title>Bad Request</title>` +
+		`</problem>`
+	assert.Equal(t, expect, string(b))
+}
+
+func TestProblemDetails_Error(t *testing.T) {
+	p := NewProblemDetails(http.StatusNotFound)
+	assert.EqualError(t, p, "status=404, title=Not Found")
+
+	p.Detail = "user not found"
+	assert.EqualError(t, p, "status=404, title=Not Found, detail=user not found")
+}
+
+func TestEcho_ProblemDetailsHTTPErrorHandler(t *testing.T) {
+	var testCases = []struct {
+		name              string
+		givenDebug        bool
+		whenErr           error
+		whenAccept        string
+		whenMethod        string
+		expectCode        int
+		expectBody        string
+		expectContentType string
+	}{
+		{
+			name:              "ok, plain error is internal server error",
+			whenErr:           errors.New("secret"),
+			expectCode:        http.StatusInternalServerError,
+			expectBody:        `{"status":500,"title":"Internal Server Error"}`,
+			expectContentType: MIMEApplicationProblemJSON,
+		},
+		{
+			name:              "ok, plain error with debug",
+			givenDebug:        true,
+			whenErr:           errors.New("secret"),
+			expectCode:        http.StatusInternalServerError,
+			expectBody:        `{"error":"secret","status":500,"title":"Internal Server Error"}`,
+			expectContentType: MIMEApplicationProblemJSON,
+		},
+		{
+			name:              "ok, HTTPError message is detail",
+			whenErr:           NewHTTPError(http.StatusConflict, "user exists"),
+			expectCode:        http.StatusConflict,
+			expectBody:        `{"detail":"user exists","status":409,"title":"Conflict"}`,
+			expectContentType: MIMEApplicationProblemJSON,
+		},
+		{
+			name:              "ok, HTTPError with default message has no detail",
+			whenErr:           ErrNotFound,
+			expectCode:        http.StatusNotFound,
+			expectBody:        `{"status":404,"title":"Not Found"}`,
+			expectContentType: MIMEApplicationProblemJSON,
+		},
+		{
+			name:              "ok, HTTPError with non string message",
+			whenErr:           NewHTTPError(http.StatusBadRequest, Map{"code": 1}),
+			expectCode:        http.StatusBadRequest,
+			expectBody:        `{"message":{"code":1},"status":400,"title":"Bad Request"}`,
+			expectContentType: MIMEApplicationProblemJSON,
+		},
+		{
+			name: "ok, HTTPError carrying problem details",
+			whenErr: NewHTTPError(http.StatusForbidden, &ProblemDetails{
+				Type:  "https://example.com/probs/out-of-credit",
+				Title: "You do not have enough credit.",
+			}),
+			expectCode:        http.StatusForbidden,
+			expectBody:        `{"status":403,"title":"You do not have enough credit.","type":"https://example.com/probs/out-of-credit"}`,
+			expectContentType: MIMEApplicationProblemJSON,
+		},
+		{
+			name:              "ok, problem details returned as error",
+			whenErr:           fmt.Errorf("wrapped: %w", NewProblemDetails(http.StatusTeapot)),
+			expectCode:        http.StatusTeapot,
+			expectBody:        `{"status":418,"title":"I'm a teapot"}`,
+			expectContentType: MIMEApplicationProblemJSON,
+		},
+		{
+			name:              "ok, binding error lists invalid params",
+			whenErr:           NewBindingError("id", []string{"x"}, "failed to bind field value to int64", nil),
+			expectCode:        http.StatusBadRequest,
+			expectBody:        `{"detail":"failed to bind field value to int64","invalid-params":[{"name":"id","reason":"failed to bind field value to int64"}],"status":400,"title":"Bad Request"}`,
+			expectContentType: MIMEApplicationProblemJSON,
+		},
+		{
+			name:              "ok, validation error lists invalid params",
+			whenErr:           &testValidationError{params: []InvalidParam{{Name: "email", Reason: "required"}}},
+			expectCode:        http.StatusBadRequest,
+			expectBody:        `{"invalid-params":[{"name":"email","reason":"required"}],"status":400,"title":"Bad Request"}`,
+			expectContentType: MIMEApplicationProblemJSON,
+		},
+		{
+			name:              "ok, validation error wrapped in HTTPError",
+			whenErr:           NewHTTPError(http.StatusUnprocessableEntity).SetInternal(&testValidationError{params: []InvalidParam{{Name: "email", Reason: "required"}}}),
+			expectCode:        http.StatusUnprocessableEntity,
+			expectBody:        `{"invalid-params":[{"name":"email","reason":"required"}],"status":422,"title":"Unprocessable Entity"}`,
+			expectContentType: MIMEApplicationProblemJSON,
+		},
+		{
+			name:              "ok, application/json accept gets problem+json",
+			whenErr:           ErrNotFound,
+			whenAccept:        MIMEApplicationJSON,
+			expectCode:        http.StatusNotFound,
+			expectBody:        `{"status":404,"title":"Not Found"}`,
+			expectContentType: MIMEApplicationProblemJSON,
+		},
+		{
+			name:              "ok, xml",
+			whenErr:           ErrNotFound,
+			whenAccept:        MIMEApplicationXML,
+			expectCode:        http.StatusNotFound,
+			expectBody:        xml.Header + `<problem xmlns="urn:ietf:rfc:7807"><status>404</status><title>Not Found</title></problem>`,
+			expectContentType: MIMEApplicationProblemXML,
+		},
+		{
+			name:       "ok, HEAD request has no body",
+			whenErr:    ErrNotFound,
+			whenMethod: http.MethodHead,
+			expectCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.Debug = tc.givenDebug
+			e.HTTPErrorHandler = e.ProblemDetailsHTTPErrorHandler
+
+			method := http.MethodGet
+			if tc.whenMethod != "" {
+				method = tc.whenMethod
+			}
+			req := httptest.NewRequest(method, "/", nil)
+			if tc.whenAccept != "" {
+				req.Header.Set(HeaderAccept, tc.whenAccept)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			e.HTTPErrorHandler(tc.whenErr, c)
+
+			assert.Equal(t, tc.expectCode, rec.Code)
+			assert.Equal(t, tc.expectContentType, rec.Header().Get(HeaderContentType))
+			if tc.expectContentType == MIMEApplicationProblemJSON {
+				assert.JSONEq(t, tc.expectBody, rec.Body.String())
+			} else {
+				assert.Equal(t, tc.expectBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestDefaultHTTPErrorHandler_ProblemDetails(t *testing.T) {
+	e := New()
+	e.GET("/error", func(c Context) error {
+		return NewProblemDetails(http.StatusConflict)
+	})
+	e.GET("/message", func(c Context) error {
+		return NewHTTPError(http.StatusForbidden, &ProblemDetails{Title: "No credit"})
+	})
+
+	code, body := request(http.MethodGet, "/error", e)
+	assert.Equal(t, http.StatusConflict, code)
+	assert.Equal(t, `{"status":409,"title":"Conflict"}`+"\n", body)
+
+	req := httptest.NewRequest(http.MethodGet, "/message", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Equal(t, MIMEApplicationProblemJSON, rec.Header().Get(HeaderContentType))
+	assert.Equal(t, `{"status":403,"title":"No credit"}`+"\n", rec.Body.String())
+}
@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RouteConflictKind is kind of conflict between routes detected when route is added.
+type RouteConflictKind string
+
+const (
+	// RouteConflictDuplicate is route with the same method and path as already registered route. Route added later
+	// replaces the earlier one.
+	RouteConflictDuplicate RouteConflictKind = "duplicate"
+	// RouteConflictParamName is route with path param at the same position as param of already registered route but
+	// with different name, i.e. `/users/:id` and `/users/:name/files`.
+	RouteConflictParamName RouteConflictKind = "param_name"
+	// RouteConflictUnreachable is route that can not be matched as it is registered, i.e. route with `*` that is not
+	// the last character of the path. Everything after `*` is matched by the wildcard.
+	RouteConflictUnreachable RouteConflictKind = "unreachable"
+)
+
+// RouteConflict describes conflict of route with routes registered before it.
+type RouteConflict struct {
+	Kind   RouteConflictKind `json:"kind"`
+	Host   string            `json:"host,omitempty"`
+	Method string            `json:"method"`
+	Path   string            `json:"path"`
+	// ConflictsWith is path of already registered route the route conflicts with. Empty for unreachable routes.
+	ConflictsWith string `json:"conflicts_with,omitempty"`
+	// Message is human-readable description of the conflict.
+	Message string `json:"message"`
+}
+
+// String returns human-readable description of the conflict.
+func (c RouteConflict) String() string {
+	host := ""
+	if c.Host != "" {
+		host = " (host " + c.Host + ")"
+	}
+	return fmt.Sprintf("%s %s%s: %s", c.Method, c.Path, host, c.Message)
+}
+
+// RouteConflictError is error returned by `Echo.AddE` and `Group.AddE` when route conflicts with registered routes.
+type RouteConflictError struct {
+	Conflicts []RouteConflict
+}
+
+// Error returns descriptions of all conflicts.
+func (e *RouteConflictError) Error() string {
+	msgs := make([]string, len(e.Conflicts))
+	for i, c := range e.Conflicts {
+		msgs[i] = c.String()
+	}
+	return "echo: route conflict: " + strings.Join(msgs, "; ")
+}
+
+// RouteConflictPolicy defines what Echo does when route added with `Echo.Add` (or `GET`, `Group.POST` etc.) conflicts
+// with registered routes. Routes are not checked for conflicts with RouteConflictIgnore policy. Conflicts of routes
+// registered with RouteConflictLog policy are recorded in `Echo.RouteConflicts` report.
+type RouteConflictPolicy uint8
+
+const (
+	// RouteConflictIgnore registers route without checking it for conflicts. Route with the same method and path
+	// replaces the earlier one.
+	RouteConflictIgnore RouteConflictPolicy = iota
+	// RouteConflictLog registers conflicting route and logs the conflict with `Echo.Logger` at warning level.
+	RouteConflictLog
+	// RouteConflictPanic panics with description of conflicts instead of registering conflicting route.
+	RouteConflictPanic
+)
+
+// RouteConflicts returns conflicts detected when routes were added to Echo instance routers. Use it to fail startup
+// checks, i.e. in CI, when route table contains conflicts. Routes are checked only when `Echo.RouteConflictPolicy` is
+// not RouteConflictIgnore. Conflicts of routes rejected by `AddE` are not included.
+func (e *Echo) RouteConflicts() []RouteConflict {
+	e.conflictsMu.Lock()
+	defer e.conflictsMu.Unlock()
+	return append([]RouteConflict(nil), e.routeConflicts...)
+}
+
+// checkRoute returns conflicts of route for method and path with routes registered to host router.
+func (e *Echo) checkRoute(host, method, path string) []RouteConflict {
+	conflicts := e.findRouter(host).conflicts(method, normalizePathSlash(path))
+	for i := range conflicts {
+		conflicts[i].Host = host
+	}
+	return conflicts
+}
+
+func (e *Echo) recordRouteConflicts(conflicts []RouteConflict) {
+	e.conflictsMu.Lock()
+	defer e.conflictsMu.Unlock()
+	e.routeConflicts = append(e.routeConflicts, conflicts...)
+}
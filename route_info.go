@@ -0,0 +1,180 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"encoding/json"
+	"maps"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// RouteInfo contains documentation, metadata and handler type information of a route. It is attached to route with
+// `Route.WithSummary`, `Route.WithMetadata` etc. and is read with `Route.Info`.
+type RouteInfo struct {
+	// RequestType is type of request value of handler registered with `HandleRoute`. Nil for other handlers.
+	RequestType reflect.Type `json:"-"`
+	// ResponseType is type of response value of handler registered with `HandleRoute`. Nil for other handlers.
+	ResponseType reflect.Type `json:"-"`
+
+	// Summary, Description, Tags and Responses document the route, i.e. for OpenAPI document generation.
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Responses   map[int]reflect.Type `json:"-"`
+
+	// Middleware contains names of route and group level middleware functions the handler is wrapped with.
+	Middleware []string `json:"middleware,omitempty"`
+	// Metadata contains values attached to the route with `WithMetadata`. There is at most one value of each type.
+	Metadata []interface{} `json:"metadata,omitempty"`
+}
+
+// routeInfos holds information of routes. It is kept outside of `Route`, so Route stays comparable and can be created
+// with unkeyed literals. Slices and maps of stored RouteInfo are replaced, never modified, as they are shared with
+// values returned by `Route.Info`.
+var routeInfos = struct {
+	sync.RWMutex
+	infos map[*Route]*RouteInfo
+}{infos: map[*Route]*RouteInfo{}}
+
+// Info returns documentation, metadata and handler type information of the route.
+func (r *Route) Info() RouteInfo {
+	if r == nil {
+		return RouteInfo{}
+	}
+	routeInfos.RLock()
+	defer routeInfos.RUnlock()
+	if info := routeInfos.infos[r]; info != nil {
+		return *info
+	}
+	return RouteInfo{}
+}
+
+func (r *Route) updateInfo(fn func(info *RouteInfo)) {
+	routeInfos.Lock()
+	defer routeInfos.Unlock()
+	info := routeInfos.infos[r]
+	if info == nil {
+		info = &RouteInfo{}
+		routeInfos.infos[r] = info
+	}
+	fn(info)
+}
+
+// releaseRouteInfo removes information of routes of from routing that are not routes of keep routing.
+func releaseRouteInfo(from *routing, keep *routing) {
+	kept := map[*Route]struct{}{}
+	keep.eachRouter(func(r *Router) {
+		for _, route := range r.routes {
+			kept[route] = struct{}{}
+		}
+	})
+
+	routeInfos.Lock()
+	defer routeInfos.Unlock()
+	from.eachRouter(func(r *Router) {
+		for _, route := range r.routes {
+			if _, ok := kept[route]; !ok {
+				delete(routeInfos.infos, route)
+			}
+		}
+	})
+}
+
+// MarshalJSON encodes route together with its information.
+func (r *Route) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Method string `json:"method"`
+		Path   string `json:"path"`
+		Name   string `json:"name"`
+		RouteInfo
+	}{Method: r.Method, Path: r.Path, Name: r.Name, RouteInfo: r.Info()})
+}
+
+// WithSummary sets short summary of what the route does.
+func (r *Route) WithSummary(summary string) *Route {
+	r.updateInfo(func(info *RouteInfo) {
+		info.Summary = summary
+	})
+	return r
+}
+
+// WithDescription sets verbose explanation of the route behaviour.
+func (r *Route) WithDescription(description string) *Route {
+	r.updateInfo(func(info *RouteInfo) {
+		info.Description = description
+	})
+	return r
+}
+
+// WithTags adds tags that are used to group routes in documentation.
+func (r *Route) WithTags(tags ...string) *Route {
+	r.updateInfo(func(info *RouteInfo) {
+		info.Tags = append(slices.Clip(info.Tags), tags...)
+	})
+	return r
+}
+
+// WithResponse documents response with status code and body of the type of given value. Body is nil for responses
+// without body.
+//
+// Example: `e.GET("/users/:id", getUser).WithResponse(http.StatusOK, User{}).WithResponse(http.StatusNotFound, nil)`
+func (r *Route) WithResponse(code int, body interface{}) *Route {
+	r.updateInfo(func(info *RouteInfo) {
+		responses := maps.Clone(info.Responses)
+		if responses == nil {
+			responses = map[int]reflect.Type{}
+		}
+		responses[code] = reflect.TypeOf(body)
+		info.Responses = responses
+	})
+	return r
+}
+
+// WithMetadata attaches values to the route. Values are identified by their type so value replaces previously attached
+// value of the same type. Metadata of matched route is available to handlers and middlewares with
+// `RouteMetadata[T](c.Route())`.
+//
+// Example:
+//
+//	type RequiredScopes []string
+//
+//	e.DELETE("/users/:id", deleteUser).WithMetadata(RequiredScopes{"users:write"})
+func (r *Route) WithMetadata(values ...interface{}) *Route {
+	r.updateInfo(func(info *RouteInfo) {
+		metadata := slices.Clone(info.Metadata)
+		for _, v := range values {
+			if v == nil {
+				continue
+			}
+			t := reflect.TypeOf(v)
+			replaced := false
+			for i, existing := range metadata {
+				if reflect.TypeOf(existing) == t {
+					metadata[i] = v
+					replaced = true
+					break
+				}
+			}
+			if !replaced {
+				metadata = append(metadata, v)
+			}
+		}
+		info.Metadata = metadata
+	})
+	return r
+}
+
+// RouteMetadata returns metadata value of type T attached to the route. When T is an interface type the first value
+// implementing it is returned. Returns false when route is nil or has no value of type T.
+func RouteMetadata[T any](r *Route) (T, bool) {
+	for _, v := range r.Info().Metadata {
+		if tv, ok := v.(T); ok {
+			return tv, true
+		}
+	}
+	var zero T
+	return zero, false
+}
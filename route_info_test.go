@@ -0,0 +1,202 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testRouteOwner string
+
+type testRouteScopes []string
+
+type testRouteDescriber interface {
+	describe() string
+}
+
+func (o testRouteOwner) describe() string {
+	return "owned by " + string(o)
+}
+
+func testRouteMiddleware(next HandlerFunc) HandlerFunc {
+	return next
+}
+
+func testRouteHandler(c Context) error {
+	return nil
+}
+
+func TestRoute_WithMetadata(t *testing.T) {
+	route := &Route{}
+
+	route.WithMetadata(testRouteOwner("team-a"), testRouteScopes{"users:read"}, nil)
+	route.WithMetadata(testRouteOwner("team-b"))
+
+	assert.Equal(t, []interface{}{testRouteOwner("team-b"), testRouteScopes{"users:read"}}, route.Info().Metadata)
+}
+
+func TestRouteMetadata(t *testing.T) {
+	route := (&Route{}).WithMetadata(testRouteOwner("team-a"))
+
+	owner, ok := RouteMetadata[testRouteOwner](route)
+	assert.True(t, ok)
+	assert.Equal(t, testRouteOwner("team-a"), owner)
+
+	describer, ok := RouteMetadata[testRouteDescriber](route)
+	assert.True(t, ok)
+	assert.Equal(t, "owned by team-a", describer.describe())
+
+	scopes, ok := RouteMetadata[testRouteScopes](route)
+	assert.False(t, ok)
+	assert.Nil(t, scopes)
+
+	_, ok = RouteMetadata[testRouteOwner](nil)
+	assert.False(t, ok)
+}
+
+func TestEcho_RouteMetadataInContext(t *testing.T) {
+	e := New()
+
+	var fromMiddleware, fromHandler []string
+	var notFoundRoute *Route
+	e.Use(func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			scopes, _ := RouteMetadata[testRouteScopes](c.Route())
+			fromMiddleware = scopes
+			if c.Route() == nil {
+				notFoundRoute = &Route{}
+			}
+			return next(c)
+		}
+	})
+	e.GET("/users/:id", func(c Context) error {
+		scopes, _ := RouteMetadata[testRouteScopes](c.Route())
+		fromHandler = scopes
+		return c.String(http.StatusOK, c.Route().Path)
+	}).WithMetadata(testRouteScopes{"users:read"})
+
+	code, body := request(http.MethodGet, "/users/1", e)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "/users/:id", body)
+	assert.Equal(t, []string{"users:read"}, fromMiddleware)
+	assert.Equal(t, []string{"users:read"}, fromHandler)
+	assert.Nil(t, notFoundRoute)
+
+	code, _ = request(http.MethodGet, "/not-found", e)
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.NotNil(t, notFoundRoute)
+}
+
+func TestContext_RouteForRouteNotFound(t *testing.T) {
+	e := New()
+	var route *Route
+	e.Use(func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			route = c.Route()
+			return next(c)
+		}
+	})
+	e.GET("/status", testRouteHandler)
+	notFound := e.RouteNotFound("/api/*", func(c Context) error {
+		return c.NoContent(http.StatusNotFound)
+	})
+
+	code, _ := request(http.MethodGet, "/api/users", e)
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Equal(t, notFound, route)
+
+	code, _ = request(http.MethodPost, "/status", e)
+	assert.Equal(t, http.StatusMethodNotAllowed, code)
+	assert.Nil(t, route)
+}
+
+func TestEcho_RoutesMiddlewareAndMetadata(t *testing.T) {
+	e := New()
+	g := e.Group("/api", testRouteMiddleware)
+	g.GET("/users", testRouteHandler, testRouteMiddleware).WithMetadata(testRouteOwner("team-a"))
+
+	var route *Route
+	for _, r := range e.Routes() {
+		if r.Method == http.MethodGet && r.Path == "/api/users" {
+			route = r
+		}
+	}
+	if !assert.NotNil(t, route) {
+		return
+	}
+	assert.Equal(t, []string{
+		"github.com/labstack/echo/v4.testRouteMiddleware",
+		"github.com/labstack/echo/v4.testRouteMiddleware",
+	}, route.Info().Middleware)
+	assert.Equal(t, []interface{}{testRouteOwner("team-a")}, route.Info().Metadata)
+
+	b, err := json.Marshal(route)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"method": "GET",
+		"path": "/api/users",
+		"name": "github.com/labstack/echo/v4.testRouteHandler",
+		"middleware": ["github.com/labstack/echo/v4.testRouteMiddleware", "github.com/labstack/echo/v4.testRouteMiddleware"],
+		"metadata": ["team-a"]
+	}`, string(b))
+}
+
+func TestRoute_Comparable(t *testing.T) {
+	e := New()
+	route := e.GET("/users/:id", testRouteHandler).
+		WithSummary("Get user").
+		WithTags("users").
+		WithResponse(http.StatusOK, "").
+		WithMetadata(testRouteOwner("team-a"))
+
+	assert.True(t, *route == Route{http.MethodGet, "/users/:id", "github.com/labstack/echo/v4.testRouteHandler"})
+	assert.Equal(t, RouteInfo{
+		Summary:   "Get user",
+		Tags:      []string{"users"},
+		Responses: map[int]reflect.Type{http.StatusOK: reflect.TypeOf("")},
+		Metadata:  []interface{}{testRouteOwner("team-a")},
+	}, route.Info())
+
+	assert.Equal(t, RouteInfo{}, (&Route{http.MethodGet, "/users/:id", ""}).Info())
+	assert.Equal(t, RouteInfo{}, (*Route)(nil).Info())
+}
+
+func TestRoute_InfoIsNotChangedByLaterUpdates(t *testing.T) {
+	route := (&Route{}).WithTags("a").WithMetadata(testRouteOwner("team-a"))
+	info := route.Info()
+
+	route.WithTags("b").WithMetadata(testRouteOwner("team-b")).WithResponse(http.StatusOK, nil)
+
+	assert.Equal(t, []string{"a"}, info.Tags)
+	assert.Equal(t, []interface{}{testRouteOwner("team-a")}, info.Metadata)
+	assert.Nil(t, info.Responses)
+}
+
+func TestEcho_UpdateRoutesReleasesRouteInfo(t *testing.T) {
+	e := New()
+	kept := e.GET("/kept", testRouteHandler).WithSummary("kept")
+	removed := e.GET("/removed", testRouteHandler).WithSummary("removed")
+
+	var discarded *Route
+	err := e.UpdateRoutes(func() error {
+		discarded = e.GET("/discarded", testRouteHandler).WithSummary("discarded")
+		return errors.New("discard")
+	})
+	assert.EqualError(t, err, "discard")
+	assert.Equal(t, "", discarded.Info().Summary)
+
+	err = e.UpdateRoutes(func() error {
+		e.RemoveRoute(http.MethodGet, "/removed")
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "kept", kept.Info().Summary)
+	assert.Equal(t, "", removed.Info().Summary)
+}
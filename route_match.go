@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"net/http"
+	"strings"
+)
+
+// RouteMatch is result of matching method and path against routes without executing handlers.
+type RouteMatch struct {
+	// Route is matched route. When no route matches method and path it is route registered with `RouteNotFound` for
+	// the path, if any. Nil for routes added directly with `Router.Add`.
+	Route *Route
+	// Path is path of matched route, i.e. `/users/:id`.
+	Path string
+	// ParamNames and ParamValues are path params of matched route.
+	ParamNames  []string
+	ParamValues []string
+	// HostParamNames and HostParamValues are params of matched host pattern, see `Echo.Host`.
+	HostParamNames  []string
+	HostParamValues []string
+	// AllowedMethods are methods that routes with matched path are registered for, including OPTIONS that Echo
+	// handles for every path. Empty when path does not match any route.
+	AllowedMethods []string
+	// NotFound is true when no route matches the path. Route is set when there is `RouteNotFound` route for the path.
+	NotFound bool
+	// MethodNotAllowed is true when routes with matched path are not registered for the method. For OPTIONS requests
+	// Echo responds with Allow header instead of 405 Method Not Allowed.
+	MethodNotAllowed bool
+}
+
+// Param returns value of path param by name.
+func (m RouteMatch) Param(name string) string {
+	for i, n := range m.ParamNames {
+		if n == name && i < len(m.ParamValues) {
+			return m.ParamValues[i]
+		}
+	}
+	return ""
+}
+
+// HostParam returns value of host pattern param by name.
+func (m RouteMatch) HostParam(name string) string {
+	for i, n := range m.HostParamNames {
+		if n == name && i < len(m.HostParamValues) {
+			return m.HostParamValues[i]
+		}
+	}
+	return ""
+}
+
+// Match returns route that method and path match without executing handlers. Match does not modify the router and is
+// safe for concurrent use with requests and other matches. Routes must not be added to the router at the same time.
+func (r *Router) Match(method, path string) RouteMatch {
+	return r.match(method, path, &context{echo: r.echo, pvalues: make([]string, r.maxParam)})
+}
+
+func (r *Router) match(method, path string, ctx *context) RouteMatch {
+	n, matched := r.find(method, path, ctx)
+	m := RouteMatch{
+		Route:           ctx.route,
+		Path:            ctx.path,
+		ParamNames:      ctx.pnames,
+		HostParamNames:  ctx.hostParamNames,
+		HostParamValues: ctx.hostParamValues,
+	}
+	if len(ctx.pnames) > 0 {
+		m.ParamValues = append([]string(nil), ctx.pvalues[:len(ctx.pnames)]...)
+	}
+	if n != nil && n.isHandler {
+		m.AllowedMethods = strings.Split(n.methods.allowHeader, ", ")
+	}
+	if !matched {
+		m.MethodNotAllowed = n != nil && n.notFoundHandler == nil && n.isHandler
+		m.NotFound = !m.MethodNotAllowed
+	}
+	return m
+}
+
+// MatchRequest returns route that request would be routed to without executing handlers or middleware. Router is
+// chosen by request host like in ServeHTTP. Safe for concurrent use, also while routes are changed with UpdateRoutes.
+// Note that pre-middleware (`Echo.Pre`) can change request before routing, i.e. rewrite path, and is not executed.
+func (e *Echo) MatchRequest(r *http.Request) RouteMatch {
+	rt := e.routing.Load()
+	ctx := &context{echo: e}
+	router := rt.hostRouter(r.Host, ctx)
+	ctx.pvalues = make([]string, router.maxParam)
+	return router.match(r.Method, GetPath(r), ctx)
+}
@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter_Match(t *testing.T) {
+	e := New()
+	e.GET("/users/:id", handlerFunc)
+	e.PUT("/users/:id", handlerFunc)
+	e.GET("/files/:name/*", handlerFunc)
+	e.RouteNotFound("/files/*", handlerFunc)
+
+	var testCases = []struct {
+		name       string
+		whenMethod string
+		whenPath   string
+		expect     RouteMatch
+	}{
+		{
+			name:       "matched",
+			whenMethod: http.MethodGet,
+			whenPath:   "/users/1",
+			expect: RouteMatch{
+				Path:           "/users/:id",
+				ParamNames:     []string{"id"},
+				ParamValues:    []string{"1"},
+				AllowedMethods: []string{http.MethodOptions, http.MethodGet, http.MethodPut},
+			},
+		},
+		{
+			name:       "method not allowed",
+			whenMethod: http.MethodDelete,
+			whenPath:   "/users/1",
+			expect: RouteMatch{
+				Path:             "/users/:id",
+				AllowedMethods:   []string{http.MethodOptions, http.MethodGet, http.MethodPut},
+				MethodNotAllowed: true,
+			},
+		},
+		{
+			name:       "any param",
+			whenMethod: http.MethodGet,
+			whenPath:   "/files/a/b/c.txt",
+			expect: RouteMatch{
+				Path:           "/files/:name/*",
+				ParamNames:     []string{"name", "*"},
+				ParamValues:    []string{"a", "b/c.txt"},
+				AllowedMethods: []string{http.MethodOptions, http.MethodGet},
+			},
+		},
+		{
+			name:       "route not found route",
+			whenMethod: http.MethodGet,
+			whenPath:   "/files/",
+			expect: RouteMatch{
+				Path:        "/files/*",
+				ParamNames:  []string{"*"},
+				ParamValues: []string{""},
+				NotFound:    true,
+			},
+		},
+		{
+			name:       "not found",
+			whenMethod: http.MethodGet,
+			whenPath:   "/teams",
+			expect: RouteMatch{
+				NotFound: true,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := e.Router().Match(tc.whenMethod, tc.whenPath)
+
+			if tc.expect.Path != "" && !tc.expect.MethodNotAllowed {
+				assert.NotNil(t, m.Route)
+				assert.Equal(t, tc.expect.Path, m.Route.Path)
+			} else {
+				assert.Nil(t, m.Route)
+			}
+			m.Route = nil
+			assert.Equal(t, tc.expect, m)
+		})
+	}
+}
+
+func TestRouteMatch_Param(t *testing.T) {
+	m := RouteMatch{
+		ParamNames:      []string{"id", "*"},
+		ParamValues:     []string{"1", "a/b"},
+		HostParamNames:  []string{"tenant"},
+		HostParamValues: []string{"acme"},
+	}
+	assert.Equal(t, "1", m.Param("id"))
+	assert.Equal(t, "a/b", m.Param("*"))
+	assert.Equal(t, "", m.Param("missing"))
+	assert.Equal(t, "acme", m.HostParam("tenant"))
+	assert.Equal(t, "", m.HostParam("missing"))
+}
+
+func TestEcho_MatchRequest(t *testing.T) {
+	e := New()
+	e.GET("/users/:id", handlerFunc)
+	e.Host("{tenant}.example.com").GET("/users/:id/:tab", handlerFunc)
+
+	m := e.MatchRequest(httptest.NewRequest(http.MethodGet, "http://acme.example.com/users/1/files", nil))
+	assert.Equal(t, "/users/:id/:tab", m.Route.Path)
+	assert.Equal(t, "files", m.Param("tab"))
+	assert.Equal(t, "acme", m.HostParam("tenant"))
+	assert.False(t, m.NotFound)
+
+	m = e.MatchRequest(httptest.NewRequest(http.MethodGet, "http://localhost/teams/1", nil))
+	assert.True(t, m.NotFound)
+	assert.Nil(t, m.Route)
+
+	m = e.MatchRequest(httptest.NewRequest(http.MethodGet, "http://localhost/users/1", nil))
+	assert.Equal(t, "/users/:id", m.Route.Path)
+	assert.Equal(t, "1", m.Param("id"))
+}
+
+func TestEcho_MatchRequestConcurrentUpdates(t *testing.T) {
+	e := New()
+	e.GET("/users/:id", handlerFunc)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				m := e.MatchRequest(httptest.NewRequest(http.MethodGet, "/users/1", nil))
+				assert.Equal(t, "1", m.Param("id"))
+			}
+		}()
+	}
+	for i := 0; i < 20; i++ {
+		err := e.UpdateRoutes(func() error {
+			e.GET("/teams/:team/users/:id", handlerFunc)
+			return nil
+		})
+		assert.NoError(t, err)
+	}
+	wg.Wait()
+}
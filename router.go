@@ -7,6 +7,8 @@ import (
 	"bytes"
 	"fmt"
 	"net/http"
+	"slices"
+	"strings"
 )
 
 // Router is the registry of all registered routes for an `Echo` instance for
@@ -15,13 +17,30 @@ type Router struct {
 	tree   *node
 	routes map[string]*Route
 	echo   *Echo
+	// mounts are Echo instances mounted under path prefix.
+	mounts []mount
+	// maxParam is maximum number of path params of routes in the tree.
+	maxParam int
+}
+
+// mount is Echo instance mounted under path prefix with `Echo.Mount`.
+type mount struct {
+	prefix string
+	echo   *Echo
+	// route is `prefix/*` route that passes requests to the mounted instance. Mount is removed with the route.
+	route *Route
 }
 
 type node struct {
-	methods    *routeMethods
-	parent     *node
-	paramChild *node
-	anyChild   *node
+	methods *routeMethods
+	parent  *node
+	// paramChildren are param nodes ordered by priority. Nodes with constraints are checked before node without one.
+	paramChildren children
+	anyChild      *node
+	// constraint is constraint of param node value. Nil when any value matches.
+	constraint *paramConstraint
+	// paramName is name of the param given by route that added param node.
+	paramName string
 	// notFoundHandler is handler registered with RouteNotFound method and is executed for 404 cases
 	notFoundHandler *routeMethod
 	prefix          string
@@ -41,8 +60,11 @@ type children []*node
 
 type routeMethod struct {
 	handler HandlerFunc
-	ppath   string
-	pnames  []string
+	route   *Route
+	// group is group the route was added with. Nil for routes added with Echo.
+	group  *Group
+	ppath  string
+	pnames []string
 }
 
 type routeMethods struct {
@@ -86,6 +108,22 @@ func (m *routeMethods) isHandler() bool {
 	// RouteNotFound/404 is not considered as a handler
 }
 
+// group returns group of first route of methods that was added with a group.
+func (m *routeMethods) group() *Group {
+	for _, rm := range []*routeMethod{m.connect, m.delete, m.get, m.head, m.options, m.patch, m.post, m.propfind,
+		m.put, m.trace, m.report} {
+		if rm != nil && rm.group != nil {
+			return rm.group
+		}
+	}
+	for _, rm := range m.anyOther {
+		if rm.group != nil {
+			return rm.group
+		}
+	}
+	return nil
+}
+
 func (m *routeMethods) updateAllowHeader() {
 	buf := new(bytes.Buffer)
 	buf.WriteString(http.MethodOptions)
@@ -146,42 +184,143 @@ func NewRouter(e *Echo) *Router {
 	}
 }
 
-// Routes returns the registered routes.
+// Routes returns the registered routes. Routes of mounted Echo instances are returned as copies with path that
+// includes mount prefix.
 func (r *Router) Routes() []*Route {
 	routes := make([]*Route, 0, len(r.routes))
 	for _, v := range r.routes {
 		routes = append(routes, v)
 	}
+	for _, m := range r.mounts {
+		for _, v := range m.echo.Routes() {
+			route := *v
+			route.Path = m.prefix + v.Path
+			routes = append(routes, &route)
+		}
+	}
 	return routes
 }
 
-// Reverse generates a URL from route name and provided parameters.
+// Reverse generates a URL from route name and provided parameters. Routes of mounted Echo instances are checked when
+// router has no route with the name.
 func (r *Router) Reverse(name string, params ...interface{}) string {
+	for _, route := range r.routes {
+		if route.Name == name {
+			uri, _ := reversePath(route.Path, params)
+			return uri
+		}
+	}
+	for _, m := range r.mounts {
+		// params of mount prefix are substituted first, remaining params are for route of mounted instance
+		prefix, n := reversePath(m.prefix, params)
+		if u := m.echo.Reverse(name, params[n:]...); u != "" {
+			return prefix + u
+		}
+	}
+	return ""
+}
+
+// reversePath replaces params and wildcard of route path with given params. Returns number of params used.
+func reversePath(path string, params []interface{}) (string, int) {
 	uri := new(bytes.Buffer)
 	ln := len(params)
 	n := 0
-	for _, route := range r.routes {
-		if route.Name == name {
-			for i, l := 0, len(route.Path); i < l; i++ {
-				hasBackslash := route.Path[i] == '\\'
-				if hasBackslash && i+1 < l && route.Path[i+1] == ':' {
-					i++ // backslash before colon escapes that colon. in that case skip backslash
-				}
-				if n < ln && (route.Path[i] == '*' || (!hasBackslash && route.Path[i] == ':')) {
-					// in case of `*` wildcard or `:` (unescaped colon) param we replace everything till next slash or end of path
-					for ; i < l && route.Path[i] != '/'; i++ {
-					}
-					uri.WriteString(fmt.Sprintf("%v", params[n]))
-					n++
-				}
-				if i < l {
-					uri.WriteByte(route.Path[i])
-				}
+	for i, l := 0, len(path); i < l; i++ {
+		hasBackslash := path[i] == '\\'
+		if hasBackslash && i+1 < l && path[i+1] == ':' {
+			i++ // backslash before colon escapes that colon. in that case skip backslash
+		}
+		if n < ln && (path[i] == '*' || (!hasBackslash && path[i] == ':')) {
+			// in case of `*` wildcard or `:` (unescaped colon) param we replace everything till next slash or end of path
+			if path[i] == ':' {
+				_, i = paramEnd(path, i+1) // constraint may contain slashes
 			}
-			break
+			for ; i < l && path[i] != '/'; i++ {
+			}
+			uri.WriteString(fmt.Sprintf("%v", params[n]))
+			n++
+		}
+		if i < l {
+			uri.WriteByte(path[i])
+		}
+	}
+	return uri.String(), n
+}
+
+// Remove removes route registered for method and path. Returns false when there is no such route.
+func (r *Router) Remove(method, path string) bool {
+	path = normalizePathSlash(path)
+	delete(r.routes, method+path)
+	r.pruneMounts()
+	return r.removeMatching(method, func(rm *routeMethod) bool {
+		return rm.ppath == path
+	})
+}
+
+// removeRoute removes route unless it has been replaced by route added later for the same method and path.
+func (r *Router) removeRoute(route *Route) bool {
+	key := route.Method + route.Path
+	if r.routes[key] != route {
+		return false
+	}
+	delete(r.routes, key)
+	r.pruneMounts()
+	return r.removeMatching(route.Method, func(rm *routeMethod) bool {
+		return rm.route == route
+	})
+}
+
+// pruneMounts removes mounts which routes have been removed.
+func (r *Router) pruneMounts() {
+	r.mounts = slices.DeleteFunc(r.mounts, func(m mount) bool {
+		return r.routes[m.route.Method+m.route.Path] != m.route
+	})
+}
+
+func (r *Router) removeMatching(method string, match func(rm *routeMethod) bool) bool {
+	var matched []*node
+	var walk func(n *node)
+	walk = func(n *node) {
+		rm := n.notFoundHandler
+		if method != RouteNotFound {
+			rm = n.findMethod(method)
+		}
+		if rm != nil && match(rm) {
+			matched = append(matched, n)
+		}
+		for _, c := range n.staticChildren {
+			walk(c)
 		}
+		for _, c := range n.paramChildren {
+			walk(c)
+		}
+		if n.anyChild != nil {
+			walk(n.anyChild)
+		}
+	}
+	walk(r.tree)
+
+	for _, n := range matched {
+		n.removeMethod(method)
+		n.prune()
+	}
+	return len(matched) > 0
+}
+
+// clone returns deep copy of router tree so routes can be added and removed without affecting router that is used
+// to serve requests. Routes and handlers are shared.
+func (r *Router) clone() *Router {
+	routes := make(map[string]*Route, len(r.routes))
+	for k, v := range r.routes {
+		routes[k] = v
+	}
+	return &Router{
+		tree:     r.tree.clone(nil),
+		routes:   routes,
+		echo:     r.echo,
+		mounts:   append([]mount(nil), r.mounts...),
+		maxParam: r.maxParam,
 	}
-	return uri.String()
 }
 
 func normalizePathSlash(path string) string {
@@ -194,79 +333,202 @@ func normalizePathSlash(path string) string {
 }
 
 func (r *Router) add(method, path, name string, h HandlerFunc) *Route {
-	path = normalizePathSlash(path)
-	r.insert(method, path, h)
+	return r.addRoute(method, path, name, nil, h)
+}
 
+// addRoute adds route with handler. g is group the route is added with, nil for routes added with Echo.
+func (r *Router) addRoute(method, path, name string, g *Group, h HandlerFunc) *Route {
+	path = normalizePathSlash(path)
 	route := &Route{
 		Method: method,
 		Path:   path,
 		Name:   name,
 	}
+	r.insert(method, path, routeMethod{handler: h, route: route, group: g})
+
 	r.routes[method+path] = route
 	return route
 }
 
 // Add registers a new route for method and path with matching handler.
 func (r *Router) Add(method, path string, h HandlerFunc) {
-	r.insert(method, normalizePathSlash(path), h)
+	r.insert(method, normalizePathSlash(path), routeMethod{handler: h})
 }
 
-func (r *Router) insert(method, path string, h HandlerFunc) {
+// insert adds rm to the tree for method and path. Path and param names of rm are set from path.
+func (r *Router) insert(method, path string, rm routeMethod) {
 	path = normalizePathSlash(path)
-	pnames := []string{} // Param names
-	ppath := path        // Pristine path
+	ppath := path // Pristine path
 
-	if h == nil && r.echo.Logger != nil {
+	if rm.handler == nil && r.echo.Logger != nil {
 		// FIXME: in future we should return error
 		r.echo.Logger.Error(fmt.Sprintf("Adding route without handler function: %v:%v", method, path))
 	}
 
-	for i, lcpIndex := 0, len(path); i < lcpIndex; i++ {
+	path, params := r.parseRoutePath(ppath)
+	pnames := []string{} // Param names
+	for _, p := range params {
+		pnames = append(pnames, p.name)
+	}
+	rm.ppath, rm.pnames = ppath, pnames
+
+	for i, p := range params {
+		r.insertNode(method, path[:p.index], staticKind, routeMethod{}, params[:i])
+		if p.kind == anyKind {
+			anyRM := rm
+			anyRM.pnames = pnames[:i+1]
+			r.insertNode(method, path[:p.index+1], p.kind, anyRM, params[:i+1])
+		} else if p.index+1 == len(path) {
+			// path node is last fragment of route path. ie. `/users/:id`
+			r.insertNode(method, path[:p.index+1], p.kind, rm, params[:i+1])
+		} else {
+			r.insertNode(method, path[:p.index+1], p.kind, routeMethod{}, params[:i+1])
+		}
+	}
+
+	r.insertNode(method, path, staticKind, rm, params)
+}
+
+// routeParam is path param of route path.
+type routeParam struct {
+	name string
+	// constraint of the param value. Nil for `*` params and params without constraint.
+	constraint *paramConstraint
+	// index is position of the param in path returned by parseRoutePath.
+	index int
+	kind  kind
+}
+
+// parseRoutePath returns path for router tree, where `:name<constraint>` params are replaced with `:` and escaped
+// colons are unescaped, and params of the path.
+func (r *Router) parseRoutePath(path string) (string, []routeParam) {
+	ppath := path
+	var params []routeParam
+	for i, l := 0, len(path); i < l; i++ {
 		if path[i] == ':' {
 			if i > 0 && path[i-1] == '\\' {
 				path = path[:i-1] + path[i:]
 				i--
-				lcpIndex--
+				l--
 				continue
 			}
 			j := i + 1
+			nameEnd, end := paramEnd(path, j)
+			params = append(params, routeParam{
+				name:       path[j:nameEnd],
+				constraint: r.paramConstraint(ppath, path[nameEnd:end]),
+				index:      i,
+				kind:       paramKind,
+			})
+			path = path[:j] + path[end:]
+			i, l = j, len(path)
+		} else if path[i] == '*' {
+			params = append(params, routeParam{name: "*", index: i, kind: anyKind})
+		}
+	}
+	return path, params
+}
 
-			r.insertNode(method, path[:i], staticKind, routeMethod{})
-			for ; i < lcpIndex && path[i] != '/'; i++ {
-			}
+// conflicts returns conflicts of route for method and path with routes already in the tree. Tree is not modified.
+func (r *Router) conflicts(method, path string) []RouteConflict {
+	var result []RouteConflict
+	conflict := func(kind RouteConflictKind, with, format string, args ...interface{}) {
+		result = append(result, RouteConflict{
+			Kind:          kind,
+			Method:        method,
+			Path:          path,
+			ConflictsWith: with,
+			Message:       fmt.Sprintf(format, args...),
+		})
+	}
 
-			pnames = append(pnames, path[j:i])
-			path = path[:j] + path[i:]
-			i, lcpIndex = j, len(path)
+	if i := strings.IndexByte(path, '*'); i != -1 && i != len(path)-1 {
+		conflict(RouteConflictUnreachable, "", "path after `*` at position %d is never matched", i)
+	}
 
-			if i == lcpIndex {
-				// path node is last fragment of route path. ie. `/users/:id`
-				r.insertNode(method, path[:i], paramKind, routeMethod{ppath: ppath, pnames: pnames, handler: h})
-			} else {
-				r.insertNode(method, path[:i], paramKind, routeMethod{})
+	tpath, params := r.parseRoutePath(path)
+	currentNode := r.tree
+	search := tpath
+	paramIndex := 0
+	for {
+		prefix := currentNode.prefix
+		if !strings.HasPrefix(search, prefix) {
+			return result
+		}
+		search = search[len(prefix):]
+		if search == "" {
+			break
+		}
+		var constraint *paramConstraint
+		if paramIndex < len(params) {
+			constraint = params[paramIndex].constraint
+		}
+		c := currentNode.findChildWithLabel(search[0], constraint)
+		if c == nil {
+			return result
+		}
+		if c.kind != staticKind {
+			// escaped colon (`\:`) of static path segment matches param node label, so the param name is compared only
+			// when route has param at the position.
+			pos := len(tpath) - len(search)
+			if c.kind == paramKind && paramIndex < len(params) && params[paramIndex].index == pos &&
+				c.paramName != params[paramIndex].name {
+				conflict(RouteConflictParamName, c.routePath(), "path param %q is named %q in already registered route",
+					params[paramIndex].name, c.paramName)
 			}
-		} else if path[i] == '*' {
-			r.insertNode(method, path[:i], staticKind, routeMethod{})
-			pnames = append(pnames, "*")
-			r.insertNode(method, path[:i+1], anyKind, routeMethod{ppath: ppath, pnames: pnames, handler: h})
+			paramIndex++
 		}
+		currentNode = c
+	}
+
+	if method == RouteNotFound {
+		return result // RouteNotFound routes are added for the same path by groups and are expected to be replaced
 	}
+	if rm := currentNode.findMethod(method); rm != nil && rm.handler != nil {
+		conflict(RouteConflictDuplicate, rm.ppath, "route replaces already registered route %s %s", method, rm.ppath)
+	}
+	return result
+}
 
-	r.insertNode(method, path, staticKind, routeMethod{ppath: ppath, pnames: pnames, handler: h})
+// paramConstraint parses `<constraint>` part of path param of route path. Returns nil for param without constraint.
+// Panics when constraint is not valid.
+func (r *Router) paramConstraint(routePath, constraint string) *paramConstraint {
+	if constraint == "" {
+		return nil
+	}
+	if constraint[len(constraint)-1] != '>' {
+		panic(fmt.Sprintf("echo: unterminated constraint of path parameter in route path %q", routePath))
+	}
+	c, err := r.echo.ParamConstraints.compile(constraint[1 : len(constraint)-1])
+	if err != nil {
+		panic(fmt.Sprintf("echo: invalid constraint of path parameter in route path %q: %v", routePath, err))
+	}
+	return c
 }
 
-func (r *Router) insertNode(method, path string, t kind, rm routeMethod) {
+// insertNode inserts node for path into tree. params contains params of path in order they appear in path.
+func (r *Router) insertNode(method, path string, t kind, rm routeMethod, params []routeParam) {
 	// Adjust max param
 	paramLen := len(rm.pnames)
 	if *r.echo.maxParam < paramLen {
 		*r.echo.maxParam = paramLen
 	}
+	if r.maxParam < paramLen {
+		r.maxParam = paramLen
+	}
 
 	currentNode := r.tree // Current node as root
 	if currentNode == nil {
 		panic("echo: invalid method")
 	}
 	search := path
+	paramIndex := 0 // index of param for next param or any node on path
+	paramAt := func(i int) routeParam {
+		if i < len(params) {
+			return params[i]
+		}
+		return routeParam{}
+	}
 
 	for {
 		searchLen := len(search)
@@ -291,7 +553,7 @@ func (r *Router) insertNode(method, path string, t kind, rm routeMethod) {
 				currentNode.paramsCount = len(rm.pnames)
 				currentNode.originalPath = rm.ppath
 			}
-			currentNode.isLeaf = currentNode.staticChildren == nil && currentNode.paramChild == nil && currentNode.anyChild == nil
+			currentNode.isLeaf = currentNode.staticChildren == nil && currentNode.paramChildren == nil && currentNode.anyChild == nil
 		} else if lcpLen < prefixLen {
 			// Split node into two before we insert new node.
 			// This happens when we are inserting path that is submatch of any existing inserted paths.
@@ -307,7 +569,7 @@ func (r *Router) insertNode(method, path string, t kind, rm routeMethod) {
 				currentNode.originalPath,
 				currentNode.methods,
 				currentNode.paramsCount,
-				currentNode.paramChild,
+				currentNode.paramChildren,
 				currentNode.anyChild,
 				currentNode.notFoundHandler,
 			)
@@ -315,8 +577,8 @@ func (r *Router) insertNode(method, path string, t kind, rm routeMethod) {
 			for _, child := range currentNode.staticChildren {
 				child.parent = n
 			}
-			if currentNode.paramChild != nil {
-				currentNode.paramChild.parent = n
+			for _, child := range currentNode.paramChildren {
+				child.parent = n
 			}
 			if currentNode.anyChild != nil {
 				currentNode.anyChild.parent = n
@@ -330,7 +592,7 @@ func (r *Router) insertNode(method, path string, t kind, rm routeMethod) {
 			currentNode.originalPath = ""
 			currentNode.methods = new(routeMethods)
 			currentNode.paramsCount = 0
-			currentNode.paramChild = nil
+			currentNode.paramChildren = nil
 			currentNode.anyChild = nil
 			currentNode.isLeaf = false
 			currentNode.isHandler = false
@@ -358,12 +620,15 @@ func (r *Router) insertNode(method, path string, t kind, rm routeMethod) {
 				// Only Static children could reach here
 				currentNode.addStaticChild(n)
 			}
-			currentNode.isLeaf = currentNode.staticChildren == nil && currentNode.paramChild == nil && currentNode.anyChild == nil
+			currentNode.isLeaf = currentNode.staticChildren == nil && currentNode.paramChildren == nil && currentNode.anyChild == nil
 		} else if lcpLen < searchLen {
 			search = search[lcpLen:]
-			c := currentNode.findChildWithLabel(search[0])
+			c := currentNode.findChildWithLabel(search[0], paramAt(paramIndex).constraint)
 			if c != nil {
 				// Go deeper
+				if c.kind != staticKind {
+					paramIndex++
+				}
 				currentNode = c
 				continue
 			}
@@ -378,11 +643,13 @@ func (r *Router) insertNode(method, path string, t kind, rm routeMethod) {
 			case staticKind:
 				currentNode.addStaticChild(n)
 			case paramKind:
-				currentNode.paramChild = n
+				n.constraint = paramAt(paramIndex).constraint
+				n.paramName = paramAt(paramIndex).name
+				currentNode.addParamChild(n)
 			case anyKind:
 				currentNode.anyChild = n
 			}
-			currentNode.isLeaf = currentNode.staticChildren == nil && currentNode.paramChild == nil && currentNode.anyChild == nil
+			currentNode.isLeaf = currentNode.staticChildren == nil && currentNode.paramChildren == nil && currentNode.anyChild == nil
 		} else {
 			// Node already exists
 			if rm.handler != nil {
@@ -403,7 +670,7 @@ func newNode(
 	originalPath string,
 	methods *routeMethods,
 	paramsCount int,
-	paramChildren,
+	paramChildren children,
 	anyChildren *node,
 	notFoundHandler *routeMethod,
 ) *node {
@@ -416,7 +683,7 @@ func newNode(
 		originalPath:    originalPath,
 		methods:         methods,
 		paramsCount:     paramsCount,
-		paramChild:      paramChildren,
+		paramChildren:   paramChildren,
 		anyChild:        anyChildren,
 		isLeaf:          sc == nil && paramChildren == nil && anyChildren == nil,
 		isHandler:       methods.isHandler(),
@@ -437,12 +704,66 @@ func (n *node) findStaticChild(l byte) *node {
 	return nil
 }
 
-func (n *node) findChildWithLabel(l byte) *node {
+// addParamChild adds param child. Children with constraint are kept in order they were added before child without
+// constraint so constrained routes are tried first.
+func (n *node) addParamChild(c *node) {
+	if c.constraint == nil || len(n.paramChildren) == 0 || n.paramChildren[len(n.paramChildren)-1].constraint != nil {
+		n.paramChildren = append(n.paramChildren, c)
+		return
+	}
+	last := len(n.paramChildren) - 1
+	n.paramChildren = append(n.paramChildren[:last], c, n.paramChildren[last])
+}
+
+func (n *node) findParamChild(constraint *paramConstraint) *node {
+	for _, c := range n.paramChildren {
+		if c.constraint == nil && constraint == nil || c.constraint != nil && constraint != nil && c.constraint.expr == constraint.expr {
+			return c
+		}
+	}
+	return nil
+}
+
+// matchParamChild returns first param child, starting from index start, which constraint accepts next value from
+// search and the length of that value.
+func (n *node) matchParamChild(search string, start int) (*node, int) {
+	if search == "" {
+		return nil, 0
+	}
+	for ; start < len(n.paramChildren); start++ {
+		child := n.paramChildren[start]
+		i := 0
+		l := len(search)
+		if child.isLeaf {
+			// when param node does not have any children (path param is last piece of route path) then param node should
+			// act similarly to any node - consider all remaining search as match
+			i = l
+		} else {
+			for ; i < l && search[i] != '/'; i++ {
+			}
+		}
+		if child.constraint == nil || child.constraint.match(search[:i]) {
+			return child, i
+		}
+	}
+	return nil, 0
+}
+
+func (n *node) paramChildIndex(c *node) int {
+	for i, child := range n.paramChildren {
+		if child == c {
+			return i
+		}
+	}
+	return -1
+}
+
+func (n *node) findChildWithLabel(l byte, constraint *paramConstraint) *node {
 	if c := n.findStaticChild(l); c != nil {
 		return c
 	}
 	if l == paramLabel {
-		return n.paramChild
+		return n.findParamChild(constraint)
 	}
 	if l == anyLabel {
 		return n.anyChild
@@ -492,6 +813,125 @@ func (n *node) addMethod(method string, h *routeMethod) {
 	n.isHandler = true
 }
 
+// removeMethod removes handler for method from node.
+func (n *node) removeMethod(method string) {
+	switch method {
+	case http.MethodConnect:
+		n.methods.connect = nil
+	case http.MethodDelete:
+		n.methods.delete = nil
+	case http.MethodGet:
+		n.methods.get = nil
+	case http.MethodHead:
+		n.methods.head = nil
+	case http.MethodOptions:
+		n.methods.options = nil
+	case http.MethodPatch:
+		n.methods.patch = nil
+	case http.MethodPost:
+		n.methods.post = nil
+	case PROPFIND:
+		n.methods.propfind = nil
+	case http.MethodPut:
+		n.methods.put = nil
+	case http.MethodTrace:
+		n.methods.trace = nil
+	case REPORT:
+		n.methods.report = nil
+	case RouteNotFound:
+		n.notFoundHandler = nil
+		return
+	default:
+		delete(n.methods.anyOther, method)
+	}
+
+	n.methods.updateAllowHeader()
+	n.isHandler = n.methods.isHandler()
+}
+
+// prune removes node and its ancestors that are left without handlers and children. Removing these nodes keeps param
+// nodes that became leaves matching the rest of the path same way as they would if removed routes were never added.
+func (n *node) prune() {
+	for n.parent != nil && !n.isHandler && n.notFoundHandler == nil && n.isLeaf {
+		p := n.parent
+		p.staticChildren = removeChild(p.staticChildren, n)
+		p.paramChildren = removeChild(p.paramChildren, n)
+		if p.anyChild == n {
+			p.anyChild = nil
+		}
+		p.isLeaf = p.staticChildren == nil && p.paramChildren == nil && p.anyChild == nil
+		n = p
+	}
+}
+
+func removeChild(c children, n *node) children {
+	result := make(children, 0, len(c))
+	for _, child := range c {
+		if child != n {
+			result = append(result, child)
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// clone returns deep copy of node and its children.
+func (n *node) clone(parent *node) *node {
+	c := new(node)
+	*c = *n
+	c.parent = parent
+
+	methods := *n.methods
+	if n.methods.anyOther != nil {
+		methods.anyOther = make(map[string]*routeMethod, len(n.methods.anyOther))
+		for k, v := range n.methods.anyOther {
+			methods.anyOther[k] = v
+		}
+	}
+	c.methods = &methods
+
+	c.staticChildren = cloneChildren(n.staticChildren, c)
+	c.paramChildren = cloneChildren(n.paramChildren, c)
+	if n.anyChild != nil {
+		c.anyChild = n.anyChild.clone(c)
+	}
+	return c
+}
+
+func cloneChildren(children children, parent *node) children {
+	if children == nil {
+		return nil
+	}
+	result := make([]*node, len(children))
+	for i, child := range children {
+		result[i] = child.clone(parent)
+	}
+	return result
+}
+
+// routePath returns path of first route found in node or its descendants.
+func (n *node) routePath() string {
+	if n.isHandler && n.originalPath != "" {
+		return n.originalPath
+	}
+	for _, c := range n.staticChildren {
+		if p := c.routePath(); p != "" {
+			return p
+		}
+	}
+	for _, c := range n.paramChildren {
+		if p := c.routePath(); p != "" {
+			return p
+		}
+	}
+	if n.anyChild != nil {
+		return n.anyChild.routePath()
+	}
+	return ""
+}
+
 func (n *node) findMethod(method string) *routeMethod {
 	switch method {
 	case http.MethodConnect:
@@ -540,7 +980,12 @@ func optionsMethodHandler(allowMethods string) func(c Context) error {
 // - Reset it `Context#Reset()`
 // - Return it `Echo#ReleaseContext()`.
 func (r *Router) Find(method, path string, c Context) {
-	ctx := c.(*context)
+	r.find(method, path, c.(*context))
+}
+
+// find implements Find. Returns node that path matched and whether node has handler for method. Node is nil when
+// nothing matched.
+func (r *Router) find(method, path string, ctx *context) (*node, bool) {
 	currentNode := r.tree // Current node as root
 
 	var (
@@ -552,6 +997,9 @@ func (r *Router) Find(method, path string, c Context) {
 		searchIndex = 0
 		paramIndex  int           // Param counter
 		paramValues = ctx.pvalues // Use the internal slice so the interface can keep the illusion of a dynamic slice
+		// nextParamChild is index of param child to check next. It is set when backtracking from param node which
+		// constraint or subtree did not match so its siblings are checked before moving to any node.
+		nextParamChild int
 	)
 
 	// Backtracking is needed when a dead end (leaf node) is reached in the router tree.
@@ -567,6 +1015,9 @@ func (r *Router) Find(method, path string, c Context) {
 		// Next node type by priority
 		if previous.kind == anyKind {
 			nextNodeKind = staticKind
+		} else if previous.kind == paramKind && valid && currentNode.paramChildIndex(previous)+1 < len(currentNode.paramChildren) {
+			nextNodeKind = paramKind // next sibling param node
+			nextParamChild = currentNode.paramChildIndex(previous) + 1
 		} else {
 			nextNodeKind = previous.kind + 1
 		}
@@ -618,7 +1069,7 @@ func (r *Router) Find(method, path string, c Context) {
 			// No matching prefix, let's backtrack to the first possible alternative node of the decision path
 			nk, ok := backtrackToNextNodeKind(staticKind)
 			if !ok {
-				return // No other possibilities on the decision path, handler will be whatever context is reset to.
+				return nil, false // No other possibilities on the decision path, handler will be whatever context is reset to.
 			} else if nk == paramKind {
 				goto Param
 				// NOTE: this case (backtracking from static node to previous any node) can not happen by current any matching logic. Any node is end of search currently
@@ -663,18 +1114,9 @@ func (r *Router) Find(method, path string, c Context) {
 
 	Param:
 		// Param node
-		if child := currentNode.paramChild; search != "" && child != nil {
+		if child, i := currentNode.matchParamChild(search, nextParamChild); child != nil {
+			nextParamChild = 0
 			currentNode = child
-			i := 0
-			l := len(search)
-			if currentNode.isLeaf {
-				// when param node does not have any children (path param is last piece of route path) then param node should
-				// act similarly to any node - consider all remaining search as match
-				i = l
-			} else {
-				for ; i < l && search[i] != '/'; i++ {
-				}
-			}
 
 			paramValues[paramIndex] = search[:i]
 			paramIndex++
@@ -683,6 +1125,8 @@ func (r *Router) Find(method, path string, c Context) {
 			continue
 		}
 
+		nextParamChild = 0
+
 	Any:
 		// Any node
 		if child := currentNode.anyChild; child != nil {
@@ -724,16 +1168,20 @@ func (r *Router) Find(method, path string, c Context) {
 	}
 
 	if currentNode == nil && previousBestMatchNode == nil {
-		return // nothing matched at all
+		return nil, false // nothing matched at all
 	}
 
 	// matchedHandler could be method+path handler that we matched or notFoundHandler from node with matching path
 	// user provided not found (404) handler has priority over generic method not found (405) handler or global 404 handler
 	var rPath string
 	var rPNames []string
+	var rRoute *Route
+	var rGroup *Group
 	if matchedRouteMethod != nil {
 		rPath = matchedRouteMethod.ppath
 		rPNames = matchedRouteMethod.pnames
+		rRoute = matchedRouteMethod.route
+		rGroup = matchedRouteMethod.group
 		ctx.handler = matchedRouteMethod.handler
 	} else {
 		// use previous match as basis. although we have no matching handler we have path match.
@@ -746,9 +1194,12 @@ func (r *Router) Find(method, path string, c Context) {
 		if currentNode.notFoundHandler != nil {
 			rPath = currentNode.notFoundHandler.ppath
 			rPNames = currentNode.notFoundHandler.pnames
+			rRoute = currentNode.notFoundHandler.route
+			rGroup = currentNode.notFoundHandler.group
 			ctx.handler = currentNode.notFoundHandler.handler
 		} else if currentNode.isHandler {
-			ctx.Set(ContextKeyHeaderAllow, currentNode.methods.allowHeader)
+			rGroup = currentNode.methods.group()
+			headerAllowKey.Set(ctx, currentNode.methods.allowHeader)
 			ctx.handler = MethodNotAllowedHandler
 			if method == http.MethodOptions {
 				ctx.handler = optionsMethodHandler(currentNode.methods.allowHeader)
@@ -757,4 +1208,7 @@ func (r *Router) Find(method, path string, c Context) {
 	}
 	ctx.path = rPath
 	ctx.pnames = rPNames
+	ctx.route = rRoute
+	matchedGroupKey.Set(ctx, rGroup)
+	return currentNode, matchedRouteMethod != nil && matchedRouteMethod != currentNode.notFoundHandler
 }
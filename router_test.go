@@ -6,6 +6,7 @@ package echo
 import (
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -1513,6 +1514,307 @@ func TestRouterParam_escapeColon(t *testing.T) {
 	}
 }
 
+func TestRouterParamConstraints(t *testing.T) {
+	e := New()
+	e.ParamConstraints.Register("even", func(value string) bool {
+		n, err := strconv.Atoi(value)
+		return err == nil && n%2 == 0
+	})
+
+	e.GET("/users/:id<int>", handlerFunc)
+	e.GET("/users/:slug<[a-z-]+>", handlerFunc)
+	e.GET("/users/:name", handlerFunc)
+	e.GET("/items/:id<int>", handlerFunc)
+	e.GET("/orders/:uid<uuid>/lines/:line<even>", handlerFunc)
+	e.GET("/a/:id<int>/b", handlerFunc)
+	e.GET("/a/:name/c", handlerFunc)
+	e.GET("/files/:path<[a-z/]+>", handlerFunc)
+	e.GET("/b/:name", handlerFunc)
+	e.GET("/b/:id<int>", handlerFunc)
+
+	var testCases = []struct {
+		whenURL     string
+		expectRoute interface{}
+		expectParam map[string]string
+		expectError string
+	}{
+		{
+			whenURL:     "/users/12",
+			expectRoute: "/users/:id<int>",
+			expectParam: map[string]string{"id": "12"},
+		},
+		{
+			whenURL:     "/users/new-user",
+			expectRoute: "/users/:slug<[a-z-]+>",
+			expectParam: map[string]string{"slug": "new-user"},
+		},
+		{
+			whenURL:     "/users/Bob_1",
+			expectRoute: "/users/:name",
+			expectParam: map[string]string{"name": "Bob_1"},
+		},
+		{
+			whenURL:     "/items/abc",
+			expectError: "code=404, message=Not Found",
+		},
+		{
+			whenURL:     "/items/1/",
+			expectError: "code=404, message=Not Found",
+		},
+		{
+			whenURL:     "/orders/0b5d8ab8-3c59-4bcb-9e7c-3fb9a1d4d7c1/lines/2",
+			expectRoute: "/orders/:uid<uuid>/lines/:line<even>",
+			expectParam: map[string]string{"uid": "0b5d8ab8-3c59-4bcb-9e7c-3fb9a1d4d7c1", "line": "2"},
+		},
+		{
+			whenURL:     "/orders/0b5d8ab8-3c59-4bcb-9e7c-3fb9a1d4d7c1/lines/3",
+			expectError: "code=404, message=Not Found",
+		},
+		{
+			whenURL:     "/orders/1/lines/2",
+			expectError: "code=404, message=Not Found",
+		},
+		{
+			whenURL:     "/a/1/b",
+			expectRoute: "/a/:id<int>/b",
+			expectParam: map[string]string{"id": "1"},
+		},
+		{
+			whenURL:     "/a/1/c", // backtracks from constrained param node to its sibling
+			expectRoute: "/a/:name/c",
+			expectParam: map[string]string{"name": "1"},
+		},
+		{
+			whenURL:     "/b/5", // constrained param is checked before param without constraint added earlier
+			expectRoute: "/b/:id<int>",
+			expectParam: map[string]string{"id": "5"},
+		},
+		{
+			whenURL:     "/b/x",
+			expectRoute: "/b/:name",
+			expectParam: map[string]string{"name": "x"},
+		},
+		{
+			whenURL:     "/files/docs/readme",
+			expectRoute: "/files/:path<[a-z/]+>",
+			expectParam: map[string]string{"path": "docs/readme"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.whenURL, func(t *testing.T) {
+			c := e.NewContext(nil, nil).(*context)
+
+			e.router.Find(http.MethodGet, tc.whenURL, c)
+			err := c.handler(c)
+
+			assert.Equal(t, tc.expectRoute, c.Get("path"))
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+			for param, expectedValue := range tc.expectParam {
+				assert.Equal(t, expectedValue, c.Param(param))
+			}
+			checkUnusedParamValues(t, c, tc.expectParam)
+		})
+	}
+}
+
+func TestRouterParamConstraints_invalid(t *testing.T) {
+	var testCases = []struct {
+		whenPath    string
+		expectPanic string
+	}{
+		{
+			whenPath:    "/users/:id<number>",
+			expectPanic: `echo: invalid constraint of path parameter in route path "/users/:id<number>": unknown constraint "number"`,
+		},
+		{
+			whenPath:    "/users/:id<[0-9>",
+			expectPanic: "echo: invalid constraint of path parameter in route path \"/users/:id<[0-9>\": error parsing regexp: missing closing ]: `[0-9)$`",
+		},
+		{
+			whenPath:    "/users/:id<int>/files/:name<[a-z>",
+			expectPanic: "echo: invalid constraint of path parameter in route path \"/users/:id<int>/files/:name<[a-z>\": error parsing regexp: missing closing ]: `[a-z)$`",
+		},
+		{
+			whenPath:    "/users/:id<int",
+			expectPanic: `echo: unterminated constraint of path parameter in route path "/users/:id<int"`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.whenPath, func(t *testing.T) {
+			e := New()
+			assert.PanicsWithValue(t, tc.expectPanic, func() {
+				e.GET(tc.whenPath, handlerFunc)
+			})
+		})
+	}
+}
+
+func TestRouterReverse_paramConstraints(t *testing.T) {
+	e := New()
+	e.GET("/users/:id<int>/files/:path<[a-z/]+>", handlerFunc).Name = "files"
+
+	assert.Equal(t, "/users/1/files/docs", e.Reverse("files", 1, "docs"))
+}
+
+func TestRouter_clone(t *testing.T) {
+	e := New()
+	r := e.router
+	r.Add(http.MethodGet, "/users/:id", handlerFunc)
+
+	clone := r.clone()
+	clone.Add(http.MethodPost, "/users/:id", handlerFunc)
+	clone.Add(http.MethodGet, "/users/:id/files/*", handlerFunc)
+	clone.Remove(http.MethodGet, "/users/:id")
+
+	c := e.NewContext(nil, nil).(*context)
+	r.Find(http.MethodGet, "/users/1/files/a", c)
+	assert.NoError(t, c.handler(c))
+	assert.Equal(t, "/users/:id", c.Get("path"))
+	assert.Equal(t, "1/files/a", c.Param("id"))
+
+	c = e.NewContext(nil, httptest.NewRecorder()).(*context)
+	clone.Find(http.MethodGet, "/users/1", c)
+	assert.EqualError(t, c.handler(c), "code=405, message=Method Not Allowed")
+	assert.Equal(t, "OPTIONS, POST", c.Get(ContextKeyHeaderAllow))
+
+	c = e.NewContext(nil, nil).(*context)
+	clone.Find(http.MethodGet, "/users/1/files/a", c)
+	assert.NoError(t, c.handler(c))
+	assert.Equal(t, "/users/:id/files/*", c.Get("path"))
+}
+
+func TestRouter_Remove(t *testing.T) {
+	e := New()
+	r := e.router
+	r.Add(http.MethodGet, "/static", handlerFunc)
+	r.Add("PURGE", "/static", handlerFunc)
+	r.Add(RouteNotFound, "/static/*", handlerFunc)
+	r.Add(http.MethodGet, "/static/*", handlerFunc)
+
+	assert.True(t, r.Remove("PURGE", "/static"))
+	assert.True(t, r.Remove(http.MethodGet, "/static/*"))
+	assert.False(t, r.Remove(http.MethodGet, "/missing"))
+
+	c := e.NewContext(nil, httptest.NewRecorder()).(*context)
+	r.Find("PURGE", "/static", c)
+	assert.EqualError(t, c.handler(c), "code=405, message=Method Not Allowed")
+	assert.Equal(t, "OPTIONS, GET", c.Get(ContextKeyHeaderAllow))
+
+	// "route not found" route for the same path is kept
+	c = e.NewContext(nil, nil).(*context)
+	r.Find(http.MethodGet, "/static/a", c)
+	assert.NoError(t, c.handler(c))
+	assert.Equal(t, "/static/*", c.Get("path"))
+
+	assert.True(t, r.Remove(RouteNotFound, "/static/*"))
+	c = e.NewContext(nil, nil).(*context)
+	r.Find(http.MethodGet, "/static/a", c)
+	assert.EqualError(t, c.handler(c), "code=404, message=Not Found")
+}
+
+func TestRouter_conflicts(t *testing.T) {
+	e := New()
+	r := e.router
+	r.Add(http.MethodGet, "/users/:id", handlerFunc)
+	r.Add(http.MethodGet, "/users/:id<int>/files", handlerFunc)
+	r.Add(http.MethodGet, "/static", handlerFunc)
+	r.Add(RouteNotFound, "/static/*", handlerFunc)
+
+	var testCases = []struct {
+		name        string
+		whenMethod  string
+		whenPath    string
+		expectKinds []RouteConflictKind
+		expectWith  []string
+	}{
+		{
+			name:        "duplicate",
+			whenMethod:  http.MethodGet,
+			whenPath:    "/static",
+			expectKinds: []RouteConflictKind{RouteConflictDuplicate},
+			expectWith:  []string{"/static"},
+		},
+		{
+			name:       "other method is not duplicate",
+			whenMethod: http.MethodPost,
+			whenPath:   "/static",
+		},
+		{
+			name:       "route not found route is not duplicate",
+			whenMethod: RouteNotFound,
+			whenPath:   "/static/*",
+		},
+		{
+			name:       "prefix of existing route",
+			whenMethod: http.MethodGet,
+			whenPath:   "/stat",
+		},
+		{
+			name:        "param name",
+			whenMethod:  http.MethodGet,
+			whenPath:    "/users/:name/posts",
+			expectKinds: []RouteConflictKind{RouteConflictParamName},
+			expectWith:  []string{"/users/:id"},
+		},
+		{
+			name:        "duplicate with different param name",
+			whenMethod:  http.MethodGet,
+			whenPath:    "/users/:name",
+			expectKinds: []RouteConflictKind{RouteConflictParamName, RouteConflictDuplicate},
+			expectWith:  []string{"/users/:id", "/users/:id"},
+		},
+		{
+			name:        "param name of param with same constraint",
+			whenMethod:  http.MethodGet,
+			whenPath:    "/users/:uid<int>",
+			expectKinds: []RouteConflictKind{RouteConflictParamName},
+			expectWith:  []string{"/users/:id<int>/files"},
+		},
+		{
+			name:       "param with other constraint",
+			whenMethod: http.MethodGet,
+			whenPath:   "/users/:uid<uuid>",
+		},
+		{
+			name:       "escaped colon next to param",
+			whenMethod: http.MethodGet,
+			whenPath:   "/users/\\:foo",
+		},
+		{
+			name:       "escaped colon before param",
+			whenMethod: http.MethodGet,
+			whenPath:   "/users/\\:foo/:name",
+		},
+		{
+			name:        "unreachable",
+			whenMethod:  http.MethodGet,
+			whenPath:    "/files/*/raw",
+			expectKinds: []RouteConflictKind{RouteConflictUnreachable},
+			expectWith:  []string{""},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			conflicts := r.conflicts(tc.whenMethod, tc.whenPath)
+
+			var kinds []RouteConflictKind
+			var with []string
+			for _, c := range conflicts {
+				assert.Equal(t, tc.whenMethod, c.Method)
+				assert.Equal(t, tc.whenPath, c.Path)
+				kinds = append(kinds, c.Kind)
+				with = append(with, c.ConflictsWith)
+			}
+			assert.Equal(t, tc.expectKinds, kinds)
+			assert.Equal(t, tc.expectWith, with)
+		})
+	}
+}
+
 func TestRouterMatchAny(t *testing.T) {
 	e := New()
 	r := e.router
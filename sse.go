@@ -0,0 +1,199 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"bytes"
+	stdContext "context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Event is a single Server-Sent Event. See https://html.spec.whatwg.org/multipage/server-sent-events.html
+type Event struct {
+	// ID sets the event stream's last event ID. Client sends it back in `Last-Event-ID` header when reconnecting.
+	// Newlines are not allowed and are removed.
+	ID string
+	// Event is event type. When empty, client dispatches event as `message` event. Newlines are not allowed and are removed.
+	Event string
+	// Data is event payload. Multi-line data is sent as multiple `data` fields and is reassembled by the client.
+	Data string
+	// Retry is reconnection time that client should use. Sent only when greater than zero.
+	Retry time.Duration
+}
+
+// EventStream writes Server-Sent Events to the response. Every written event is flushed to the client immediately.
+// EventStream methods are safe for concurrent use.
+//
+// Example:
+//
+//	e.GET("/events", func(c echo.Context) error {
+//		return c.EventStream(func(es *echo.EventStream) error {
+//			es.Heartbeat(15 * time.Second)
+//			for {
+//				select {
+//				case <-es.Done():
+//					return nil
+//				case msg := <-messages:
+//					if err := es.Send(echo.Event{Data: msg}); err != nil {
+//						return err
+//					}
+//				}
+//			}
+//		})
+//	})
+type EventStream struct {
+	response    *Response
+	ctx         stdContext.Context
+	lastEventID string
+
+	lock   sync.Mutex
+	closed bool
+	stop   chan struct{}
+	wg     sync.WaitGroup
+}
+
+var (
+	// ErrEventStreamClosed is returned when writing to EventStream after handler function has returned.
+	ErrEventStreamClosed = errors.New("event stream closed")
+
+	eventFieldReplacer = strings.NewReplacer("\r\n", "", "\r", "", "\n", "")
+)
+
+func (c *context) EventStream(fn func(es *EventStream) error) error {
+	res := c.Response()
+	header := res.Header()
+	header.Set(HeaderContentType, MIMETextEventStream)
+	header.Set(HeaderCacheControl, "no-cache")
+	header.Set(HeaderXAccelBuffering, "no")
+	header.Del(HeaderContentLength)
+	res.WriteHeader(http.StatusOK)
+	if err := http.NewResponseController(res.Writer).Flush(); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	es := &EventStream{
+		response:    res,
+		ctx:         ctx,
+		lastEventID: c.Request().Header.Get(HeaderLastEventID),
+		stop:        make(chan struct{}),
+	}
+	err := fn(es)
+	es.close()
+
+	if err != nil && ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+		return nil
+	}
+	return err
+}
+
+// LastEventID returns value of `Last-Event-ID` request header that client sends when it reconnects to the stream.
+// Can be used to resume the stream from last event client received.
+func (es *EventStream) LastEventID() string {
+	return es.lastEventID
+}
+
+// Done returns channel that is closed when request context is cancelled, i.e. client has disconnected.
+func (es *EventStream) Done() <-chan struct{} {
+	return es.ctx.Done()
+}
+
+// Send writes event to the stream and flushes it to the client. Returns request context error when client has disconnected.
+func (es *EventStream) Send(event Event) error {
+	buf := new(bytes.Buffer)
+	if event.ID != "" {
+		buf.WriteString("id: ")
+		buf.WriteString(eventFieldReplacer.Replace(event.ID))
+		buf.WriteByte('\n')
+	}
+	if event.Event != "" {
+		buf.WriteString("event: ")
+		buf.WriteString(eventFieldReplacer.Replace(event.Event))
+		buf.WriteByte('\n')
+	}
+	if event.Retry > 0 {
+		buf.WriteString("retry: ")
+		buf.WriteString(strconv.FormatInt(event.Retry.Milliseconds(), 10))
+		buf.WriteByte('\n')
+	}
+	if event.Data != "" || buf.Len() == 0 {
+		writeEventLines(buf, "data: ", event.Data)
+	}
+	buf.WriteByte('\n')
+	return es.write(buf.Bytes())
+}
+
+// Comment writes comment to the stream. Comments are ignored by the client but keep the connection alive.
+func (es *EventStream) Comment(text string) error {
+	buf := new(bytes.Buffer)
+	writeEventLines(buf, ": ", text)
+	buf.WriteByte('\n')
+	return es.write(buf.Bytes())
+}
+
+// Heartbeat starts sending empty comments to the stream with given interval until the stream is terminated. Heartbeats
+// keep idle connections from being closed by proxies and help to detect disconnected clients.
+func (es *EventStream) Heartbeat(interval time.Duration) {
+	es.lock.Lock()
+	defer es.lock.Unlock()
+	if interval <= 0 || es.closed {
+		return
+	}
+	es.wg.Add(1)
+	go func() {
+		defer es.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-es.stop:
+				return
+			case <-es.ctx.Done():
+				return
+			case <-ticker.C:
+				if err := es.write([]byte(":\n\n")); err != nil {
+					return
+				}
+			}
+		}
+	}()
+}
+
+func (es *EventStream) write(b []byte) error {
+	if err := es.ctx.Err(); err != nil {
+		return err
+	}
+	es.lock.Lock()
+	defer es.lock.Unlock()
+	if es.closed {
+		return ErrEventStreamClosed
+	}
+	if _, err := es.response.Write(b); err != nil {
+		return err
+	}
+	return http.NewResponseController(es.response.Writer).Flush()
+}
+
+func (es *EventStream) close() {
+	es.lock.Lock()
+	es.closed = true
+	es.lock.Unlock()
+	close(es.stop)
+	es.wg.Wait()
+}
+
+func writeEventLines(buf *bytes.Buffer, prefix string, text string) {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	for _, line := range strings.Split(text, "\n") {
+		buf.WriteString(prefix)
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+}
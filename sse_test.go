@@ -0,0 +1,162 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	stdContext "context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventStream_Send(t *testing.T) {
+	var testCases = []struct {
+		name       string
+		whenEvent  Event
+		expectBody string
+	}{
+		{
+			name:       "ok, data only",
+			whenEvent:  Event{Data: "hello"},
+			expectBody: "data: hello\n\n",
+		},
+		{
+			name:       "ok, all fields",
+			whenEvent:  Event{ID: "42", Event: "progress", Data: "50%", Retry: 3 * time.Second},
+			expectBody: "id: 42\nevent: progress\nretry: 3000\ndata: 50%\n\n",
+		},
+		{
+			name:       "ok, multi-line data",
+			whenEvent:  Event{Data: "line1\nline2\r\nline3"},
+			expectBody: "data: line1\ndata: line2\ndata: line3\n\n",
+		},
+		{
+			name:       "ok, newlines are removed from id and event",
+			whenEvent:  Event{ID: "1\n2", Event: "a\r\nb", Data: "x"},
+			expectBody: "id: 12\nevent: ab\ndata: x\n\n",
+		},
+		{
+			name:       "ok, id only",
+			whenEvent:  Event{ID: "7"},
+			expectBody: "id: 7\n\n",
+		},
+		{
+			name:       "ok, empty event",
+			whenEvent:  Event{},
+			expectBody: "data: \n\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := c.EventStream(func(es *EventStream) error {
+				return es.Send(tc.whenEvent)
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, MIMETextEventStream, rec.Header().Get(HeaderContentType))
+			assert.Equal(t, "no-cache", rec.Header().Get(HeaderCacheControl))
+			assert.Equal(t, "no", rec.Header().Get(HeaderXAccelBuffering))
+			assert.True(t, rec.Flushed)
+			assert.Equal(t, tc.expectBody, rec.Body.String())
+		})
+	}
+}
+
+func TestEventStream_Comment(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := c.EventStream(func(es *EventStream) error {
+		return es.Comment("keep\nalive")
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, ": keep\n: alive\n\n", rec.Body.String())
+}
+
+func TestEventStream_LastEventID(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(HeaderLastEventID, "99")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	var lastEventID string
+	err := c.EventStream(func(es *EventStream) error {
+		lastEventID = es.LastEventID()
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "99", lastEventID)
+}
+
+func TestEventStream_Heartbeat(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := c.EventStream(func(es *EventStream) error {
+		es.Heartbeat(time.Millisecond)
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	body := rec.Body.String()
+	assert.True(t, strings.HasPrefix(body, ":\n\n"))
+	assert.Equal(t, "", strings.ReplaceAll(body, ":\n\n", ""))
+}
+
+func TestEventStream_ClientDisconnect(t *testing.T) {
+	e := New()
+	ctx, cancel := stdContext.WithCancel(stdContext.Background())
+	req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := c.EventStream(func(es *EventStream) error {
+		if err := es.Send(Event{Data: "first"}); err != nil {
+			return err
+		}
+		cancel()
+		<-es.Done()
+		return es.Send(Event{Data: "second"})
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "data: first\n\n", rec.Body.String())
+}
+
+func TestEventStream_SendAfterClose(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	var stream *EventStream
+	err := c.EventStream(func(es *EventStream) error {
+		stream = es
+		return errors.New("handler error")
+	})
+
+	assert.EqualError(t, err, "handler error")
+	assert.ErrorIs(t, stream.Send(Event{Data: "late"}), ErrEventStreamClosed)
+	stream.Heartbeat(time.Millisecond) // no-op on closed stream
+}
@@ -0,0 +1,524 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"fmt"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// ValidationRuleFunc reports whether field value satisfies custom validation rule. Param is rule parameter from struct
+// tag, i.e. `2` for `validate:"divisible=2"`, and is empty for rules without parameter. Value of pointer field is
+// dereferenced before rule is called.
+type ValidationRuleFunc func(value reflect.Value, param string) bool
+
+// StructValidator is Validator that validates structs by `validate` struct tags. Enable it with
+// `e.Validator = echo.NewStructValidator()`.
+//
+// Rules in tag are separated by comma and are checked in order they are listed:
+//   - `required` value must not be zero value (nil, empty string, 0, false or empty slice/map),
+//   - `omitempty` other rules are skipped when value is zero value,
+//   - `min=n`, `max=n` and `len=n` limit numbers by value and strings (in characters), slices and maps by length,
+//   - `oneof=a b c` value must be one of space separated values,
+//   - `email`, `url` and `uuid` strings must be valid email address, absolute URL or UUID,
+//   - `regexp=pattern` string must match pattern as whole. Rule must be last in the tag as pattern can contain commas,
+//   - `eqfield=F`, `nefield=F`, `gtfield=F`, `gtefield=F`, `ltfield=F`, `ltefield=F` compare value to field F of the
+//     same struct. Numbers, strings and `time.Time` values can be compared,
+//   - `dive` rules after it are applied to each element of slice, array or map,
+//   - rules registered with RegisterRule.
+//
+// Fields of nested structs and structs in slices, arrays and maps are validated recursively. Tag `validate:"-"` skips
+// the field. Parsed rules are cached per struct type.
+//
+// Validate returns `*HTTPError` with status 400 Bad Request that wraps `*ValidationError` listing all invalid fields by
+// their path, i.e. `items[0].name`. Path uses names from `json`, `form`, `query` or `param` tags and falls back to Go
+// field names.
+type StructValidator struct {
+	rules map[string]ValidationRuleFunc
+	// types caches parsed rules by struct type.
+	types sync.Map
+}
+
+// ValidationError lists struct fields that failed validation. Implements InvalidParamsError interface so problem details
+// list the fields in `invalid-params` extension member.
+type ValidationError struct {
+	Errors []FieldError `json:"errors"`
+}
+
+// FieldError describes struct field that failed validation rule.
+type FieldError struct {
+	// Field is path of the field, i.e. `address.street` or `items[0].name`.
+	Field string `json:"field"`
+	// Rule is name of the rule that failed, i.e. `min`.
+	Rule string `json:"rule"`
+	// Param is parameter of the rule, i.e. `3` for `min=3`.
+	Param string `json:"param,omitempty"`
+	// Message describes why value is not valid, i.e. `must be at least 3 characters long`.
+	Message string `json:"message"`
+}
+
+// Error returns field errors joined to single message.
+func (ve *ValidationError) Error() string {
+	msgs := make([]string, len(ve.Errors))
+	for i, fe := range ve.Errors {
+		msgs[i] = fe.Field + " " + fe.Message
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// InvalidParams returns fields that failed validation. Implements InvalidParamsError interface.
+func (ve *ValidationError) InvalidParams() []InvalidParam {
+	params := make([]InvalidParam, len(ve.Errors))
+	for i, fe := range ve.Errors {
+		params[i] = InvalidParam{Name: fe.Field, Reason: fe.Message}
+	}
+	return params
+}
+
+// structRules are parsed validation rules of struct type.
+type structRules struct {
+	fields []fieldRules
+	err    error
+}
+
+// fieldRules are validation rules of struct field, or of elements of slice, array or map field after `dive`.
+type fieldRules struct {
+	index     int
+	name      string
+	omitEmpty bool
+	rules     []validationRule
+	// elem are rules for elements after `dive`. Nil when tag does not contain `dive`.
+	elem *fieldRules
+}
+
+type validationRule struct {
+	name  string
+	param string
+	// check reports whether value satisfies the rule. parent is struct that contains the field.
+	check func(value, parent reflect.Value) bool
+	// message describes failed rule for value.
+	message func(value reflect.Value) string
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// maxValidationDepth limits nesting of validated values, i.e. of pointers that refer back to the struct containing them.
+const maxValidationDepth = 1000
+
+// NewStructValidator creates new instance of StructValidator.
+func NewStructValidator() *StructValidator {
+	return &StructValidator{rules: map[string]ValidationRuleFunc{}}
+}
+
+// RegisterRule adds custom rule that can be used in `validate` tags by name. Rule with the same name is replaced.
+// Rules must be registered before structs using them are validated.
+//
+// Example: `v.RegisterRule("even", func(v reflect.Value, _ string) bool { return v.CanInt() && v.Int()%2 == 0 })`
+func (v *StructValidator) RegisterRule(name string, fn ValidationRuleFunc) {
+	v.rules[name] = fn
+}
+
+// Validate validates struct, pointer to struct or slice of structs by `validate` tags. Values of other types are
+// valid. Returns error that is not `*ValidationError` when tag contains invalid rule.
+func (v *StructValidator) Validate(i interface{}) error {
+	var errs []FieldError
+	if err := v.validate(reflect.ValueOf(i), "", 0, &errs); err != nil {
+		return err
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	ve := &ValidationError{Errors: errs}
+	return NewHTTPError(http.StatusBadRequest, ve.Error()).SetInternal(ve)
+}
+
+// validate validates structs in value recursively and adds errors of invalid fields to errs. Depth is number of
+// nested values validated before value and limits recursion of cyclic pointers.
+func (v *StructValidator) validate(value reflect.Value, path string, depth int, errs *[]FieldError) error {
+	value = indirectValue(value)
+	if !value.IsValid() {
+		return nil
+	}
+	if depth > maxValidationDepth {
+		return fmt.Errorf("echo: validated value %s exceeds maximum nesting depth of %d", path, maxValidationDepth)
+	}
+	switch value.Kind() {
+	case reflect.Struct:
+		if value.Type() == timeType {
+			return nil
+		}
+		sr := v.structRules(value.Type())
+		if sr.err != nil {
+			return sr.err
+		}
+		for _, fr := range sr.fields {
+			fieldPath := fr.name
+			if path != "" {
+				fieldPath = path + "." + fr.name
+			}
+			if err := v.validateField(value.Field(fr.index), value, fieldPath, depth+1, &fr, errs); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if !hasStructs(value.Type().Elem()) {
+			return nil
+		}
+		for i := 0; i < value.Len(); i++ {
+			if err := v.validate(value.Index(i), path+"["+strconv.Itoa(i)+"]", depth+1, errs); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if !hasStructs(value.Type().Elem()) {
+			return nil
+		}
+		iter := value.MapRange()
+		for iter.Next() {
+			if err := v.validate(iter.Value(), path+"["+fmt.Sprint(iter.Key().Interface())+"]", depth+1, errs); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateField checks rules of the field, rules of its elements and validates nested structs.
+func (v *StructValidator) validateField(value, parent reflect.Value, path string, depth int, fr *fieldRules, errs *[]FieldError) error {
+	if fr.omitEmpty && value.IsZero() {
+		return nil
+	}
+	if (value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface) && value.IsNil() {
+		for _, r := range fr.rules {
+			if r.name == "required" {
+				*errs = append(*errs, FieldError{Field: path, Rule: r.name, Message: r.message(value)})
+			}
+		}
+		return nil
+	}
+	for _, r := range fr.rules {
+		checked := value
+		if r.name != "required" { // required checks pointer itself, other rules check value pointer points to
+			checked = indirectValue(value)
+		}
+		if !r.check(checked, parent) {
+			*errs = append(*errs, FieldError{Field: path, Rule: r.name, Param: r.param, Message: r.message(checked)})
+			return nil // following rules usually fail for the same reason
+		}
+	}
+
+	elem := indirectValue(value)
+	if fr.elem != nil {
+		switch elem.Kind() {
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < elem.Len(); i++ {
+				if err := v.validateField(elem.Index(i), parent, path+"["+strconv.Itoa(i)+"]", depth, fr.elem, errs); err != nil {
+					return err
+				}
+			}
+		case reflect.Map:
+			iter := elem.MapRange()
+			for iter.Next() {
+				key := path + "[" + fmt.Sprint(iter.Key().Interface()) + "]"
+				if err := v.validateField(iter.Value(), parent, key, depth, fr.elem, errs); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	return v.validate(elem, path, depth, errs)
+}
+
+// structRules returns cached rules of struct type.
+func (v *StructValidator) structRules(t reflect.Type) *structRules {
+	if sr, ok := v.types.Load(t); ok {
+		return sr.(*structRules)
+	}
+	sr := &structRules{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("validate")
+		if !f.IsExported() || tag == "-" {
+			continue
+		}
+		fr := fieldRules{index: i, name: validationFieldName(f)}
+		if err := v.parseRules(t, f, tag, &fr); err != nil {
+			sr.err = err
+			break
+		}
+		if len(fr.rules) == 0 && fr.elem == nil && !hasStructs(f.Type) {
+			continue
+		}
+		sr.fields = append(sr.fields, fr)
+	}
+	actual, _ := v.types.LoadOrStore(t, sr)
+	return actual.(*structRules)
+}
+
+// parseRules parses rules of field tag to fr.
+func (v *StructValidator) parseRules(t reflect.Type, f reflect.StructField, tag string, fr *fieldRules) error {
+	for tag != "" {
+		var part string
+		if strings.HasPrefix(tag, "regexp=") {
+			part, tag = tag, "" // pattern can contain commas
+		} else {
+			part, tag, _ = strings.Cut(tag, ",")
+		}
+		name, param, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch name {
+		case "":
+			continue
+		case "omitempty":
+			fr.omitEmpty = true
+			continue
+		case "dive":
+			fr.elem = &fieldRules{index: fr.index, name: fr.name}
+			fr = fr.elem
+			continue
+		}
+		r, err := v.newRule(t, name, param)
+		if err != nil {
+			return fmt.Errorf("echo: invalid validation rule %q of field %s.%s: %w", part, t.Name(), f.Name, err)
+		}
+		fr.rules = append(fr.rules, r)
+	}
+	return nil
+}
+
+func (v *StructValidator) newRule(t reflect.Type, name, param string) (validationRule, error) {
+	r := validationRule{name: name, param: param}
+	switch name {
+	case "required":
+		r.check = func(value, _ reflect.Value) bool { return !value.IsZero() }
+		r.message = func(reflect.Value) string { return "is required" }
+	case "min", "max", "len":
+		limit, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return r, err
+		}
+		r.check = func(value, _ reflect.Value) bool {
+			n, ok := sizeOf(value)
+			if !ok {
+				return false
+			}
+			switch name {
+			case "min":
+				return n >= limit
+			case "max":
+				return n <= limit
+			default:
+				return n == limit
+			}
+		}
+		r.message = func(value reflect.Value) string { return sizeMessage(name, param, value) }
+	case "oneof":
+		values := strings.Fields(param)
+		r.check = func(value, _ reflect.Value) bool {
+			s := fmt.Sprint(value.Interface())
+			for _, allowed := range values {
+				if s == allowed {
+					return true
+				}
+			}
+			return false
+		}
+		r.message = func(reflect.Value) string { return "must be one of: " + strings.Join(values, ", ") }
+	case "email":
+		r.check = stringRule(func(s string) bool {
+			addr, err := mail.ParseAddress(s)
+			return err == nil && addr.Name == "" && addr.Address == s
+		})
+		r.message = func(reflect.Value) string { return "must be a valid email address" }
+	case "url":
+		r.check = stringRule(func(s string) bool {
+			u, err := url.Parse(s)
+			return err == nil && u.Scheme != "" && u.Host != ""
+		})
+		r.message = func(reflect.Value) string { return "must be a valid URL" }
+	case "uuid":
+		r.check = stringRule(isUUIDParam)
+		r.message = func(reflect.Value) string { return "must be a valid UUID" }
+	case "regexp":
+		re, err := regexp.Compile(`^(?:` + param + `)$`)
+		if err != nil {
+			return r, err
+		}
+		r.check = stringRule(re.MatchString)
+		r.message = func(reflect.Value) string { return "must match pattern " + param }
+	case "eqfield", "nefield", "gtfield", "gtefield", "ltfield", "ltefield":
+		other, ok := t.FieldByName(param)
+		if !ok {
+			return r, fmt.Errorf("no field %q", param)
+		}
+		r.check = func(value, parent reflect.Value) bool {
+			return compareFields(name, value, indirectValue(parent.FieldByIndex(other.Index)))
+		}
+		otherName := validationFieldName(other)
+		r.message = func(reflect.Value) string { return fieldComparisonMessages[name] + " " + otherName }
+	default:
+		fn, ok := v.rules[name]
+		if !ok {
+			return r, fmt.Errorf("unknown rule %q", name)
+		}
+		r.check = func(value, _ reflect.Value) bool { return fn(value, param) }
+		r.message = func(reflect.Value) string { return fmt.Sprintf("failed %s validation", name) }
+	}
+	return r, nil
+}
+
+var fieldComparisonMessages = map[string]string{
+	"eqfield":  "must be equal to",
+	"nefield":  "must not be equal to",
+	"gtfield":  "must be greater than",
+	"gtefield": "must be greater than or equal to",
+	"ltfield":  "must be less than",
+	"ltefield": "must be less than or equal to",
+}
+
+// validationFieldName returns name of the field in paths of FieldError.
+func validationFieldName(f reflect.StructField) string {
+	for _, key := range []string{"json", "form", "query", "param"} {
+		if name, _, _ := strings.Cut(f.Tag.Get(key), ","); name != "" && name != "-" {
+			return name
+		}
+	}
+	return f.Name
+}
+
+func stringRule(fn func(s string) bool) func(value, parent reflect.Value) bool {
+	return func(value, _ reflect.Value) bool {
+		return value.Kind() == reflect.String && fn(value.String())
+	}
+}
+
+// sizeOf returns number value or length of string (in characters), slice, array or map.
+func sizeOf(value reflect.Value) (float64, bool) {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(value.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), true
+	case reflect.String:
+		return float64(utf8.RuneCountInString(value.String())), true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(value.Len()), true
+	}
+	return 0, false
+}
+
+func sizeMessage(rule, param string, value reflect.Value) string {
+	bound := map[string]string{"min": "at least", "max": "at most", "len": "exactly"}[rule]
+	switch value.Kind() {
+	case reflect.String:
+		return "must be " + bound + " " + param + " characters long"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return "must contain " + bound + " " + param + " items"
+	}
+	if rule == "len" {
+		return "must be " + param
+	}
+	return "must be " + bound + " " + param
+}
+
+// compareFields compares field value to value of other field with eqfield, nefield, gtfield etc. rule.
+func compareFields(rule string, value, other reflect.Value) bool {
+	cmp, ok := compareValues(value, other)
+	if !ok {
+		if rule == "eqfield" || rule == "nefield" {
+			equal := value.IsValid() && other.IsValid() && reflect.DeepEqual(value.Interface(), other.Interface())
+			return equal == (rule == "eqfield")
+		}
+		return false
+	}
+	switch rule {
+	case "eqfield":
+		return cmp == 0
+	case "nefield":
+		return cmp != 0
+	case "gtfield":
+		return cmp > 0
+	case "gtefield":
+		return cmp >= 0
+	case "ltfield":
+		return cmp < 0
+	default: // ltefield
+		return cmp <= 0
+	}
+}
+
+func compareValues(a, b reflect.Value) (int, bool) {
+	if !a.IsValid() || !b.IsValid() {
+		return 0, false
+	}
+	if a.Type() == timeType && b.Type() == timeType {
+		return a.Interface().(time.Time).Compare(b.Interface().(time.Time)), true
+	}
+	if a.Kind() == reflect.String && b.Kind() == reflect.String {
+		return strings.Compare(a.String(), b.String()), true
+	}
+	if a.Kind() == reflect.String || b.Kind() == reflect.String {
+		return 0, false
+	}
+	x, ok := sizeOf(a)
+	y, ok2 := sizeOf(b)
+	if !ok || !ok2 || a.Kind() == reflect.Slice || a.Kind() == reflect.Map || a.Kind() == reflect.Array {
+		return 0, false
+	}
+	switch {
+	case x < y:
+		return -1, true
+	case x > y:
+		return 1, true
+	}
+	return 0, true
+}
+
+// indirectValue dereferences pointers and interfaces. Returns invalid value for nil pointer.
+func indirectValue(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// hasStructs reports whether values of type can contain structs that are validated recursively.
+func hasStructs(t reflect.Type) bool {
+	return hasStructsVisited(t, map[reflect.Type]bool{})
+}
+
+// hasStructsVisited is hasStructs that skips types in visited, so recursive types like `type Tree map[string]Tree`
+// are inspected only once.
+func hasStructsVisited(t reflect.Type, visited map[reflect.Type]bool) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if visited[t] {
+		return false
+	}
+	visited[t] = true
+	switch t.Kind() {
+	case reflect.Struct:
+		return t != timeType
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return hasStructsVisited(t.Elem(), visited)
+	case reflect.Interface:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,336 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testValidateAddress struct {
+	Street string `json:"street" validate:"required"`
+	Zip    string `json:"zip" validate:"omitempty,len=5"`
+}
+
+type testValidateItem struct {
+	Name     string `json:"name" validate:"required,max=10"`
+	Quantity int    `json:"quantity" validate:"min=1"`
+}
+
+type testValidateUser struct {
+	Name     string               `json:"name" validate:"required,min=2,max=5"`
+	Email    string               `json:"email" validate:"omitempty,email"`
+	Website  string               `json:"website" validate:"omitempty,url"`
+	ID       string               `query:"id" validate:"omitempty,uuid"`
+	Role     string               `form:"role" validate:"omitempty,oneof=admin user"`
+	Code     string               `validate:"omitempty,regexp=[a-z]{2,3}"`
+	Age      *int                 `json:"age" validate:"omitempty,min=18"`
+	Manager  *testValidateUser    `json:"manager"`
+	Address  testValidateAddress  `json:"address"`
+	Items    []testValidateItem   `json:"items" validate:"max=2"`
+	Tags     []string             `json:"tags" validate:"dive,min=2"`
+	Labels   map[string]*struct{} `json:"labels"`
+	Password string               `json:"password"`
+	Confirm  string               `json:"confirm" validate:"eqfield=Password"`
+	Start    time.Time            `json:"start"`
+	End      time.Time            `json:"end" validate:"omitempty,gtfield=Start"`
+	Ignored  string               `json:"ignored" validate:"-"`
+}
+
+func validUser() testValidateUser {
+	return testValidateUser{
+		Name:    "bob",
+		Address: testValidateAddress{Street: "Main"},
+	}
+}
+
+func TestStructValidator_Validate(t *testing.T) {
+	age := 17
+	var testCases = []struct {
+		name         string
+		givenUser    func(u *testValidateUser)
+		expectErrors []FieldError
+	}{
+		{
+			name:      "ok",
+			givenUser: func(u *testValidateUser) {},
+		},
+		{
+			name: "ok, all fields",
+			givenUser: func(u *testValidateUser) {
+				adult := 18
+				u.Email = "bob@example.com"
+				u.Website = "https://example.com"
+				u.ID = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+				u.Role = "admin"
+				u.Code = "abc"
+				u.Age = &adult
+				u.Address.Zip = "12345"
+				u.Items = []testValidateItem{{Name: "apple", Quantity: 1}}
+				u.Tags = []string{"go", "web"}
+				u.Password = "secret"
+				u.Confirm = "secret"
+				u.Start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+				u.End = u.Start.Add(time.Hour)
+			},
+		},
+		{
+			name: "nok, required and length",
+			givenUser: func(u *testValidateUser) {
+				u.Name = ""
+			},
+			expectErrors: []FieldError{
+				{Field: "name", Rule: "required", Message: "is required"},
+			},
+		},
+		{
+			name: "nok, string length counts characters",
+			givenUser: func(u *testValidateUser) {
+				u.Name = "ääääää"
+			},
+			expectErrors: []FieldError{
+				{Field: "name", Rule: "max", Param: "5", Message: "must be at most 5 characters long"},
+			},
+		},
+		{
+			name: "nok, formats",
+			givenUser: func(u *testValidateUser) {
+				u.Email = "Bob <bob@example.com>"
+				u.Website = "example.com"
+				u.ID = "6ba7b810"
+				u.Role = "root"
+				u.Code = "abcd"
+			},
+			expectErrors: []FieldError{
+				{Field: "email", Rule: "email", Message: "must be a valid email address"},
+				{Field: "website", Rule: "url", Message: "must be a valid URL"},
+				{Field: "id", Rule: "uuid", Message: "must be a valid UUID"},
+				{Field: "role", Rule: "oneof", Param: "admin user", Message: "must be one of: admin, user"},
+				{Field: "Code", Rule: "regexp", Param: "[a-z]{2,3}", Message: "must match pattern [a-z]{2,3}"},
+			},
+		},
+		{
+			name: "nok, pointer value",
+			givenUser: func(u *testValidateUser) {
+				u.Age = &age
+			},
+			expectErrors: []FieldError{
+				{Field: "age", Rule: "min", Param: "18", Message: "must be at least 18"},
+			},
+		},
+		{
+			name: "nok, nested structs",
+			givenUser: func(u *testValidateUser) {
+				u.Address.Zip = "123"
+				u.Manager = &testValidateUser{Name: "al"}
+			},
+			expectErrors: []FieldError{
+				{Field: "manager.address.street", Rule: "required", Message: "is required"},
+				{Field: "address.zip", Rule: "len", Param: "5", Message: "must be exactly 5 characters long"},
+			},
+		},
+		{
+			name: "nok, slices",
+			givenUser: func(u *testValidateUser) {
+				u.Items = []testValidateItem{{Name: "apple", Quantity: 1}, {Name: "pear"}}
+				u.Tags = []string{"go", "x"}
+			},
+			expectErrors: []FieldError{
+				{Field: "items[1].quantity", Rule: "min", Param: "1", Message: "must be at least 1"},
+				{Field: "tags[1]", Rule: "min", Param: "2", Message: "must be at least 2 characters long"},
+			},
+		},
+		{
+			name: "nok, slice length",
+			givenUser: func(u *testValidateUser) {
+				u.Items = []testValidateItem{{Name: "a", Quantity: 1}, {Name: "b", Quantity: 1}, {Name: "c", Quantity: 1}}
+			},
+			expectErrors: []FieldError{
+				{Field: "items", Rule: "max", Param: "2", Message: "must contain at most 2 items"},
+			},
+		},
+		{
+			name: "nok, cross-field",
+			givenUser: func(u *testValidateUser) {
+				u.Password = "secret"
+				u.Confirm = "secrets"
+				u.Start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+				u.End = u.Start.Add(-time.Hour)
+			},
+			expectErrors: []FieldError{
+				{Field: "confirm", Rule: "eqfield", Param: "Password", Message: "must be equal to password"},
+				{Field: "end", Rule: "gtfield", Param: "Start", Message: "must be greater than start"},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u := validUser()
+			tc.givenUser(&u)
+
+			err := NewStructValidator().Validate(&u)
+			if tc.expectErrors == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var he *HTTPError
+			assert.ErrorAs(t, err, &he)
+			assert.Equal(t, http.StatusBadRequest, he.Code)
+			var ve *ValidationError
+			assert.ErrorAs(t, err, &ve)
+			assert.Equal(t, tc.expectErrors, ve.Errors)
+		})
+	}
+}
+
+func TestStructValidator_ValidateSliceOfStructs(t *testing.T) {
+	err := NewStructValidator().Validate([]testValidateItem{{Name: "a", Quantity: 1}, {Quantity: 1}})
+
+	var ve *ValidationError
+	assert.ErrorAs(t, err, &ve)
+	assert.Equal(t, []FieldError{{Field: "[1].name", Rule: "required", Message: "is required"}}, ve.Errors)
+	assert.NoError(t, NewStructValidator().Validate("not a struct"))
+	assert.NoError(t, NewStructValidator().Validate(nil))
+}
+
+type testValidateTree map[string]testValidateTree
+
+type testValidateList []testValidateList
+
+type testValidateNode struct {
+	Name string            `json:"name" validate:"required"`
+	Next *testValidateNode `json:"next"`
+}
+
+func TestStructValidator_RecursiveTypes(t *testing.T) {
+	type request struct {
+		Name string           `json:"name" validate:"required"`
+		Tree testValidateTree `json:"tree"`
+		List testValidateList `json:"list"`
+	}
+	req := request{
+		Tree: testValidateTree{"a": {"b": nil}},
+		List: testValidateList{{}, {{}}},
+	}
+
+	err := (&StructValidator{}).Validate(&req)
+
+	var ve *ValidationError
+	assert.ErrorAs(t, err, &ve)
+	assert.Equal(t, []FieldError{{Field: "name", Rule: "required", Message: "is required"}}, ve.Errors)
+}
+
+func TestStructValidator_CyclicPointers(t *testing.T) {
+	node := &testValidateNode{Name: "a"}
+	node.Next = node
+
+	err := NewStructValidator().Validate(node)
+
+	assert.ErrorContains(t, err, "exceeds maximum nesting depth of 1000")
+	assert.False(t, errors.As(err, new(*ValidationError)))
+}
+
+func TestStructValidator_RegisterRule(t *testing.T) {
+	type request struct {
+		Count int `json:"count" validate:"divisible=3"`
+	}
+	v := NewStructValidator()
+	v.RegisterRule("divisible", func(value reflect.Value, param string) bool {
+		return value.CanInt() && param == "3" && value.Int()%3 == 0
+	})
+
+	assert.NoError(t, v.Validate(request{Count: 9}))
+
+	var ve *ValidationError
+	assert.ErrorAs(t, v.Validate(request{Count: 10}), &ve)
+	assert.Equal(t, []FieldError{
+		{Field: "count", Rule: "divisible", Param: "3", Message: "failed divisible validation"},
+	}, ve.Errors)
+}
+
+func TestStructValidator_InvalidRule(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		given       interface{}
+		expectError string
+	}{
+		{
+			name: "unknown rule",
+			given: struct {
+				Name string `validate:"required,uppercase"`
+			}{},
+			expectError: `echo: invalid validation rule "uppercase" of field .Name: unknown rule "uppercase"`,
+		},
+		{
+			name: "invalid number",
+			given: struct {
+				Name string `validate:"min=x"`
+			}{},
+			expectError: `echo: invalid validation rule "min=x" of field .Name: strconv.ParseFloat: parsing "x": invalid syntax`,
+		},
+		{
+			name: "unknown field",
+			given: struct {
+				Name string `validate:"eqfield=Missing"`
+			}{},
+			expectError: `echo: invalid validation rule "eqfield=Missing" of field .Name: no field "Missing"`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := NewStructValidator().Validate(tc.given)
+			assert.EqualError(t, err, tc.expectError)
+			assert.False(t, errors.As(err, new(*ValidationError)))
+		})
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	ve := &ValidationError{Errors: []FieldError{
+		{Field: "name", Rule: "required", Message: "is required"},
+		{Field: "items[0].quantity", Rule: "min", Param: "1", Message: "must be at least 1"},
+	}}
+
+	assert.Equal(t, "validation failed: name is required; items[0].quantity must be at least 1", ve.Error())
+	assert.Equal(t, []InvalidParam{
+		{Name: "name", Reason: "is required"},
+		{Name: "items[0].quantity", Reason: "must be at least 1"},
+	}, ve.InvalidParams())
+}
+
+func TestStructValidator_ProblemDetails(t *testing.T) {
+	type request struct {
+		Name string `json:"name" validate:"required"`
+	}
+	e := New()
+	e.Validator = NewStructValidator()
+	e.HTTPErrorHandler = e.ProblemDetailsHTTPErrorHandler
+	e.POST("/", func(c Context) error {
+		var req request
+		if err := c.Bind(&req); err != nil {
+			return err
+		}
+		return c.Validate(&req)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+	req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.JSONEq(t, `{
+		"title": "Bad Request",
+		"status": 400,
+		"detail": "validation failed: name is required",
+		"invalid-params": [{"name": "name", "reason": "is required"}]
+	}`, rec.Body.String())
+}
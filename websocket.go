@@ -0,0 +1,951 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+)
+
+// WebSocketMessageType is type of WebSocket data message.
+type WebSocketMessageType int
+
+// WebSocket data message types. See https://datatracker.ietf.org/doc/html/rfc6455#section-5.6
+const (
+	WebSocketTextMessage   WebSocketMessageType = 1
+	WebSocketBinaryMessage WebSocketMessageType = 2
+)
+
+// WebSocket close status codes. See https://datatracker.ietf.org/doc/html/rfc6455#section-7.4.1
+const (
+	WebSocketCloseNormalClosure           = 1000
+	WebSocketCloseGoingAway               = 1001
+	WebSocketCloseProtocolError           = 1002
+	WebSocketCloseUnsupportedData         = 1003
+	WebSocketCloseNoStatusReceived        = 1005
+	WebSocketCloseAbnormalClosure         = 1006
+	WebSocketCloseInvalidFramePayloadData = 1007
+	WebSocketClosePolicyViolation         = 1008
+	WebSocketCloseMessageTooBig           = 1009
+	WebSocketCloseMandatoryExtension      = 1010
+	WebSocketCloseInternalServerErr       = 1011
+)
+
+const (
+	// DefaultWebSocketReadLimit is default maximum size of received message in bytes.
+	DefaultWebSocketReadLimit = 1 << 20
+	// DefaultWebSocketWriteBufferSize is default maximum payload size of a single frame written to the connection.
+	DefaultWebSocketWriteBufferSize = 4096
+	// DefaultWebSocketCloseTimeout is default time to wait for peer to respond to close frame.
+	DefaultWebSocketCloseTimeout = 5 * time.Second
+
+	webSocketGUID          = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	webSocketDeflate       = "permessage-deflate"
+	webSocketMaxControl    = 125
+	webSocketMaxReasonSize = webSocketMaxControl - 2
+
+	wsOpContinuation = 0x0
+	wsOpText         = 0x1
+	wsOpBinary       = 0x2
+	wsOpClose        = 0x8
+	wsOpPing         = 0x9
+	wsOpPong         = 0xa
+)
+
+var (
+	// ErrWebSocketClosed is returned when writing to WebSocket connection after close frame has been sent.
+	ErrWebSocketClosed = errors.New("websocket: connection closed")
+	// ErrWebSocketReadLimit is returned when received message is larger than the read limit.
+	ErrWebSocketReadLimit = errors.New("websocket: read limit exceeded")
+
+	// flate stream end markers that permessage-deflate sender strips from message. See RFC 7692 section 7.2.2
+	webSocketDeflateTail = []byte("\x00\x00\xff\xff\x01\x00\x00\xff\xff")
+
+	flateReaderPool = sync.Pool{New: func() interface{} {
+		return flate.NewReader(nil)
+	}}
+	flateWriterPools [flate.BestCompression - flate.HuffmanOnly + 1]sync.Pool
+)
+
+// WebSocketCloseError is returned by WebSocket read methods when peer has closed the connection.
+type WebSocketCloseError struct {
+	// Code is close status code sent by the peer. WebSocketCloseNoStatusReceived when close frame had no status.
+	Code int
+	// Reason is close reason sent by the peer.
+	Reason string
+}
+
+// Error makes it compatible with `error` interface.
+func (ce *WebSocketCloseError) Error() string {
+	return fmt.Sprintf("websocket: close code=%d, reason=%s", ce.Code, ce.Reason)
+}
+
+// WebSocketUpgrader upgrades HTTP connections to WebSocket protocol (RFC 6455). Zero value is ready to use.
+// WebSocketUpgrader must not be copied after first use.
+type WebSocketUpgrader struct {
+	// Subprotocols lists subprotocols supported by the server in order of preference. First of them that client
+	// offers in `Sec-WebSocket-Protocol` header is selected.
+	Subprotocols []string
+
+	// AllowOrigins lists origins that are allowed to open the connection. Uses same rules as `AllowOrigins` in the
+	// CORS middleware (see `OriginMatcher`). When AllowOrigins and AllowOriginFunc are empty, only requests where
+	// `Origin` header matches request host are allowed.
+	// Requests without `Origin` header (non-browser clients) are always allowed.
+	AllowOrigins []string
+
+	// AllowOriginFunc is a custom function to validate the origin. If this option is set, AllowOrigins is ignored.
+	AllowOriginFunc func(origin string) (bool, error)
+
+	// ReadLimit is maximum size of received message in bytes (after decompression). Connection is closed with
+	// WebSocketCloseMessageTooBig status when message is larger.
+	// Optional. Default value DefaultWebSocketReadLimit.
+	ReadLimit int64
+
+	// WriteBufferSize is maximum payload size of a single frame. Larger messages are sent fragmented.
+	// Optional. Default value DefaultWebSocketWriteBufferSize.
+	WriteBufferSize int
+
+	// EnableCompression enables negotiation of permessage-deflate extension (RFC 7692).
+	EnableCompression bool
+
+	// CompressionLevel is flate compression level used when compression is negotiated. Nil means the level is not
+	// set, which allows selecting `flate.NoCompression` (zero).
+	// Optional. Default value -1 (flate.DefaultCompression).
+	CompressionLevel *int
+
+	// CloseTimeout is time to wait for peer to respond to close frame before connection is closed.
+	// Optional. Default value DefaultWebSocketCloseTimeout.
+	CloseTimeout time.Duration
+
+	originOnce    sync.Once
+	originMatcher *OriginMatcher
+}
+
+// WebSocket is server side WebSocket connection. Reading methods must not be called concurrently. Writing methods
+// may be called concurrently with reading and other writing methods, messages are written one at a time.
+type WebSocket struct {
+	conn         net.Conn
+	reader       *bufio.Reader
+	subprotocol  string
+	compression  bool
+	level        int
+	writeSize    int
+	closeTimeout time.Duration
+
+	readLock      sync.Mutex
+	readLimit     int64
+	readErr       error
+	pongHandler   func(data []byte)
+	closeReceived atomic.Bool
+
+	messageLock sync.Mutex // held for duration of writing whole message
+	frameLock   sync.Mutex // held for duration of writing single frame
+	closeSent   bool
+	goingAway   bool
+}
+
+var defaultWebSocketUpgrader = &WebSocketUpgrader{}
+
+func (c *context) WebSocket(fn func(ws *WebSocket) error) error {
+	return defaultWebSocketUpgrader.Upgrade(c, fn)
+}
+
+// Upgrade performs WebSocket handshake, hijacks the connection and calls fn to communicate over it. When fn returns,
+// normal closure (or internal error closure when fn returned an error) is sent to the peer and connection is closed.
+//
+// Handshake errors are returned as `*HTTPError` before the connection is hijacked. Errors returned by fn are returned
+// except `*WebSocketCloseError` that signals that peer closed the connection.
+//
+// Example:
+//
+//	e.GET("/ws", func(c echo.Context) error {
+//		return c.WebSocket(func(ws *echo.WebSocket) error {
+//			for {
+//				mt, msg, err := ws.ReadMessage()
+//				if err != nil {
+//					return err
+//				}
+//				if err := ws.WriteMessage(mt, msg); err != nil {
+//					return err
+//				}
+//			}
+//		})
+//	})
+func (u *WebSocketUpgrader) Upgrade(c Context, fn func(ws *WebSocket) error) error {
+	req := c.Request()
+	if req.Method != http.MethodGet {
+		return NewHTTPError(http.StatusMethodNotAllowed, "websocket: request method is not GET")
+	}
+	if !headerContainsToken(req.Header, HeaderConnection, "upgrade") ||
+		!headerContainsToken(req.Header, HeaderUpgrade, "websocket") {
+		return NewHTTPError(http.StatusBadRequest, "websocket: not a websocket handshake")
+	}
+	if req.Header.Get(HeaderSecWebSocketVersion) != "13" {
+		c.Response().Header().Set(HeaderSecWebSocketVersion, "13")
+		return NewHTTPError(http.StatusUpgradeRequired, "websocket: unsupported version")
+	}
+	key := req.Header.Get(HeaderSecWebSocketKey)
+	if decoded, err := base64.StdEncoding.DecodeString(key); err != nil || len(decoded) != 16 {
+		return NewHTTPError(http.StatusBadRequest, "websocket: invalid Sec-WebSocket-Key header")
+	}
+	allowed, err := u.checkOrigin(req)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return NewHTTPError(http.StatusForbidden, "websocket: origin not allowed")
+	}
+
+	ws := &WebSocket{
+		subprotocol:  u.selectSubprotocol(req.Header),
+		compression:  u.EnableCompression && acceptDeflateOffer(req.Header),
+		level:        flate.DefaultCompression,
+		writeSize:    u.WriteBufferSize,
+		closeTimeout: u.CloseTimeout,
+		readLimit:    u.ReadLimit,
+	}
+	if l := u.CompressionLevel; l != nil && *l >= flate.HuffmanOnly && *l <= flate.BestCompression {
+		ws.level = *l
+	}
+	if ws.writeSize <= 0 {
+		ws.writeSize = DefaultWebSocketWriteBufferSize
+	}
+	if ws.closeTimeout <= 0 {
+		ws.closeTimeout = DefaultWebSocketCloseTimeout
+	}
+	if ws.readLimit <= 0 {
+		ws.readLimit = DefaultWebSocketReadLimit
+	}
+
+	e := c.Echo()
+	if !e.webSockets.add(ws) {
+		return ErrServiceUnavailable
+	}
+	defer e.webSockets.remove(ws)
+
+	res := c.Response()
+	conn, brw, err := res.Hijack()
+	if err != nil {
+		return err
+	}
+	// clear deadlines that http.Server could have set for the request
+	_ = conn.SetDeadline(time.Time{})
+
+	header := res.Header().Clone()
+	header.Set(HeaderUpgrade, "websocket")
+	header.Set(HeaderConnection, "Upgrade")
+	header.Set(HeaderSecWebSocketAccept, webSocketAcceptKey(key))
+	if ws.subprotocol != "" {
+		header.Set(HeaderSecWebSocketProtocol, ws.subprotocol)
+	}
+	if ws.compression {
+		header.Set(HeaderSecWebSocketExtensions, webSocketDeflate+"; server_no_context_takeover; client_no_context_takeover")
+	}
+	buf := new(bytes.Buffer)
+	buf.WriteString("HTTP/1.1 101 Switching Protocols\r\n")
+	_ = header.Write(buf)
+	buf.WriteString("\r\n")
+
+	ws.frameLock.Lock()
+	ws.conn = conn
+	ws.reader = brw.Reader
+	_, err = conn.Write(buf.Bytes())
+	goingAway := ws.goingAway
+	ws.frameLock.Unlock()
+	if goingAway {
+		ws.goAway()
+	}
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	res.Status = http.StatusSwitchingProtocols
+	res.Committed = true
+
+	err = fn(ws)
+	ws.finish(err)
+
+	var ce *WebSocketCloseError
+	if errors.As(err, &ce) {
+		return nil
+	}
+	return err
+}
+
+func (u *WebSocketUpgrader) checkOrigin(r *http.Request) (bool, error) {
+	origin := r.Header.Get(HeaderOrigin)
+	if origin == "" {
+		return true, nil
+	}
+	if u.AllowOriginFunc != nil {
+		return u.AllowOriginFunc(origin)
+	}
+	if len(u.AllowOrigins) > 0 {
+		u.originOnce.Do(func() {
+			u.originMatcher = NewOriginMatcher(u.AllowOrigins)
+		})
+		return u.originMatcher.Match(origin) != "", nil
+	}
+	o, err := url.Parse(origin)
+	if err != nil {
+		return false, nil
+	}
+	return strings.EqualFold(o.Host, r.Host), nil
+}
+
+func (u *WebSocketUpgrader) selectSubprotocol(header http.Header) string {
+	offered := headerTokens(header, HeaderSecWebSocketProtocol)
+	for _, p := range u.Subprotocols {
+		for _, o := range offered {
+			if p == o {
+				return p
+			}
+		}
+	}
+	return ""
+}
+
+// acceptDeflateOffer checks if client offered permessage-deflate extension with parameters that server can accept.
+// Server always disables context takeover so it does not need to keep compression state between messages.
+func acceptDeflateOffer(header http.Header) bool {
+	for _, offer := range headerTokens(header, HeaderSecWebSocketExtensions) {
+		params := strings.Split(offer, ";")
+		if !strings.EqualFold(strings.TrimSpace(params[0]), webSocketDeflate) {
+			continue
+		}
+		ok := true
+		for _, p := range params[1:] {
+			name, value, _ := strings.Cut(strings.TrimSpace(p), "=")
+			value = strings.Trim(strings.TrimSpace(value), `"`)
+			switch strings.ToLower(strings.TrimSpace(name)) {
+			case "server_no_context_takeover", "client_no_context_takeover", "client_max_window_bits":
+			case "server_max_window_bits":
+				// Go flate always uses 32KB window, smaller windows can not be honoured
+				ok = value == "15"
+			default:
+				ok = false
+			}
+			if !ok {
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+func webSocketAcceptKey(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key))
+	h.Write([]byte(webSocketGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+func headerTokens(header http.Header, name string) []string {
+	var tokens []string
+	for _, v := range header.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				tokens = append(tokens, t)
+			}
+		}
+	}
+	return tokens
+}
+
+func headerContainsToken(header http.Header, name string, token string) bool {
+	for _, t := range headerTokens(header, name) {
+		if strings.EqualFold(t, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// Subprotocol returns negotiated subprotocol. Empty when no subprotocol was negotiated.
+func (ws *WebSocket) Subprotocol() string {
+	return ws.subprotocol
+}
+
+// Compressed returns true when permessage-deflate extension was negotiated for the connection.
+func (ws *WebSocket) Compressed() bool {
+	return ws.compression
+}
+
+// RemoteAddr returns remote network address of the connection.
+func (ws *WebSocket) RemoteAddr() net.Addr {
+	return ws.conn.RemoteAddr()
+}
+
+// SetReadLimit sets maximum size of received message in bytes.
+func (ws *WebSocket) SetReadLimit(limit int64) {
+	ws.readLock.Lock()
+	defer ws.readLock.Unlock()
+	ws.readLimit = limit
+}
+
+// SetReadDeadline sets deadline for reading from the connection. After deadline has passed reads return error.
+func (ws *WebSocket) SetReadDeadline(t time.Time) error {
+	return ws.conn.SetReadDeadline(t)
+}
+
+// SetWriteDeadline sets deadline for writing to the connection. After deadline has passed writes return error.
+func (ws *WebSocket) SetWriteDeadline(t time.Time) error {
+	return ws.conn.SetWriteDeadline(t)
+}
+
+// SetPongHandler sets function that is called with payload of received pong frames. Handler is called from
+// ReadMessage goroutine.
+func (ws *WebSocket) SetPongHandler(fn func(data []byte)) {
+	ws.readLock.Lock()
+	defer ws.readLock.Unlock()
+	ws.pongHandler = fn
+}
+
+// Ping sends ping frame to the peer. Peer response is received by pong handler. Payload must not exceed 125 bytes.
+func (ws *WebSocket) Ping(data []byte) error {
+	if len(data) > webSocketMaxControl {
+		return errors.New("websocket: control frame payload too large")
+	}
+	return ws.writeFrame(wsOpPing, false, true, data)
+}
+
+// Close sends close frame with given status code and reason to the peer. Further writes return ErrWebSocketClosed.
+// Read methods return `*WebSocketCloseError` when peer responds to close frame.
+func (ws *WebSocket) Close(code int, reason string) error {
+	if len(reason) > webSocketMaxReasonSize {
+		return errors.New("websocket: close reason too long")
+	}
+	return ws.writeClose(code, reason)
+}
+
+// ReadMessage reads next data message from the connection. Fragmented messages are reassembled and compressed
+// messages decompressed. Ping frames received while reading are answered with pong frames.
+//
+// Returns `*WebSocketCloseError` when peer closed the connection. Protocol violations by the peer close the connection
+// with appropriate close status code. After an error is returned all following reads return the same error.
+func (ws *WebSocket) ReadMessage() (WebSocketMessageType, []byte, error) {
+	ws.readLock.Lock()
+	defer ws.readLock.Unlock()
+	if ws.readErr != nil {
+		return 0, nil, ws.readErr
+	}
+	mt, p, err := ws.readMessage()
+	if err != nil {
+		ws.readErr = err
+	}
+	return mt, p, err
+}
+
+// WriteMessage writes data message to the connection. Messages larger than write buffer size are fragmented.
+func (ws *WebSocket) WriteMessage(mt WebSocketMessageType, data []byte) error {
+	w, err := ws.NextWriter(mt)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// NextWriter returns writer for the next data message. Written data is sent in fragments as write buffer fills up.
+// Message is finished with Close. Other messages can not be written until the writer is closed.
+func (ws *WebSocket) NextWriter(mt WebSocketMessageType) (io.WriteCloser, error) {
+	if mt != WebSocketTextMessage && mt != WebSocketBinaryMessage {
+		return nil, fmt.Errorf("websocket: invalid message type %d", mt)
+	}
+	ws.messageLock.Lock()
+	w := &webSocketMessageWriter{
+		ws:     ws,
+		opcode: byte(mt),
+		rsv1:   ws.compression,
+		buf:    make([]byte, 0, ws.writeSize),
+	}
+	if ws.compression {
+		w.flate = getFlateWriter(ws.level, &webSocketTailTrimmer{w: w})
+	}
+	return w, nil
+}
+
+func (ws *WebSocket) readMessage() (WebSocketMessageType, []byte, error) {
+	var (
+		opcode     byte
+		compressed bool
+		payload    []byte
+	)
+	for {
+		h, err := ws.readFrameHeader()
+		if err != nil {
+			return 0, nil, err
+		}
+		if h.opcode >= wsOpClose {
+			if err := ws.readControlFrame(h); err != nil {
+				return 0, nil, err
+			}
+			continue
+		}
+
+		if opcode == 0 {
+			if h.opcode == wsOpContinuation {
+				return 0, nil, ws.fail(WebSocketCloseProtocolError, "unexpected continuation frame")
+			}
+			if h.rsv1 && !ws.compression {
+				return 0, nil, ws.fail(WebSocketCloseProtocolError, "unexpected RSV1 bit")
+			}
+			opcode = h.opcode
+			compressed = h.rsv1
+		} else if h.opcode != wsOpContinuation {
+			return 0, nil, ws.fail(WebSocketCloseProtocolError, "expected continuation frame")
+		} else if h.rsv1 {
+			return 0, nil, ws.fail(WebSocketCloseProtocolError, "unexpected RSV1 bit on continuation frame")
+		}
+
+		if uint64(len(payload))+h.length > uint64(ws.readLimit) {
+			ws.fail(WebSocketCloseMessageTooBig, "")
+			return 0, nil, ErrWebSocketReadLimit
+		}
+		start := len(payload)
+		payload = append(payload, make([]byte, h.length)...)
+		if _, err := io.ReadFull(ws.reader, payload[start:]); err != nil {
+			return 0, nil, err
+		}
+		maskBytes(h.mask, payload[start:])
+
+		if h.fin {
+			break
+		}
+	}
+
+	if compressed {
+		var err error
+		if payload, err = ws.decompress(payload); err != nil {
+			return 0, nil, err
+		}
+	}
+	if opcode == wsOpText && !utf8.Valid(payload) {
+		return 0, nil, ws.fail(WebSocketCloseInvalidFramePayloadData, "invalid UTF-8 in text message")
+	}
+	return WebSocketMessageType(opcode), payload, nil
+}
+
+type webSocketFrameHeader struct {
+	fin    bool
+	rsv1   bool
+	opcode byte
+	length uint64
+	mask   [4]byte
+}
+
+func (ws *WebSocket) readFrameHeader() (webSocketFrameHeader, error) {
+	h := webSocketFrameHeader{}
+	var b [8]byte
+	if _, err := io.ReadFull(ws.reader, b[:2]); err != nil {
+		return h, err
+	}
+	h.fin = b[0]&0x80 != 0
+	h.rsv1 = b[0]&0x40 != 0
+	h.opcode = b[0] & 0x0f
+	masked := b[1]&0x80 != 0
+	h.length = uint64(b[1] & 0x7f)
+
+	if b[0]&0x30 != 0 {
+		return h, ws.fail(WebSocketCloseProtocolError, "unexpected RSV2 or RSV3 bit")
+	}
+	switch h.opcode {
+	case wsOpContinuation, wsOpText, wsOpBinary:
+	case wsOpClose, wsOpPing, wsOpPong:
+		if !h.fin || h.rsv1 || h.length > webSocketMaxControl {
+			return h, ws.fail(WebSocketCloseProtocolError, "invalid control frame")
+		}
+	default:
+		return h, ws.fail(WebSocketCloseProtocolError, fmt.Sprintf("unknown opcode %d", h.opcode))
+	}
+	if !masked {
+		return h, ws.fail(WebSocketCloseProtocolError, "client frame is not masked")
+	}
+
+	switch h.length {
+	case 126:
+		if _, err := io.ReadFull(ws.reader, b[:2]); err != nil {
+			return h, err
+		}
+		h.length = uint64(binary.BigEndian.Uint16(b[:2]))
+	case 127:
+		if _, err := io.ReadFull(ws.reader, b[:8]); err != nil {
+			return h, err
+		}
+		h.length = binary.BigEndian.Uint64(b[:8])
+		if h.length>>63 != 0 {
+			return h, ws.fail(WebSocketCloseProtocolError, "invalid payload length")
+		}
+	}
+	if _, err := io.ReadFull(ws.reader, h.mask[:]); err != nil {
+		return h, err
+	}
+	return h, nil
+}
+
+func (ws *WebSocket) readControlFrame(h webSocketFrameHeader) error {
+	payload := make([]byte, h.length)
+	if _, err := io.ReadFull(ws.reader, payload); err != nil {
+		return err
+	}
+	maskBytes(h.mask, payload)
+
+	switch h.opcode {
+	case wsOpPing:
+		if err := ws.writeFrame(wsOpPong, false, true, payload); err != nil && !errors.Is(err, ErrWebSocketClosed) {
+			return err
+		}
+	case wsOpPong:
+		if ws.pongHandler != nil {
+			ws.pongHandler(payload)
+		}
+	case wsOpClose:
+		ce := &WebSocketCloseError{Code: WebSocketCloseNoStatusReceived}
+		switch {
+		case len(payload) == 1:
+			return ws.fail(WebSocketCloseProtocolError, "invalid close frame payload")
+		case len(payload) >= 2:
+			ce.Code = int(binary.BigEndian.Uint16(payload))
+			ce.Reason = string(payload[2:])
+			if !isValidReceivedCloseCode(ce.Code) {
+				return ws.fail(WebSocketCloseProtocolError, "invalid close status code")
+			}
+			if !utf8.ValidString(ce.Reason) {
+				return ws.fail(WebSocketCloseInvalidFramePayloadData, "invalid UTF-8 in close reason")
+			}
+		}
+		ws.closeReceived.Store(true)
+		_ = ws.writeClose(ce.Code, "")
+		return ce
+	}
+	return nil
+}
+
+func (ws *WebSocket) decompress(payload []byte) ([]byte, error) {
+	fr := flateReaderPool.Get().(io.ReadCloser)
+	defer flateReaderPool.Put(fr)
+	if err := fr.(flate.Resetter).Reset(io.MultiReader(bytes.NewReader(payload), bytes.NewReader(webSocketDeflateTail)), nil); err != nil {
+		return nil, err
+	}
+	b, err := io.ReadAll(io.LimitReader(fr, ws.readLimit+1))
+	if err != nil {
+		return nil, ws.fail(WebSocketCloseInvalidFramePayloadData, "invalid compressed message")
+	}
+	if int64(len(b)) > ws.readLimit {
+		ws.fail(WebSocketCloseMessageTooBig, "")
+		return nil, ErrWebSocketReadLimit
+	}
+	return b, nil
+}
+
+// fail sends close frame for protocol violation by the peer and returns error describing the violation.
+func (ws *WebSocket) fail(code int, reason string) error {
+	_ = ws.writeClose(code, reason)
+	return fmt.Errorf("websocket: %s (close code=%d)", reason, code)
+}
+
+func (ws *WebSocket) writeClose(code int, reason string) error {
+	var payload []byte
+	if code != WebSocketCloseNoStatusReceived {
+		payload = make([]byte, 2, 2+len(reason))
+		binary.BigEndian.PutUint16(payload, uint16(code))
+		payload = append(payload, reason...)
+	}
+	err := ws.writeFrame(wsOpClose, false, true, payload)
+	if errors.Is(err, ErrWebSocketClosed) {
+		return nil
+	}
+	return err
+}
+
+func (ws *WebSocket) writeFrame(opcode byte, rsv1 bool, fin bool, payload []byte) error {
+	ws.frameLock.Lock()
+	defer ws.frameLock.Unlock()
+	if ws.closeSent {
+		return ErrWebSocketClosed
+	}
+	if opcode == wsOpClose {
+		ws.closeSent = true
+	}
+
+	var header [10]byte
+	header[0] = opcode
+	if fin {
+		header[0] |= 0x80
+	}
+	if rsv1 {
+		header[0] |= 0x40
+	}
+	n := 2
+	switch l := len(payload); {
+	case l <= 125:
+		header[1] = byte(l)
+	case l <= 0xffff:
+		header[1] = 126
+		binary.BigEndian.PutUint16(header[2:], uint16(l))
+		n += 2
+	default:
+		header[1] = 127
+		binary.BigEndian.PutUint64(header[2:], uint64(l))
+		n += 8
+	}
+	buffers := net.Buffers{header[:n], payload}
+	_, err := buffers.WriteTo(ws.conn)
+	return err
+}
+
+// finish completes closing handshake and closes the connection.
+func (ws *WebSocket) finish(err error) {
+	code := WebSocketCloseNormalClosure
+	var ce *WebSocketCloseError
+	if err != nil && !errors.As(err, &ce) {
+		code = WebSocketCloseInternalServerErr
+	}
+	_ = ws.writeClose(code, "")
+
+	// wait for peer to respond to our close frame unless some other goroutine is still reading
+	if !ws.closeReceived.Load() && ws.readLock.TryLock() {
+		_ = ws.conn.SetReadDeadline(time.Now().Add(ws.closeTimeout))
+		for ws.readErr == nil {
+			_, _, ws.readErr = ws.readMessage()
+		}
+		ws.readLock.Unlock()
+	}
+	_ = ws.conn.Close()
+}
+
+// goAway notifies peer that server is shutting down and limits time the connection is kept open.
+func (ws *WebSocket) goAway() {
+	ws.frameLock.Lock()
+	conn := ws.conn
+	if conn == nil {
+		ws.goingAway = true // handshake is not yet completed, Upgrade will call goAway after it
+	}
+	ws.frameLock.Unlock()
+	if conn == nil {
+		return
+	}
+	_ = ws.writeClose(WebSocketCloseGoingAway, "server shutting down")
+	_ = conn.SetReadDeadline(time.Now().Add(ws.closeTimeout))
+}
+
+func isValidReceivedCloseCode(code int) bool {
+	switch {
+	case code >= 1000 && code <= 1003, code >= 1007 && code <= 1014:
+		return true
+	case code >= 3000 && code <= 4999:
+		return true
+	}
+	return false
+}
+
+func maskBytes(mask [4]byte, b []byte) {
+	for i := range b {
+		b[i] ^= mask[i&3]
+	}
+}
+
+type webSocketMessageWriter struct {
+	ws     *WebSocket
+	opcode byte
+	rsv1   bool
+	buf    []byte
+	flate  *flate.Writer
+	closed bool
+	err    error
+}
+
+func (w *webSocketMessageWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, errors.New("websocket: write to closed message writer")
+	}
+	if w.err != nil {
+		return 0, w.err
+	}
+	if w.flate != nil {
+		n, err := w.flate.Write(p)
+		if err != nil {
+			w.err = err
+		}
+		return n, err
+	}
+	if err := w.writePayload(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (w *webSocketMessageWriter) writePayload(p []byte) error {
+	for len(p) > 0 {
+		if len(w.buf) == cap(w.buf) {
+			if err := w.flushFrame(false); err != nil {
+				return err
+			}
+		}
+		n := min(cap(w.buf)-len(w.buf), len(p))
+		w.buf = append(w.buf, p[:n]...)
+		p = p[n:]
+	}
+	return nil
+}
+
+func (w *webSocketMessageWriter) flushFrame(fin bool) error {
+	err := w.ws.writeFrame(w.opcode, w.rsv1, fin, w.buf)
+	w.opcode = wsOpContinuation
+	w.rsv1 = false
+	w.buf = w.buf[:0]
+	if err != nil {
+		w.err = err
+	}
+	return err
+}
+
+// Close writes final frame of the message.
+func (w *webSocketMessageWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	defer w.ws.messageLock.Unlock()
+
+	if w.flate != nil {
+		if err := w.flate.Flush(); err != nil && w.err == nil {
+			w.err = err
+		}
+		putFlateWriter(w.ws.level, w.flate)
+		w.flate = nil
+	}
+	if w.err != nil {
+		return w.err
+	}
+	return w.flushFrame(true)
+}
+
+// webSocketTailTrimmer passes compressed data to message writer except last 4 bytes. After flate flush these are
+// always 0x00 0x00 0xff 0xff and are removed from the message as RFC 7692 section 7.2.1 requires.
+type webSocketTailTrimmer struct {
+	w    *webSocketMessageWriter
+	tail [4]byte
+	n    int
+}
+
+func (t *webSocketTailTrimmer) Write(p []byte) (int, error) {
+	total := len(p)
+	if t.n+len(p) <= len(t.tail) {
+		t.n += copy(t.tail[t.n:], p)
+		return total, nil
+	}
+
+	emit := t.n + len(p) - len(t.tail)
+	fromTail := min(emit, t.n)
+	if err := t.w.writePayload(t.tail[:fromTail]); err != nil {
+		return 0, err
+	}
+	t.n = copy(t.tail[:], t.tail[fromTail:t.n])
+	emit -= fromTail
+
+	if err := t.w.writePayload(p[:emit]); err != nil {
+		return 0, err
+	}
+	t.n += copy(t.tail[t.n:], p[emit:])
+	return total, nil
+}
+
+func getFlateWriter(level int, w io.Writer) *flate.Writer {
+	pool := &flateWriterPools[level-flate.HuffmanOnly]
+	if fw, ok := pool.Get().(*flate.Writer); ok {
+		fw.Reset(w)
+		return fw
+	}
+	fw, _ := flate.NewWriter(w, level) // level is validated by Upgrade
+	return fw
+}
+
+func putFlateWriter(level int, fw *flate.Writer) {
+	fw.Reset(io.Discard)
+	flateWriterPools[level-flate.HuffmanOnly].Put(fw)
+}
+
+// webSocketTracker keeps track of open WebSocket connections so they can be notified when Echo is shutting down.
+type webSocketTracker struct {
+	lock         sync.Mutex
+	sockets      map[*WebSocket]struct{}
+	shuttingDown bool
+}
+
+// start allows new connections again after previous shutdown.
+func (t *webSocketTracker) start() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.shuttingDown = false
+}
+
+func (t *webSocketTracker) add(ws *WebSocket) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.shuttingDown {
+		return false
+	}
+	if t.sockets == nil {
+		t.sockets = map[*WebSocket]struct{}{}
+	}
+	t.sockets[ws] = struct{}{}
+	return true
+}
+
+func (t *webSocketTracker) remove(ws *WebSocket) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	delete(t.sockets, ws)
+}
+
+func (t *webSocketTracker) snapshot() []*WebSocket {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.shuttingDown = true
+	sockets := make([]*WebSocket, 0, len(t.sockets))
+	for ws := range t.sockets {
+		sockets = append(sockets, ws)
+	}
+	return sockets
+}
+
+// shutdown sends going away close frame to all open connections.
+func (t *webSocketTracker) shutdown() {
+	for _, ws := range t.snapshot() {
+		ws.goAway()
+	}
+}
+
+// close closes all open connections immediately.
+func (t *webSocketTracker) close() {
+	for _, ws := range t.snapshot() {
+		ws.frameLock.Lock()
+		if ws.conn != nil {
+			_ = ws.conn.Close()
+		}
+		ws.frameLock.Unlock()
+	}
+}
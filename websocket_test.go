@@ -0,0 +1,670 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	stdContext "context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testWSClient struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	res    *http.Response
+}
+
+type testWSFrame struct {
+	fin     bool
+	rsv1    bool
+	opcode  byte
+	payload []byte
+}
+
+func dialTestWebSocket(t *testing.T, serverURL string, header http.Header) *testWSClient {
+	t.Helper()
+	conn, err := net.Dial("tcp", strings.TrimPrefix(serverURL, "http://"))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	req, err := http.NewRequest(http.MethodGet, serverURL+"/ws", nil)
+	require.NoError(t, err)
+	req.Header.Set(HeaderConnection, "Upgrade")
+	req.Header.Set(HeaderUpgrade, "websocket")
+	req.Header.Set(HeaderSecWebSocketVersion, "13")
+	req.Header.Set(HeaderSecWebSocketKey, "dGhlIHNhbXBsZSBub25jZQ==")
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	require.NoError(t, req.Write(conn))
+
+	reader := bufio.NewReader(conn)
+	res, err := http.ReadResponse(reader, req)
+	require.NoError(t, err)
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+	return &testWSClient{conn: conn, reader: reader, res: res}
+}
+
+func (c *testWSClient) writeFrame(t *testing.T, f testWSFrame) {
+	t.Helper()
+	b0 := f.opcode
+	if f.fin {
+		b0 |= 0x80
+	}
+	if f.rsv1 {
+		b0 |= 0x40
+	}
+	buf := []byte{b0}
+	switch l := len(f.payload); {
+	case l <= 125:
+		buf = append(buf, 0x80|byte(l))
+	case l <= 0xffff:
+		buf = append(buf, 0x80|126)
+		buf = binary.BigEndian.AppendUint16(buf, uint16(l))
+	default:
+		buf = append(buf, 0x80|127)
+		buf = binary.BigEndian.AppendUint64(buf, uint64(l))
+	}
+	mask := [4]byte{1, 2, 3, 4}
+	buf = append(buf, mask[:]...)
+	payload := append([]byte(nil), f.payload...)
+	maskBytes(mask, payload)
+	buf = append(buf, payload...)
+	_, err := c.conn.Write(buf)
+	require.NoError(t, err)
+}
+
+func (c *testWSClient) writeMessage(t *testing.T, opcode byte, payload string) {
+	c.writeFrame(t, testWSFrame{fin: true, opcode: opcode, payload: []byte(payload)})
+}
+
+func (c *testWSClient) readFrame(t *testing.T) testWSFrame {
+	t.Helper()
+	var h [2]byte
+	_, err := io.ReadFull(c.reader, h[:])
+	require.NoError(t, err)
+	assert.Zero(t, h[1]&0x80, "server frames must not be masked")
+	length := uint64(h[1] & 0x7f)
+	switch length {
+	case 126:
+		var b [2]byte
+		_, err = io.ReadFull(c.reader, b[:])
+		length = uint64(binary.BigEndian.Uint16(b[:]))
+	case 127:
+		var b [8]byte
+		_, err = io.ReadFull(c.reader, b[:])
+		length = binary.BigEndian.Uint64(b[:])
+	}
+	require.NoError(t, err)
+	payload := make([]byte, length)
+	_, err = io.ReadFull(c.reader, payload)
+	require.NoError(t, err)
+	return testWSFrame{fin: h[0]&0x80 != 0, rsv1: h[0]&0x40 != 0, opcode: h[0] & 0x0f, payload: payload}
+}
+
+func (c *testWSClient) readClose(t *testing.T) (int, string) {
+	t.Helper()
+	f := c.readFrame(t)
+	require.Equal(t, byte(wsOpClose), f.opcode)
+	if len(f.payload) < 2 {
+		return WebSocketCloseNoStatusReceived, ""
+	}
+	return int(binary.BigEndian.Uint16(f.payload)), string(f.payload[2:])
+}
+
+func closePayload(code int, reason string) string {
+	return string(binary.BigEndian.AppendUint16(nil, uint16(code))) + reason
+}
+
+func newTestWebSocketServer(t *testing.T, upgrader *WebSocketUpgrader, fn func(ws *WebSocket) error) (*Echo, *httptest.Server, chan error) {
+	e := New()
+	result := make(chan error, 1)
+	e.GET("/ws", func(c Context) error {
+		err := upgrader.Upgrade(c, fn)
+		result <- err
+		return err
+	})
+	server := httptest.NewServer(e)
+	t.Cleanup(server.Close)
+	return e, server, result
+}
+
+func echoWebSocketMessages(ws *WebSocket) error {
+	for {
+		mt, msg, err := ws.ReadMessage()
+		if err != nil {
+			return err
+		}
+		if err := ws.WriteMessage(mt, msg); err != nil {
+			return err
+		}
+	}
+}
+
+func TestWebSocketAcceptKey(t *testing.T) {
+	// example from RFC 6455 section 1.3
+	assert.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", webSocketAcceptKey("dGhlIHNhbXBsZSBub25jZQ=="))
+}
+
+func TestWebSocketUpgrader_HandshakeErrors(t *testing.T) {
+	var testCases = []struct {
+		name          string
+		givenUpgrader *WebSocketUpgrader
+		whenMethod    string
+		whenHeader    map[string]string
+		expectCode    int
+		expectVersion string
+	}{
+		{
+			name:       "nok, method is not GET",
+			whenMethod: http.MethodPost,
+			expectCode: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "nok, missing upgrade header",
+			whenHeader: map[string]string{HeaderUpgrade: ""},
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			name:       "nok, missing connection upgrade token",
+			whenHeader: map[string]string{HeaderConnection: "keep-alive"},
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			name:          "nok, unsupported version",
+			whenHeader:    map[string]string{HeaderSecWebSocketVersion: "8"},
+			expectCode:    http.StatusUpgradeRequired,
+			expectVersion: "13",
+		},
+		{
+			name:       "nok, invalid key",
+			whenHeader: map[string]string{HeaderSecWebSocketKey: "short"},
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			name:       "nok, cross origin request",
+			whenHeader: map[string]string{HeaderOrigin: "https://evil.com"},
+			expectCode: http.StatusForbidden,
+		},
+		{
+			name:          "nok, origin is not in allowed origins",
+			givenUpgrader: &WebSocketUpgrader{AllowOrigins: []string{"https://*.example.com"}},
+			whenHeader:    map[string]string{HeaderOrigin: "https://example.org"},
+			expectCode:    http.StatusForbidden,
+		},
+		{
+			name: "nok, origin func denies",
+			givenUpgrader: &WebSocketUpgrader{AllowOriginFunc: func(origin string) (bool, error) {
+				return false, nil
+			}},
+			whenHeader: map[string]string{HeaderOrigin: "https://example.com"},
+			expectCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			upgrader := tc.givenUpgrader
+			if upgrader == nil {
+				upgrader = &WebSocketUpgrader{}
+			}
+			method := http.MethodGet
+			if tc.whenMethod != "" {
+				method = tc.whenMethod
+			}
+			req := httptest.NewRequest(method, "http://example.com/ws", nil)
+			req.Header.Set(HeaderConnection, "keep-alive, Upgrade")
+			req.Header.Set(HeaderUpgrade, "websocket")
+			req.Header.Set(HeaderSecWebSocketVersion, "13")
+			req.Header.Set(HeaderSecWebSocketKey, "dGhlIHNhbXBsZSBub25jZQ==")
+			for k, v := range tc.whenHeader {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			c := New().NewContext(req, rec)
+
+			called := false
+			err := upgrader.Upgrade(c, func(ws *WebSocket) error {
+				called = true
+				return nil
+			})
+
+			assert.False(t, called)
+			var he *HTTPError
+			if assert.ErrorAs(t, err, &he) {
+				assert.Equal(t, tc.expectCode, he.Code)
+			}
+			assert.Equal(t, tc.expectVersion, rec.Header().Get(HeaderSecWebSocketVersion))
+		})
+	}
+}
+
+func TestWebSocketUpgrader_checkOrigin(t *testing.T) {
+	var testCases = []struct {
+		name          string
+		givenUpgrader *WebSocketUpgrader
+		whenOrigin    string
+		expect        bool
+	}{
+		{
+			name:       "ok, no origin",
+			whenOrigin: "",
+			expect:     true,
+		},
+		{
+			name:       "ok, same origin",
+			whenOrigin: "https://example.com",
+			expect:     true,
+		},
+		{
+			name:          "ok, allowed origin with CORS rules",
+			givenUpgrader: &WebSocketUpgrader{AllowOrigins: []string{"https://*.example.org"}},
+			whenOrigin:    "https://app.example.org",
+			expect:        true,
+		},
+		{
+			name:          "ok, wildcard",
+			givenUpgrader: &WebSocketUpgrader{AllowOrigins: []string{"*"}},
+			whenOrigin:    "https://other.com",
+			expect:        true,
+		},
+		{
+			name:       "nok, different host",
+			whenOrigin: "https://example.com.evil.com",
+			expect:     false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			upgrader := tc.givenUpgrader
+			if upgrader == nil {
+				upgrader = &WebSocketUpgrader{}
+			}
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/ws", nil)
+			if tc.whenOrigin != "" {
+				req.Header.Set(HeaderOrigin, tc.whenOrigin)
+			}
+			ok, err := upgrader.checkOrigin(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, ok)
+		})
+	}
+}
+
+func TestWebSocket_Echo(t *testing.T) {
+	upgrader := &WebSocketUpgrader{Subprotocols: []string{"v2", "v1"}}
+	_, server, result := newTestWebSocketServer(t, upgrader, echoWebSocketMessages)
+
+	client := dialTestWebSocket(t, server.URL, http.Header{HeaderSecWebSocketProtocol: {"v1, v2"}})
+	assert.Equal(t, http.StatusSwitchingProtocols, client.res.StatusCode)
+	assert.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", client.res.Header.Get(HeaderSecWebSocketAccept))
+	assert.Equal(t, "v2", client.res.Header.Get(HeaderSecWebSocketProtocol))
+	assert.Equal(t, "", client.res.Header.Get(HeaderSecWebSocketExtensions))
+
+	client.writeMessage(t, wsOpText, "hello")
+	f := client.readFrame(t)
+	assert.Equal(t, testWSFrame{fin: true, opcode: wsOpText, payload: []byte("hello")}, f)
+
+	client.writeMessage(t, wsOpBinary, "\x00\x01")
+	f = client.readFrame(t)
+	assert.Equal(t, testWSFrame{fin: true, opcode: wsOpBinary, payload: []byte{0, 1}}, f)
+
+	// fragmented message with interleaved ping
+	client.writeFrame(t, testWSFrame{opcode: wsOpText, payload: []byte("hel")})
+	client.writeFrame(t, testWSFrame{fin: true, opcode: wsOpPing, payload: []byte("ping")})
+	client.writeFrame(t, testWSFrame{fin: true, opcode: wsOpContinuation, payload: []byte("lo")})
+
+	f = client.readFrame(t)
+	assert.Equal(t, testWSFrame{fin: true, opcode: wsOpPong, payload: []byte("ping")}, f)
+	f = client.readFrame(t)
+	assert.Equal(t, testWSFrame{fin: true, opcode: wsOpText, payload: []byte("hello")}, f)
+
+	client.writeMessage(t, wsOpClose, closePayload(WebSocketCloseGoingAway, "bye"))
+	code, reason := client.readClose(t)
+	assert.Equal(t, WebSocketCloseGoingAway, code)
+	assert.Equal(t, "", reason)
+
+	assert.NoError(t, <-result)
+}
+
+func TestWebSocket_WriteFragmented(t *testing.T) {
+	upgrader := &WebSocketUpgrader{WriteBufferSize: 4}
+	_, server, result := newTestWebSocketServer(t, upgrader, func(ws *WebSocket) error {
+		return ws.WriteMessage(WebSocketTextMessage, []byte("0123456789"))
+	})
+
+	client := dialTestWebSocket(t, server.URL, nil)
+	assert.Equal(t, testWSFrame{fin: false, opcode: wsOpText, payload: []byte("0123")}, client.readFrame(t))
+	assert.Equal(t, testWSFrame{fin: false, opcode: wsOpContinuation, payload: []byte("4567")}, client.readFrame(t))
+	assert.Equal(t, testWSFrame{fin: true, opcode: wsOpContinuation, payload: []byte("89")}, client.readFrame(t))
+
+	code, _ := client.readClose(t)
+	assert.Equal(t, WebSocketCloseNormalClosure, code)
+	client.writeMessage(t, wsOpClose, closePayload(WebSocketCloseNormalClosure, ""))
+
+	assert.NoError(t, <-result)
+}
+
+func TestWebSocket_ProtocolErrors(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		givenLimit  int64
+		whenFrames  []testWSFrame
+		whenRaw     []byte
+		expectCode  int
+		expectError string
+	}{
+		{
+			name:        "nok, unmasked frame",
+			whenRaw:     []byte{0x81, 0x01, 'a'},
+			expectCode:  WebSocketCloseProtocolError,
+			expectError: "websocket: client frame is not masked (close code=1002)",
+		},
+		{
+			name:        "nok, unknown opcode",
+			whenFrames:  []testWSFrame{{fin: true, opcode: 0x3}},
+			expectCode:  WebSocketCloseProtocolError,
+			expectError: "websocket: unknown opcode 3 (close code=1002)",
+		},
+		{
+			name:        "nok, unexpected continuation",
+			whenFrames:  []testWSFrame{{fin: true, opcode: wsOpContinuation, payload: []byte("x")}},
+			expectCode:  WebSocketCloseProtocolError,
+			expectError: "websocket: unexpected continuation frame (close code=1002)",
+		},
+		{
+			name: "nok, new message before previous is finished",
+			whenFrames: []testWSFrame{
+				{opcode: wsOpText, payload: []byte("x")},
+				{fin: true, opcode: wsOpText, payload: []byte("y")},
+			},
+			expectCode:  WebSocketCloseProtocolError,
+			expectError: "websocket: expected continuation frame (close code=1002)",
+		},
+		{
+			name:        "nok, fragmented control frame",
+			whenFrames:  []testWSFrame{{fin: false, opcode: wsOpPing}},
+			expectCode:  WebSocketCloseProtocolError,
+			expectError: "websocket: invalid control frame (close code=1002)",
+		},
+		{
+			name:        "nok, compressed without negotiation",
+			whenFrames:  []testWSFrame{{fin: true, rsv1: true, opcode: wsOpText, payload: []byte("x")}},
+			expectCode:  WebSocketCloseProtocolError,
+			expectError: "websocket: unexpected RSV1 bit (close code=1002)",
+		},
+		{
+			name:        "nok, invalid utf-8",
+			whenFrames:  []testWSFrame{{fin: true, opcode: wsOpText, payload: []byte{0xff, 0xfe}}},
+			expectCode:  WebSocketCloseInvalidFramePayloadData,
+			expectError: "websocket: invalid UTF-8 in text message (close code=1007)",
+		},
+		{
+			name:        "nok, invalid close code",
+			whenFrames:  []testWSFrame{{fin: true, opcode: wsOpClose, payload: []byte(closePayload(1005, ""))}},
+			expectCode:  WebSocketCloseProtocolError,
+			expectError: "websocket: invalid close status code (close code=1002)",
+		},
+		{
+			name:       "nok, read limit exceeded",
+			givenLimit: 4,
+			whenFrames: []testWSFrame{
+				{opcode: wsOpBinary, payload: []byte("abc")},
+				{fin: true, opcode: wsOpContinuation, payload: []byte("de")},
+			},
+			expectCode:  WebSocketCloseMessageTooBig,
+			expectError: ErrWebSocketReadLimit.Error(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			upgrader := &WebSocketUpgrader{ReadLimit: tc.givenLimit, CloseTimeout: 100 * time.Millisecond}
+			_, server, result := newTestWebSocketServer(t, upgrader, func(ws *WebSocket) error {
+				_, _, err := ws.ReadMessage()
+				return err
+			})
+
+			client := dialTestWebSocket(t, server.URL, nil)
+			for _, f := range tc.whenFrames {
+				client.writeFrame(t, f)
+			}
+			if tc.whenRaw != nil {
+				_, err := client.conn.Write(tc.whenRaw)
+				require.NoError(t, err)
+			}
+
+			code, _ := client.readClose(t)
+			assert.Equal(t, tc.expectCode, code)
+			assert.EqualError(t, <-result, tc.expectError)
+		})
+	}
+}
+
+func TestWebSocket_CloseWithoutStatus(t *testing.T) {
+	var readErr error
+	_, server, result := newTestWebSocketServer(t, &WebSocketUpgrader{}, func(ws *WebSocket) error {
+		_, _, readErr = ws.ReadMessage()
+		return readErr
+	})
+
+	client := dialTestWebSocket(t, server.URL, nil)
+	client.writeFrame(t, testWSFrame{fin: true, opcode: wsOpClose})
+
+	code, _ := client.readClose(t)
+	assert.Equal(t, WebSocketCloseNoStatusReceived, code)
+	assert.NoError(t, <-result)
+	assert.Equal(t, &WebSocketCloseError{Code: WebSocketCloseNoStatusReceived}, readErr)
+}
+
+func TestWebSocket_Compression(t *testing.T) {
+	upgrader := &WebSocketUpgrader{EnableCompression: true}
+	_, server, result := newTestWebSocketServer(t, upgrader, echoWebSocketMessages)
+
+	client := dialTestWebSocket(t, server.URL, http.Header{
+		HeaderSecWebSocketExtensions: {"permessage-deflate; server_max_window_bits=10, permessage-deflate; client_max_window_bits"},
+	})
+	assert.Equal(t, "permessage-deflate; server_no_context_takeover; client_no_context_takeover",
+		client.res.Header.Get(HeaderSecWebSocketExtensions))
+
+	message := strings.Repeat("hello websocket ", 100)
+	compressed := new(bytes.Buffer)
+	fw, _ := flate.NewWriter(compressed, flate.BestSpeed)
+	fw.Write([]byte(message))
+	fw.Flush()
+	payload := bytes.TrimSuffix(compressed.Bytes(), []byte{0x00, 0x00, 0xff, 0xff})
+
+	// compressed message sent in two fragments, only first has RSV1 bit set
+	client.writeFrame(t, testWSFrame{rsv1: true, opcode: wsOpText, payload: payload[:5]})
+	client.writeFrame(t, testWSFrame{fin: true, opcode: wsOpContinuation, payload: payload[5:]})
+
+	var received []byte
+	first := true
+	for {
+		f := client.readFrame(t)
+		assert.Equal(t, first, f.rsv1)
+		first = false
+		received = append(received, f.payload...)
+		if f.fin {
+			break
+		}
+	}
+	fr := flate.NewReader(io.MultiReader(bytes.NewReader(received), bytes.NewReader(webSocketDeflateTail)))
+	decompressed, err := io.ReadAll(fr)
+	assert.NoError(t, err)
+	assert.Equal(t, message, string(decompressed))
+	assert.Less(t, len(received), len(message))
+
+	client.writeMessage(t, wsOpClose, closePayload(WebSocketCloseNormalClosure, ""))
+	client.readClose(t)
+	assert.NoError(t, <-result)
+}
+
+func TestWebSocket_NoCompressionLevel(t *testing.T) {
+	level := flate.NoCompression
+	upgrader := &WebSocketUpgrader{EnableCompression: true, CompressionLevel: &level}
+	_, server, result := newTestWebSocketServer(t, upgrader, func(ws *WebSocket) error {
+		return ws.WriteMessage(WebSocketTextMessage, []byte(strings.Repeat("a", 100)))
+	})
+
+	client := dialTestWebSocket(t, server.URL, http.Header{HeaderSecWebSocketExtensions: {"permessage-deflate"}})
+	f := client.readFrame(t)
+	assert.True(t, f.rsv1)
+	// stored block: 1 byte header, 4 bytes length and uncompressed message
+	assert.Equal(t, []byte{0x00, 100, 0x00, 0x9b, 0xff}, f.payload[:5])
+	assert.Equal(t, strings.Repeat("a", 100), string(f.payload[5:105]))
+
+	client.readClose(t)
+	client.writeMessage(t, wsOpClose, closePayload(WebSocketCloseNormalClosure, ""))
+	assert.NoError(t, <-result)
+}
+
+func TestAcceptDeflateOffer(t *testing.T) {
+	var testCases = []struct {
+		name   string
+		when   string
+		expect bool
+	}{
+		{name: "ok, plain", when: "permessage-deflate", expect: true},
+		{name: "ok, client params", when: "permessage-deflate; client_max_window_bits; client_no_context_takeover", expect: true},
+		{name: "ok, server window 15", when: `permessage-deflate; server_max_window_bits="15"`, expect: true},
+		{name: "ok, second offer", when: "x-webkit-deflate-frame, permessage-deflate", expect: true},
+		{name: "nok, smaller server window", when: "permessage-deflate; server_max_window_bits=9", expect: false},
+		{name: "nok, unknown param", when: "permessage-deflate; foo=1", expect: false},
+		{name: "nok, no offer", when: "", expect: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := http.Header{}
+			if tc.when != "" {
+				h.Set(HeaderSecWebSocketExtensions, tc.when)
+			}
+			assert.Equal(t, tc.expect, acceptDeflateOffer(h))
+		})
+	}
+}
+
+func TestWebSocket_HandlerError(t *testing.T) {
+	_, server, result := newTestWebSocketServer(t, &WebSocketUpgrader{CloseTimeout: 50 * time.Millisecond}, func(ws *WebSocket) error {
+		return errors.New("handler failed")
+	})
+
+	client := dialTestWebSocket(t, server.URL, nil)
+	code, _ := client.readClose(t)
+	assert.Equal(t, WebSocketCloseInternalServerErr, code)
+	assert.EqualError(t, <-result, "handler failed")
+}
+
+func TestWebSocket_PingPong(t *testing.T) {
+	pong := make(chan string, 1)
+	_, server, result := newTestWebSocketServer(t, &WebSocketUpgrader{}, func(ws *WebSocket) error {
+		ws.SetPongHandler(func(data []byte) {
+			pong <- string(data)
+		})
+		if err := ws.Ping([]byte("are you there")); err != nil {
+			return err
+		}
+		_, _, err := ws.ReadMessage()
+		return err
+	})
+
+	client := dialTestWebSocket(t, server.URL, nil)
+	f := client.readFrame(t)
+	assert.Equal(t, testWSFrame{fin: true, opcode: wsOpPing, payload: []byte("are you there")}, f)
+	client.writeMessage(t, wsOpPong, "yes")
+	assert.Equal(t, "yes", <-pong)
+
+	client.writeMessage(t, wsOpClose, closePayload(WebSocketCloseNormalClosure, ""))
+	client.readClose(t)
+	assert.NoError(t, <-result)
+}
+
+func TestWebSocket_Close(t *testing.T) {
+	_, server, result := newTestWebSocketServer(t, &WebSocketUpgrader{}, func(ws *WebSocket) error {
+		if err := ws.Close(4000, "custom"); err != nil {
+			return err
+		}
+		if err := ws.WriteMessage(WebSocketTextMessage, []byte("late")); !errors.Is(err, ErrWebSocketClosed) {
+			return err
+		}
+		_, _, err := ws.ReadMessage()
+		return err
+	})
+
+	client := dialTestWebSocket(t, server.URL, nil)
+	code, reason := client.readClose(t)
+	assert.Equal(t, 4000, code)
+	assert.Equal(t, "custom", reason)
+	client.writeMessage(t, wsOpClose, closePayload(4000, ""))
+
+	assert.NoError(t, <-result)
+}
+
+func TestEcho_ShutdownClosesWebSockets(t *testing.T) {
+	started := make(chan struct{})
+	e, server, result := newTestWebSocketServer(t, &WebSocketUpgrader{}, func(ws *WebSocket) error {
+		close(started)
+		_, _, err := ws.ReadMessage()
+		return err
+	})
+
+	client := dialTestWebSocket(t, server.URL, nil)
+	<-started
+
+	ctx, cancel := stdContext.WithTimeout(stdContext.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, e.Shutdown(ctx))
+
+	code, reason := client.readClose(t)
+	assert.Equal(t, WebSocketCloseGoingAway, code)
+	assert.Equal(t, "server shutting down", reason)
+	client.writeMessage(t, wsOpClose, closePayload(WebSocketCloseGoingAway, ""))
+	assert.NoError(t, <-result)
+
+	// new connections are refused during shutdown
+	client = dialTestWebSocket(t, server.URL, nil)
+	assert.Equal(t, http.StatusServiceUnavailable, client.res.StatusCode)
+}
+
+func TestEcho_RestartAcceptsWebSockets(t *testing.T) {
+	e := New()
+	e.GET("/ws", func(c Context) error {
+		return c.WebSocket(func(ws *WebSocket) error { return nil })
+	})
+
+	for i := 0; i < 2; i++ {
+		e.Listener = nil
+		server := &http.Server{Addr: "127.0.0.1:0"}
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- e.StartServer(server)
+		}()
+		require.NoError(t, waitForServerStart(e, errCh, false))
+
+		client := dialTestWebSocket(t, "http://"+e.ListenerAddr().String(), nil)
+		assert.Equal(t, http.StatusSwitchingProtocols, client.res.StatusCode)
+		client.conn.Close()
+
+		ctx, cancel := stdContext.WithTimeout(stdContext.Background(), time.Second)
+		assert.NoError(t, e.Shutdown(ctx))
+		assert.NoError(t, server.Shutdown(ctx))
+		cancel()
+		assert.Equal(t, http.ErrServerClosed, <-errCh)
+	}
+}